@@ -0,0 +1,184 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+// Command policy-sign signs a policy release for sync. It builds the signing
+// payload from the definition file, the artifact at the sync's downloadUrl and
+// the metadata sent in the sync request, and prints a base64 signature made
+// with an Ed25519 or ECDSA P-256 private key (PKCS#8 PEM). With -payload it
+// prints the payload instead, for signing with external tools such as
+// "cosign sign-blob". With -bundle the release is the bundle directory, whose
+// definition, metadata and files are signed in place of an artifact.
+//
+// Usage:
+//
+//	go run ./cmd/policy-sign -name rate-limiting -version 1.1.0 \
+//	    -definition policy-definition.yml -artifact rate-limiting-1.1.0.zip \
+//	    -metadata metadata.json -key signing-key.pem
+//
+//	go run ./cmd/policy-sign -name rate-limiting -version 1.1.0 \
+//	    -bundle rate-limiting-1.1.0/ -key signing-key.pem
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/wso2/policyhub/internal/policy"
+	"github.com/wso2/policyhub/internal/sync"
+)
+
+func main() {
+	var (
+		name           = flag.String("name", "", "policy name")
+		version        = flag.String("version", "", "policy version")
+		definitionPath = flag.String("definition", "", "policy definition YAML file (default: the bundle's)")
+		artifactPath   = flag.String("artifact", "", "artifact file served at the sync's downloadUrl")
+		bundleDir      = flag.String("bundle", "", "bundle directory, instead of -artifact")
+		metadataPath   = flag.String("metadata", "", "JSON file with the sync request's metadata object (default: the bundle's)")
+		keyPath        = flag.String("key", "", "PKCS#8 PEM private key (Ed25519 or ECDSA P-256)")
+		payloadOnly    = flag.Bool("payload", false, "print the signing payload instead of a signature")
+	)
+	flag.Parse()
+
+	if *bundleDir != "" {
+		if *definitionPath == "" {
+			*definitionPath = filepath.Join(*bundleDir, "policy-definition.yml")
+		}
+		if *metadataPath == "" {
+			*metadataPath = filepath.Join(*bundleDir, "metadata.json")
+		}
+	}
+	if *name == "" || *version == "" || *definitionPath == "" || *metadataPath == "" ||
+		(*artifactPath == "") == (*bundleDir == "") || (*keyPath == "" && !*payloadOnly) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var artifactDigest string
+	var err error
+	if *bundleDir != "" {
+		artifactDigest, err = bundleDigest(*bundleDir)
+	} else {
+		artifactDigest, err = fileDigest(*artifactPath)
+	}
+	if err != nil {
+		log.Fatalf("policy-sign: %v", err)
+	}
+
+	payload, err := buildPayload(*name, *version, *definitionPath, artifactDigest, *metadataPath)
+	if err != nil {
+		log.Fatalf("policy-sign: %v", err)
+	}
+
+	if *payloadOnly {
+		os.Stdout.Write(payload)
+		return
+	}
+
+	signature, err := sign(*keyPath, payload)
+	if err != nil {
+		log.Fatalf("policy-sign: %v", err)
+	}
+	fmt.Println(base64.StdEncoding.EncodeToString(signature))
+}
+
+// buildPayload builds the payload exactly as the sync service does
+func buildPayload(name, version, definitionPath, artifactDigest, metadataPath string) ([]byte, error) {
+	definition, err := os.ReadFile(definitionPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read definition: %w", err)
+	}
+
+	data, err := os.ReadFile(metadataPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata: %w", err)
+	}
+	var metadata policy.PolicyMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse metadata: %w", err)
+	}
+
+	return sync.ReleasePayload(name, version, definition, artifactDigest, &metadata)
+}
+
+// fileDigest returns the SHA-256 digest of an artifact file
+func fileDigest(artifactPath string) (string, error) {
+	file, err := os.Open(artifactPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read artifact: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to read artifact: %w", err)
+	}
+	return policy.FormatDigest(hash.Sum(nil)), nil
+}
+
+// bundleDigest returns the content digest of a bundle directory, as the sync
+// service computes it from the uploaded archive
+func bundleDigest(dir string) (string, error) {
+	files := make(map[string][]byte)
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)], err = os.ReadFile(path)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read bundle: %w", err)
+	}
+	return sync.BundleContentDigest(files), nil
+}
+
+// sign signs the payload with an Ed25519 key, or with an ECDSA key over its SHA-256 digest
+func sign(keyPath string, payload []byte) ([]byte, error) {
+	data, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("key must be a PKCS#8 PEM encoded PRIVATE KEY")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+
+	switch privateKey := key.(type) {
+	case ed25519.PrivateKey:
+		return privateKey.Sign(rand.Reader, payload, crypto.Hash(0))
+	case *ecdsa.PrivateKey:
+		sum := sha256.Sum256(payload)
+		return ecdsa.SignASN1(rand.Reader, privateKey, sum[:])
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+}
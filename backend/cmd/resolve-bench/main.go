@@ -0,0 +1,354 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+// Command resolve-bench compares the per-item and set-based resolve queries
+// against a local Postgres database. It seeds synthetic policies under a
+// dedicated name prefix, times both approaches for each retrieval strategy,
+// checks that they return the same versions and removes the seeded rows.
+//
+// Usage:
+//
+//	go run ./cmd/resolve-bench -policies 500 -batch 100 -iterations 50
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/wso2/policyhub/internal/config"
+	"github.com/wso2/policyhub/internal/db"
+	"github.com/wso2/policyhub/internal/logging"
+	"github.com/wso2/policyhub/internal/policy"
+	"github.com/wso2/policyhub/internal/semver"
+)
+
+const policyPrefix = "bench-resolve-"
+
+type options struct {
+	policies   int
+	majors     int
+	minors     int
+	patches    int
+	batch      int
+	iterations int
+	keep       bool
+}
+
+// strategyBench runs one strategy both ways for a batch of policy names
+type strategyBench struct {
+	name    string
+	perItem func(ctx context.Context, names []string) ([]*policy.PolicyVersion, error)
+	bulk    func(ctx context.Context, names []string) ([]*policy.PolicyVersion, error)
+}
+
+func main() {
+	var opts options
+	flag.IntVar(&opts.policies, "policies", 500, "number of synthetic policies to seed")
+	flag.IntVar(&opts.majors, "majors", 3, "major versions per policy")
+	flag.IntVar(&opts.minors, "minors", 5, "minor versions per major")
+	flag.IntVar(&opts.patches, "patches", 5, "patch versions per minor")
+	flag.IntVar(&opts.batch, "batch", policy.MaxBatchSize, "resolve requests per batch")
+	flag.IntVar(&opts.iterations, "iterations", 50, "batches timed per strategy and approach")
+	flag.BoolVar(&opts.keep, "keep", false, "keep the seeded rows after the run")
+	flag.Parse()
+
+	if err := run(opts); err != nil {
+		log.Fatalf("resolve-bench: %v", err)
+	}
+}
+
+func run(opts options) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	logger, err := logging.NewLogger("warn", "console")
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+
+	database, err := db.NewDB(&cfg.Database, logger)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	ctx := context.Background()
+
+	migrator, err := db.NewMigrator(database.Pool, logger)
+	if err != nil {
+		return err
+	}
+	if err := migrator.Up(ctx); err != nil {
+		return err
+	}
+
+	if err := seed(ctx, database, opts); err != nil {
+		return err
+	}
+	if !opts.keep {
+		defer func() {
+			if err := cleanup(context.Background(), database); err != nil {
+				fmt.Fprintf(os.Stderr, "cleanup failed: %v\n", err)
+			}
+		}()
+	}
+
+	repo := policy.NewSQLCRepository(database)
+	rng := rand.New(rand.NewSource(1))
+
+	// Every request targets a major/minor that exists, so both approaches must agree on every item
+	major := func() int32 { return int32(rng.Intn(opts.majors)) }
+	minor := func() int32 { return int32(rng.Intn(opts.minors)) }
+
+	benches := []strategyBench{
+		{
+			name: "exact",
+			perItem: func(ctx context.Context, names []string) ([]*policy.PolicyVersion, error) {
+				versions := exactVersions(names, rng, opts)
+				results := make([]*policy.PolicyVersion, len(names))
+				for i, name := range names {
+					pv, err := repo.GetPolicyVersionByExact(ctx, name, versions[i])
+					if err != nil {
+						return nil, err
+					}
+					results[i] = pv
+				}
+				return results, nil
+			},
+			bulk: func(ctx context.Context, names []string) ([]*policy.PolicyVersion, error) {
+				versions := exactVersions(names, rng, opts)
+				return bulkResolve(ctx, repo, names, func(i int) string { return "=" + versions[i] })
+			},
+		},
+		{
+			name: "latest_patch",
+			perItem: func(ctx context.Context, names []string) ([]*policy.PolicyVersion, error) {
+				results := make([]*policy.PolicyVersion, len(names))
+				for i, name := range names {
+					pv, err := repo.GetPolicyVersionByLatestPatch(ctx, name, major(), minor())
+					if err != nil {
+						return nil, err
+					}
+					results[i] = pv
+				}
+				return results, nil
+			},
+			bulk: func(ctx context.Context, names []string) ([]*policy.PolicyVersion, error) {
+				return bulkResolve(ctx, repo, names, func(int) string { return fmt.Sprintf("~%d.%d", major(), minor()) })
+			},
+		},
+		{
+			name: "latest_minor",
+			perItem: func(ctx context.Context, names []string) ([]*policy.PolicyVersion, error) {
+				results := make([]*policy.PolicyVersion, len(names))
+				for i, name := range names {
+					pv, err := repo.GetPolicyVersionByLatestMinor(ctx, name, major())
+					if err != nil {
+						return nil, err
+					}
+					results[i] = pv
+				}
+				return results, nil
+			},
+			bulk: func(ctx context.Context, names []string) ([]*policy.PolicyVersion, error) {
+				return bulkResolve(ctx, repo, names, func(int) string { return fmt.Sprintf("%d.x", major()) })
+			},
+		},
+		{
+			name: "latest_major",
+			perItem: func(ctx context.Context, names []string) ([]*policy.PolicyVersion, error) {
+				results := make([]*policy.PolicyVersion, len(names))
+				for i, name := range names {
+					pv, err := repo.GetPolicyVersionByLatestMajor(ctx, name)
+					if err != nil {
+						return nil, err
+					}
+					results[i] = pv
+				}
+				return results, nil
+			},
+			bulk: func(ctx context.Context, names []string) ([]*policy.PolicyVersion, error) {
+				return bulkResolve(ctx, repo, names, func(int) string { return "*" })
+			},
+		},
+	}
+
+	fmt.Printf("seeded %d policies x %d versions, batch size %d, %d iterations\n\n",
+		opts.policies, opts.majors*opts.minors*opts.patches, opts.batch, opts.iterations)
+	fmt.Printf("%-14s %-9s %10s %10s %10s\n", "strategy", "approach", "mean", "p50", "p95")
+
+	for _, bench := range benches {
+		perItem, bulk, err := measure(ctx, bench, rng, opts)
+		if err != nil {
+			return fmt.Errorf("%s: %w", bench.name, err)
+		}
+		printStats(bench.name, "per-item", perItem)
+		printStats(bench.name, "bulk", bulk)
+		fmt.Printf("%-14s speedup %.1fx\n\n", bench.name, float64(mean(perItem))/float64(mean(bulk)))
+	}
+
+	return nil
+}
+
+// measure times both approaches on the same batches. The RNG is re-seeded per
+// batch so both approaches see identical requests, and their results are compared.
+func measure(ctx context.Context, bench strategyBench, rng *rand.Rand, opts options) ([]time.Duration, []time.Duration, error) {
+	perItem := make([]time.Duration, 0, opts.iterations)
+	bulk := make([]time.Duration, 0, opts.iterations)
+
+	for i := 0; i < opts.iterations; i++ {
+		batchSeed := int64(i + 1)
+
+		rng.Seed(batchSeed)
+		names := batchNames(rng, opts)
+		start := time.Now()
+		expected, err := bench.perItem(ctx, names)
+		if err != nil {
+			return nil, nil, err
+		}
+		perItem = append(perItem, time.Since(start))
+
+		rng.Seed(batchSeed)
+		names = batchNames(rng, opts)
+		start = time.Now()
+		actual, err := bench.bulk(ctx, names)
+		if err != nil {
+			return nil, nil, err
+		}
+		bulk = append(bulk, time.Since(start))
+
+		if err := compare(expected, actual); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return perItem, bulk, nil
+}
+
+// batchNames picks the policy names of one batch
+func batchNames(rng *rand.Rand, opts options) []string {
+	names := make([]string, opts.batch)
+	for i := range names {
+		names[i] = fmt.Sprintf("%s%d", policyPrefix, rng.Intn(opts.policies)+1)
+	}
+	return names
+}
+
+// exactVersions picks an existing version for every name
+func exactVersions(names []string, rng *rand.Rand, opts options) []string {
+	versions := make([]string, len(names))
+	for i := range versions {
+		versions[i] = fmt.Sprintf("%d.%d.%d", rng.Intn(opts.majors), rng.Intn(opts.minors), rng.Intn(opts.patches))
+	}
+	return versions
+}
+
+// bulkResolve resolves every name against the constraint the strategy compiles
+// to, the same way the resolve endpoint does
+func bulkResolve(ctx context.Context, repo policy.Repository, names []string, constraint func(i int) string) ([]*policy.PolicyVersion, error) {
+	requests := make([]policy.VersionRangeRequest, len(names))
+	for i, name := range names {
+		c, err := semver.ParseConstraint(constraint(i))
+		if err != nil {
+			return nil, err
+		}
+		_, pinned := c.Exact()
+		requests[i] = policy.VersionRangeRequest{Name: name, Ranges: c.Ranges(), AllowYanked: pinned}
+	}
+	return repo.BulkGetPolicyVersionsByRanges(ctx, requests)
+}
+
+// compare checks that both approaches resolved the same version for every request
+func compare(expected, actual []*policy.PolicyVersion) error {
+	if len(expected) != len(actual) {
+		return fmt.Errorf("result count mismatch: per-item %d, bulk %d", len(expected), len(actual))
+	}
+	for i := range expected {
+		if actual[i] == nil {
+			return fmt.Errorf("request %d: bulk returned no version, per-item returned %s", i, expected[i].Version)
+		}
+		if expected[i].PolicyName != actual[i].PolicyName || expected[i].Version != actual[i].Version {
+			return fmt.Errorf("request %d: per-item %s@%s, bulk %s@%s", i,
+				expected[i].PolicyName, expected[i].Version, actual[i].PolicyName, actual[i].Version)
+		}
+	}
+	return nil
+}
+
+// seed inserts the synthetic policies in a single statement
+func seed(ctx context.Context, database *db.DB, opts options) error {
+	if err := cleanup(ctx, database); err != nil {
+		return err
+	}
+
+	_, err := database.Exec(ctx, `
+		INSERT INTO policy_version (policy_name, version, is_latest, display_name, provider, definition_yaml)
+		SELECT $1::text || p,
+		       ma || '.' || mi || '.' || pa,
+		       ma = $3 - 1 AND mi = $4 - 1 AND pa = $5 - 1,
+		       'Benchmark policy ' || p,
+		       'bench',
+		       'name: bench'
+		FROM generate_series(1, $2::int) AS p,
+		     generate_series(0, $3::int - 1) AS ma,
+		     generate_series(0, $4::int - 1) AS mi,
+		     generate_series(0, $5::int - 1) AS pa`,
+		policyPrefix, opts.policies, opts.majors, opts.minors, opts.patches)
+	if err != nil {
+		return fmt.Errorf("failed to seed benchmark data: %w", err)
+	}
+
+	_, err = database.Exec(ctx, "ANALYZE policy_version")
+	return err
+}
+
+// cleanup removes all rows created by the benchmark
+func cleanup(ctx context.Context, database *db.DB) error {
+	_, err := database.Exec(ctx, "DELETE FROM policy_version WHERE policy_name LIKE $1", policyPrefix+"%")
+	if err != nil {
+		return fmt.Errorf("failed to remove benchmark data: %w", err)
+	}
+	return nil
+}
+
+func printStats(strategy, approach string, durations []time.Duration) {
+	fmt.Printf("%-14s %-9s %10s %10s %10s\n", strategy, approach,
+		mean(durations).Round(time.Microsecond),
+		percentile(durations, 50).Round(time.Microsecond),
+		percentile(durations, 95).Round(time.Microsecond))
+}
+
+func mean(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	return total / time.Duration(len(durations))
+}
+
+func percentile(durations []time.Duration, p int) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted[(len(sorted)-1)*p/100]
+}
@@ -5,11 +5,11 @@ go 1.24.0
 require (
 	github.com/gin-contrib/cors v1.7.6
 	github.com/gin-gonic/gin v1.10.1
+	github.com/golang-jwt/jwt/v5 v5.3.1
 	github.com/google/uuid v1.5.0
 	github.com/jackc/pgx/v5 v5.7.2
 	github.com/joho/godotenv v1.5.1
 	go.uber.org/zap v1.26.0
-	golang.org/x/mod v0.31.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
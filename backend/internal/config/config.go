@@ -14,6 +14,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -24,6 +25,10 @@ type Config struct {
 	Database DatabaseConfig
 	CORS     CORSConfig
 	Logging  LoggingConfig
+	Auth     AuthConfig
+	Signing  SigningConfig
+	Sync     SyncConfig
+	Storage  StorageConfig
 }
 
 // ServerConfig holds server-related configuration
@@ -31,6 +36,10 @@ type ServerConfig struct {
 	Host    string
 	Port    int
 	GinMode string
+	// PublicURL is where clients reach the server, used for URLs of files it serves
+	PublicURL string
+	// MaxBodySize bounds JSON request bodies in bytes; bundle uploads have their own limit
+	MaxBodySize int64
 }
 
 // DatabaseConfig holds database-related configuration
@@ -43,6 +52,8 @@ type DatabaseConfig struct {
 	SSLMode  string
 	MaxConns int
 	MinConns int
+	// AutoMigrate applies pending schema migrations on server startup
+	AutoMigrate bool
 }
 
 // CORSConfig holds CORS-related configuration
@@ -50,7 +61,125 @@ type CORSConfig struct {
 	AllowOrigins []string
 }
 
-// LoggingConfig holds logging-related configuration
+// AuthConfig holds authentication settings for the internal API
+type AuthConfig struct {
+	Enabled bool
+	// APIKeysFile is a JSON file of SHA-256 hashed API keys and their scopes
+	APIKeysFile string
+	// JWKSFile is a local JWKS document used to verify JWT bearer tokens
+	JWKSFile    string
+	JWTIssuer   string
+	JWTAudience string
+}
+
+// SigningConfig holds release signature verification settings for syncs
+type SigningConfig struct {
+	// KeysFile is a JSON file of provider public keys used to verify release signatures
+	KeysFile string
+	// UnsignedPolicy decides what happens to unsigned syncs: allow, warn or reject
+	UnsignedPolicy string
+}
+
+// SyncConfig holds settings for the workers that process sync jobs
+type SyncConfig struct {
+	Workers     int
+	MaxAttempts int
+	// JobTimeout bounds a single attempt; the job's lease is slightly longer
+	JobTimeout   time.Duration
+	PollInterval time.Duration
+	// JobRetention is how long succeeded and failed jobs are kept once finished
+	JobRetention time.Duration
+	// Failed attempts are retried after RetryBaseDelay, doubling up to RetryMaxDelay
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	Source         SyncSourceConfig
+	Bundle         SyncBundleConfig
+	Fetch          SyncFetchConfig
+	Docs           SyncDocsConfig
+}
+
+// SyncSourceConfig holds settings for pulling policies from a policy repository
+type SyncSourceConfig struct {
+	// Type is "directory" for a local checkout or "git" for a bare repository
+	Type string
+	// Path locates the repository; empty disables pull-based sync
+	Path string
+	// Ref is the branch, tag or commit scanned in a git repository
+	Ref string
+	// ScanInterval is the time between scheduled scans; zero scans on demand only
+	ScanInterval time.Duration
+	// DownloadURL is the artifact URL of a version, with {policy} and {version} placeholders
+	DownloadURL string
+	// PublicURL is where the repository's files are served, used for asset URLs
+	PublicURL string
+}
+
+// SyncBundleConfig holds limits for policy bundles uploaded to the internal API
+type SyncBundleConfig struct {
+	// MaxSize bounds the uploaded archive in bytes
+	MaxSize int64
+	// MaxUnpackedSize bounds the total size of the unpacked files in bytes
+	MaxUnpackedSize int64
+	// MaxEntries bounds the number of entries in the archive, directories included
+	MaxEntries int
+	// Retention is how long a bundle no version was published from is kept
+	Retention time.Duration
+}
+
+// SyncFetchConfig restricts the remote files syncs fetch
+type SyncFetchConfig struct {
+	// AllowedHosts lists the hosts files may be fetched from, exactly or as
+	// "*.example.com" for subdomains; empty allows any host
+	AllowedHosts []string
+	// AllowPrivateNetworks allows loopback, private and link-local addresses
+	AllowPrivateNetworks bool
+	// Size limits in bytes, enforced while the response is read
+	MaxDefinitionSize int64
+	MaxDocSize        int64
+	MaxAssetSize      int64 // logos, banners and doc images
+	MaxArtifactSize   int64
+	// MaxRedirects bounds the redirects followed per fetch
+	MaxRedirects int
+}
+
+// SyncDocsConfig holds settings for fetching the doc pages of a sync
+type SyncDocsConfig struct {
+	// Concurrency bounds the pages of a sync fetched at once
+	Concurrency int
+	// Failed fetches of a page are retried Retries times, after RetryDelay,
+	// doubling; only network errors and 429 and 5xx responses are retried
+	Retries    int
+	RetryDelay time.Duration
+	// CacheRetention is how long a page kept for conditional requests is
+	// kept after a sync last used it
+	CacheRetention time.Duration
+}
+
+// StorageConfig holds settings for the blob store that synced artifacts and images are mirrored into
+type StorageConfig struct {
+	// Backend is "filesystem" or "s3"; empty disables mirroring
+	Backend string
+	// Path is the root directory of the filesystem backend
+	Path string
+	// MaxObjectSize bounds a single mirrored file in bytes
+	MaxObjectSize int64
+	S3            S3Config
+}
+
+// S3Config holds settings for an S3-compatible blob store
+type S3Config struct {
+	// Endpoint is the base URL of the service, e.g. https://s3.us-east-1.amazonaws.com
+	Endpoint  string
+	Bucket    string
+	Region    string
+	AccessKey string
+	SecretKey string
+	// PathStyle addresses objects as <endpoint>/<bucket>/<key>, as MinIO expects,
+	// instead of <bucket>.<endpoint host>/<key>
+	PathStyle bool
+}
+
+// DSN returns the PostgreSQL connection string
 func (d *DatabaseConfig) DSN() string {
 	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		d.Host, d.Port, d.User, d.Password, d.Name, d.SSLMode)
@@ -69,19 +198,21 @@ func Load() (*Config, error) {
 
 	cfg := &Config{
 		Server: ServerConfig{
-			Host:    getEnv("SERVER_HOST", "0.0.0.0"),
-			Port:    getEnvAsInt("SERVER_PORT", 8080),
-			GinMode: getEnv("GIN_MODE", "release"),
+			Host:        getEnv("SERVER_HOST", "0.0.0.0"),
+			Port:        getEnvAsInt("SERVER_PORT", 8080),
+			GinMode:     getEnv("GIN_MODE", "release"),
+			MaxBodySize: getEnvAsInt64("SERVER_MAX_BODY_SIZE", 1<<20),
 		},
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnvAsInt("DB_PORT", 5432),
-			User:     getEnv("DB_USER", "policyhub"),
-			Password: getEnv("DB_PASSWORD", "policyhub"),
-			Name:     getEnv("DB_NAME", "policyhub"),
-			SSLMode:  getEnv("DB_SSLMODE", "disable"),
-			MaxConns: getEnvAsInt("DB_MAX_CONNS", 25),
-			MinConns: getEnvAsInt("DB_MIN_CONNS", 5),
+			Host:        getEnv("DB_HOST", "localhost"),
+			Port:        getEnvAsInt("DB_PORT", 5432),
+			User:        getEnv("DB_USER", "policyhub"),
+			Password:    getEnv("DB_PASSWORD", "policyhub"),
+			Name:        getEnv("DB_NAME", "policyhub"),
+			SSLMode:     getEnv("DB_SSLMODE", "disable"),
+			MaxConns:    getEnvAsInt("DB_MAX_CONNS", 25),
+			MinConns:    getEnvAsInt("DB_MIN_CONNS", 5),
+			AutoMigrate: getEnvAsBool("DB_AUTO_MIGRATE", true),
 		},
 		CORS: CORSConfig{
 			AllowOrigins: parseAllowOrigins(getEnv("CORS_ALLOWED_ORIGINS", "*")),
@@ -90,8 +221,73 @@ func Load() (*Config, error) {
 			Level:  getEnv("LOG_LEVEL", "debug"),
 			Format: getEnv("LOG_FORMAT", "json"),
 		},
+		Auth: AuthConfig{
+			Enabled:     getEnvAsBool("AUTH_ENABLED", true),
+			APIKeysFile: getEnv("AUTH_API_KEYS_FILE", ""),
+			JWKSFile:    getEnv("AUTH_JWKS_FILE", ""),
+			JWTIssuer:   getEnv("AUTH_JWT_ISSUER", ""),
+			JWTAudience: getEnv("AUTH_JWT_AUDIENCE", ""),
+		},
+		Signing: SigningConfig{
+			KeysFile:       getEnv("SIGNING_KEYS_FILE", ""),
+			UnsignedPolicy: getEnv("SIGNING_UNSIGNED_POLICY", "warn"),
+		},
+		Sync: SyncConfig{
+			Workers:        getEnvAsInt("SYNC_WORKERS", 4),
+			MaxAttempts:    getEnvAsInt("SYNC_MAX_ATTEMPTS", 5),
+			JobTimeout:     getEnvAsDuration("SYNC_JOB_TIMEOUT", 10*time.Minute),
+			PollInterval:   getEnvAsDuration("SYNC_POLL_INTERVAL", 2*time.Second),
+			JobRetention:   getEnvAsDuration("SYNC_JOB_RETENTION", 30*24*time.Hour),
+			RetryBaseDelay: getEnvAsDuration("SYNC_RETRY_BASE_DELAY", 30*time.Second),
+			RetryMaxDelay:  getEnvAsDuration("SYNC_RETRY_MAX_DELAY", 15*time.Minute),
+			Source: SyncSourceConfig{
+				Type:         getEnv("SYNC_SOURCE_TYPE", "directory"),
+				Path:         getEnv("SYNC_SOURCE_PATH", ""),
+				Ref:          getEnv("SYNC_SOURCE_REF", "HEAD"),
+				ScanInterval: getEnvAsDuration("SYNC_SOURCE_SCAN_INTERVAL", 5*time.Minute),
+				DownloadURL:  getEnv("SYNC_SOURCE_DOWNLOAD_URL", ""),
+				PublicURL:    getEnv("SYNC_SOURCE_PUBLIC_URL", ""),
+			},
+			Bundle: SyncBundleConfig{
+				MaxSize:         getEnvAsInt64("SYNC_BUNDLE_MAX_SIZE", 20<<20),
+				MaxUnpackedSize: getEnvAsInt64("SYNC_BUNDLE_MAX_UNPACKED_SIZE", 50<<20),
+				MaxEntries:      getEnvAsInt("SYNC_BUNDLE_MAX_ENTRIES", 500),
+				Retention:       getEnvAsDuration("SYNC_BUNDLE_RETENTION", 24*time.Hour),
+			},
+			Fetch: SyncFetchConfig{
+				AllowedHosts:         parseHosts(getEnv("SYNC_FETCH_ALLOWED_HOSTS", "")),
+				AllowPrivateNetworks: getEnvAsBool("SYNC_FETCH_ALLOW_PRIVATE_NETWORKS", false),
+				MaxDefinitionSize:    getEnvAsInt64("SYNC_FETCH_MAX_DEFINITION_SIZE", 1<<20),
+				MaxDocSize:           getEnvAsInt64("SYNC_FETCH_MAX_DOC_SIZE", 1<<20),
+				MaxAssetSize:         getEnvAsInt64("SYNC_FETCH_MAX_ASSET_SIZE", 5<<20),
+				MaxArtifactSize:      getEnvAsInt64("SYNC_FETCH_MAX_ARTIFACT_SIZE", 100<<20),
+				MaxRedirects:         getEnvAsInt("SYNC_FETCH_MAX_REDIRECTS", 5),
+			},
+			Docs: SyncDocsConfig{
+				Concurrency:    getEnvAsInt("SYNC_DOCS_CONCURRENCY", 4),
+				Retries:        getEnvAsInt("SYNC_DOCS_RETRIES", 2),
+				RetryDelay:     getEnvAsDuration("SYNC_DOCS_RETRY_DELAY", time.Second),
+				CacheRetention: getEnvAsDuration("SYNC_DOCS_CACHE_RETENTION", 30*24*time.Hour),
+			},
+		},
+		Storage: StorageConfig{
+			Backend:       getEnv("STORAGE_BACKEND", ""),
+			Path:          getEnv("STORAGE_PATH", "./data/blobs"),
+			MaxObjectSize: getEnvAsInt64("STORAGE_MAX_OBJECT_SIZE", 100<<20),
+			S3: S3Config{
+				Endpoint:  getEnv("STORAGE_S3_ENDPOINT", ""),
+				Bucket:    getEnv("STORAGE_S3_BUCKET", ""),
+				Region:    getEnv("STORAGE_S3_REGION", "us-east-1"),
+				AccessKey: getEnv("STORAGE_S3_ACCESS_KEY", ""),
+				SecretKey: getEnv("STORAGE_S3_SECRET_KEY", ""),
+				PathStyle: getEnvAsBool("STORAGE_S3_PATH_STYLE", true),
+			},
+		},
 	}
 
+	// Files served by the server are addressed through its public URL
+	cfg.Server.PublicURL = strings.TrimSuffix(getEnv("SERVER_PUBLIC_URL", fmt.Sprintf("http://localhost:%d", cfg.Server.Port)), "/")
+
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("configuration validation failed: %w", err)
@@ -112,6 +308,13 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid gin mode: %s (must be debug, release, or test)", c.Server.GinMode)
 	}
 
+	if !strings.HasPrefix(c.Server.PublicURL, "http://") && !strings.HasPrefix(c.Server.PublicURL, "https://") {
+		return fmt.Errorf("invalid server public URL: %q (must be an http or https URL)", c.Server.PublicURL)
+	}
+	if c.Server.MaxBodySize < 1 {
+		return fmt.Errorf("invalid server max body size: %d (must be positive)", c.Server.MaxBodySize)
+	}
+
 	// Validate database configuration
 	if c.Database.Host == "" {
 		return fmt.Errorf("database host is required")
@@ -150,6 +353,99 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid log format: %s (must be json or console)", c.Logging.Format)
 	}
 
+	// Validate signing configuration
+	validUnsignedPolicies := map[string]bool{"allow": true, "warn": true, "reject": true}
+	if !validUnsignedPolicies[c.Signing.UnsignedPolicy] {
+		return fmt.Errorf("invalid unsigned sync policy: %s (must be allow, warn, or reject)", c.Signing.UnsignedPolicy)
+	}
+
+	// Validate sync configuration
+	if c.Sync.Workers < 1 {
+		return fmt.Errorf("invalid sync workers: %d (must be at least 1)", c.Sync.Workers)
+	}
+	if c.Sync.MaxAttempts < 1 {
+		return fmt.Errorf("invalid sync max attempts: %d (must be at least 1)", c.Sync.MaxAttempts)
+	}
+	if c.Sync.JobTimeout < time.Second || c.Sync.PollInterval <= 0 || c.Sync.RetryBaseDelay < time.Second {
+		return fmt.Errorf("sync job timeout and retry delay must be at least 1s and the poll interval positive")
+	}
+	if c.Sync.RetryMaxDelay < c.Sync.RetryBaseDelay {
+		return fmt.Errorf("sync retry max delay (%s) cannot be less than the base delay (%s)", c.Sync.RetryMaxDelay, c.Sync.RetryBaseDelay)
+	}
+	if c.Sync.JobRetention < time.Hour {
+		return fmt.Errorf("invalid sync job retention: %s (must be at least 1h)", c.Sync.JobRetention)
+	}
+
+	// Validate sync source configuration
+	if source := c.Sync.Source; source.Path != "" {
+		if source.Type != "directory" && source.Type != "git" {
+			return fmt.Errorf("invalid sync source type: %s (must be directory or git)", source.Type)
+		}
+		if source.Ref == "" || strings.HasPrefix(source.Ref, "-") {
+			return fmt.Errorf("invalid sync source ref: %q", source.Ref)
+		}
+		if source.ScanInterval < 0 {
+			return fmt.Errorf("invalid sync source scan interval: %s (must not be negative)", source.ScanInterval)
+		}
+		if source.DownloadURL == "" {
+			return fmt.Errorf("SYNC_SOURCE_DOWNLOAD_URL is required when SYNC_SOURCE_PATH is set")
+		}
+	}
+
+	// Validate sync bundle configuration
+	if bundle := c.Sync.Bundle; bundle.MaxSize < 1 || bundle.MaxUnpackedSize < 1 || bundle.MaxEntries < 1 {
+		return fmt.Errorf("sync bundle size and entry limits must be positive")
+	}
+	if c.Sync.Bundle.Retention < c.Sync.JobTimeout {
+		return fmt.Errorf("sync bundle retention (%s) cannot be less than the job timeout (%s)", c.Sync.Bundle.Retention, c.Sync.JobTimeout)
+	}
+
+	// Validate sync fetch limits
+	if fetch := c.Sync.Fetch; fetch.MaxDefinitionSize < 1 || fetch.MaxDocSize < 1 || fetch.MaxAssetSize < 1 || fetch.MaxArtifactSize < 1 {
+		return fmt.Errorf("sync fetch size limits must be positive")
+	}
+	if c.Sync.Fetch.MaxRedirects < 0 {
+		return fmt.Errorf("invalid sync fetch max redirects: %d (cannot be negative)", c.Sync.Fetch.MaxRedirects)
+	}
+	for _, host := range c.Sync.Fetch.AllowedHosts {
+		if pattern := strings.TrimPrefix(host, "*."); pattern == "" || strings.ContainsAny(pattern, "*/:") {
+			return fmt.Errorf("invalid sync fetch allowed host: %q (must be a host name or *.domain)", host)
+		}
+	}
+
+	// Validate doc fetching
+	if c.Sync.Docs.Concurrency < 1 {
+		return fmt.Errorf("invalid sync docs concurrency: %d (must be at least 1)", c.Sync.Docs.Concurrency)
+	}
+	if c.Sync.Docs.Retries < 0 || c.Sync.Docs.RetryDelay < 0 {
+		return fmt.Errorf("sync docs retries and retry delay cannot be negative")
+	}
+	if c.Sync.Docs.CacheRetention < time.Hour {
+		return fmt.Errorf("invalid sync docs cache retention: %s (must be at least 1h)", c.Sync.Docs.CacheRetention)
+	}
+
+	// Validate storage configuration
+	switch c.Storage.Backend {
+	case "":
+	case "filesystem":
+		if c.Storage.Path == "" {
+			return fmt.Errorf("STORAGE_PATH is required for the filesystem storage backend")
+		}
+	case "s3":
+		s3 := c.Storage.S3
+		if !strings.HasPrefix(s3.Endpoint, "http://") && !strings.HasPrefix(s3.Endpoint, "https://") {
+			return fmt.Errorf("invalid S3 storage endpoint: %q (must be an http or https URL)", s3.Endpoint)
+		}
+		if s3.Bucket == "" || s3.Region == "" || s3.AccessKey == "" || s3.SecretKey == "" {
+			return fmt.Errorf("the s3 storage backend requires a bucket, region, access key and secret key")
+		}
+	default:
+		return fmt.Errorf("invalid storage backend: %s (must be filesystem or s3)", c.Storage.Backend)
+	}
+	if c.Storage.MaxObjectSize < 1 {
+		return fmt.Errorf("invalid storage max object size: %d (must be positive)", c.Storage.MaxObjectSize)
+	}
+
 	return nil
 }
 
@@ -175,6 +471,56 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return value
 }
 
+// getEnvAsInt64 gets an environment variable as a 64-bit integer or returns a default value
+func getEnvAsInt64(key string, defaultValue int64) int64 {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseInt(valueStr, 10, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvAsBool gets an environment variable as a boolean or returns a default value
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvAsDuration gets an environment variable as a duration (e.g. "30s") or returns a default value
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := time.ParseDuration(valueStr)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// parseHosts parses a comma-separated list of host names, lowercased
+func parseHosts(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, strings.ToLower(item))
+		}
+	}
+	return items
+}
+
 // parseAllowOrigins parses CORS allowed origins from environment variable
 func parseAllowOrigins(originsStr string) []string {
 	if originsStr == "" || originsStr == "*" {
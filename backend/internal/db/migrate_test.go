@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/000010_add_index.up.sql":        {Data: []byte("CREATE INDEX i ON t (c);")},
+		"migrations/000010_add_index.down.sql":      {Data: []byte("DROP INDEX i;")},
+		"migrations/000002_add_column.down.sql":     {Data: []byte("ALTER TABLE t DROP COLUMN c;")},
+		"migrations/000002_add_column.up.sql":       {Data: []byte("ALTER TABLE t ADD COLUMN c TEXT;")},
+		"migrations/000001_initial_schema.up.sql":   {Data: []byte("CREATE TABLE t (id INT);")},
+		"migrations/000001_initial_schema.down.sql": {Data: []byte("DROP TABLE t;")},
+		"migrations/fixtures/seed.sql":              {Data: []byte("INSERT INTO t VALUES (1);")},
+	}
+
+	migrations, err := loadMigrations(fsys, "migrations")
+	if err != nil {
+		t.Fatalf("loadMigrations() error = %v", err)
+	}
+	want := []Migration{
+		{Version: 1, Name: "initial_schema", Up: "CREATE TABLE t (id INT);", Down: "DROP TABLE t;"},
+		{Version: 2, Name: "add_column", Up: "ALTER TABLE t ADD COLUMN c TEXT;", Down: "ALTER TABLE t DROP COLUMN c;"},
+		{Version: 10, Name: "add_index", Up: "CREATE INDEX i ON t (c);", Down: "DROP INDEX i;"},
+	}
+	if len(migrations) != len(want) {
+		t.Fatalf("loadMigrations() = %d migrations, want %d", len(migrations), len(want))
+	}
+	for i := range want {
+		if migrations[i] != want[i] {
+			t.Errorf("migration %d = %+v, want %+v", i, migrations[i], want[i])
+		}
+	}
+}
+
+func TestLoadMigrationsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  string
+	}{
+		{name: "invalid name", files: []string{"0001_Initial.up.sql"}, want: "invalid migration file name"},
+		{name: "not sql", files: []string{"0001_initial.up.txt"}, want: "invalid migration file name"},
+		{name: "no direction", files: []string{"0001_initial.sql"}, want: "invalid migration file name"},
+		{name: "version out of range", files: []string{"99999999999999999999_initial.up.sql"}, want: "invalid migration version"},
+		{name: "missing up", files: []string{"0001_initial.down.sql"}, want: "missing an up file"},
+		{name: "missing down", files: []string{"0001_initial.up.sql"}, want: "missing a down file"},
+		{name: "conflicting names", files: []string{"0001_initial.up.sql", "0001_other.down.sql"}, want: "conflicting names for migration 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{}
+			for _, name := range tt.files {
+				fsys["migrations/"+name] = &fstest.MapFile{Data: []byte("SELECT 1;")}
+			}
+			_, err := loadMigrations(fsys, "migrations")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("loadMigrations() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := loadMigrations(fstest.MapFS{}, "migrations"); err == nil {
+		t.Error("loadMigrations() accepted a missing directory")
+	}
+}
+
+// TestEmbeddedMigrations checks the shipped migrations load and are numbered
+// without gaps, so a new migration cannot reuse or skip a version
+func TestEmbeddedMigrations(t *testing.T) {
+	migrator, err := NewMigrator(nil, nil)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+	if len(migrator.migrations) == 0 {
+		t.Fatal("no migrations embedded")
+	}
+	for i, migration := range migrator.migrations {
+		if migration.Version != int64(i+1) {
+			t.Errorf("migration %d_%s follows version %d", migration.Version, migration.Name, i)
+		}
+		if strings.TrimSpace(migration.Up) == "" || strings.TrimSpace(migration.Down) == "" {
+			t.Errorf("migration %d_%s has an empty up or down file", migration.Version, migration.Name)
+		}
+	}
+}
+
+func TestMigratorDownSteps(t *testing.T) {
+	// Invalid step counts are rejected before the database is touched
+	migrator := &Migrator{}
+	for _, steps := range []int{0, -1} {
+		if err := migrator.Down(context.Background(), steps); err == nil || !strings.Contains(err.Error(), "at least 1") {
+			t.Errorf("Down(%d) error = %v, want a step count error", steps, err)
+		}
+	}
+}
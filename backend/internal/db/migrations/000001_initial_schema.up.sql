@@ -8,6 +8,9 @@
  */
 
 -- Policy Hub Database Schema (Single Table Architecture)
+--
+-- Statements are idempotent so that deployments created before versioned
+-- migrations were introduced can adopt this baseline without changes.
 
 -- Single policy_version table with all metadata
 CREATE TABLE IF NOT EXISTS policy_version (
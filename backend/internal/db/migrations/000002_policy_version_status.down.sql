@@ -0,0 +1,17 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+DROP INDEX IF EXISTS idx_policy_version_resolvable;
+
+ALTER TABLE policy_version
+	DROP CONSTRAINT IF EXISTS policy_version_status_check,
+	DROP COLUMN IF EXISTS status_updated_at,
+	DROP COLUMN IF EXISTS replacement_version,
+	DROP COLUMN IF EXISTS status_reason,
+	DROP COLUMN IF EXISTS status;
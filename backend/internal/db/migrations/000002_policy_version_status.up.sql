@@ -0,0 +1,23 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+-- Lifecycle status for published versions. Deprecated versions remain resolvable
+-- with a warning; yanked versions are only reachable by exact version.
+ALTER TABLE policy_version
+	ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active',
+	ADD COLUMN status_reason TEXT,
+	ADD COLUMN replacement_version VARCHAR(50),
+	ADD COLUMN status_updated_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE policy_version
+	ADD CONSTRAINT policy_version_status_check CHECK (status IN ('active', 'deprecated', 'yanked'));
+
+CREATE INDEX idx_policy_version_resolvable
+ON policy_version (policy_name, major_version DESC, minor_version DESC, patch_version DESC)
+WHERE status <> 'yanked';
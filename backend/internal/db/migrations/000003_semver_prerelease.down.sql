@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+-- The original generated columns cannot parse pre-release or build metadata
+-- versions. Rather than deleting published versions, refuse to revert while
+-- any exist; an operator must remove them first.
+DO $$
+DECLARE
+	remaining BIGINT;
+BEGIN
+	SELECT count(*) INTO remaining FROM policy_version WHERE version !~ '^\d+\.\d+\.\d+$';
+	IF remaining > 0 THEN
+		RAISE EXCEPTION 'cannot revert migration 3: % policy versions have pre-release or build metadata', remaining
+			USING HINT = 'Delete the versions listed by: SELECT policy_name, version FROM policy_version WHERE version !~ ''^\d+\.\d+\.\d+$''';
+	END IF;
+END
+$$;
+
+DROP INDEX IF EXISTS idx_policy_version_precedence_unique;
+
+ALTER TABLE policy_version
+	DROP COLUMN prerelease_key,
+	DROP COLUMN prerelease,
+	DROP COLUMN major_version,
+	DROP COLUMN minor_version,
+	DROP COLUMN patch_version;
+
+DROP FUNCTION IF EXISTS semver_prerelease_key(TEXT);
+
+ALTER TABLE policy_version
+	ADD COLUMN major_version INT GENERATED ALWAYS AS (
+		CASE WHEN version ~ '^\d+\.\d+\.\d+' THEN split_part(version, '.', 1)::INT ELSE NULL END
+	) STORED,
+	ADD COLUMN minor_version INT GENERATED ALWAYS AS (
+		CASE WHEN version ~ '^\d+\.\d+\.\d+' THEN split_part(version, '.', 2)::INT ELSE NULL END
+	) STORED,
+	ADD COLUMN patch_version INT GENERATED ALWAYS AS (
+		CASE WHEN version ~ '^\d+\.\d+\.\d+' THEN split_part(version, '.', 3)::INT ELSE NULL END
+	) STORED;
+
+CREATE INDEX idx_policy_version_semver
+ON policy_version (policy_name, major_version DESC, minor_version DESC, patch_version DESC);
+
+CREATE INDEX idx_policy_version_patch_lookup
+ON policy_version (policy_name, major_version, minor_version, patch_version DESC);
+
+CREATE INDEX idx_policy_version_resolvable
+ON policy_version (policy_name, major_version DESC, minor_version DESC, patch_version DESC)
+WHERE status <> 'yanked';
@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+-- Full SemVer 2.0 versions: major.minor.patch[-prerelease][+build].
+--
+-- semver_prerelease_key maps a pre-release to a TEXT[] whose ordering (under the
+-- "C" collation) is SemVer precedence: numeric identifiers are length-prefixed so
+-- they compare numerically and sort below alphanumeric ones, a shorter identifier
+-- list sorts first, and a release ({'2'}) sorts above all of its pre-releases.
+CREATE OR REPLACE FUNCTION semver_prerelease_key(prerelease TEXT) RETURNS TEXT[]
+LANGUAGE SQL IMMUTABLE PARALLEL SAFE AS $$
+	SELECT CASE
+		WHEN prerelease IS NULL OR prerelease = '' THEN ARRAY['2']
+		ELSE ARRAY(
+			SELECT CASE
+				WHEN ident ~ '^\d+$' THEN '0' || lpad(length(ident)::TEXT, 4, '0') || ident
+				ELSE '1' || ident
+			END
+			FROM unnest(string_to_array(prerelease, '.')) WITH ORDINALITY AS t(ident, ord)
+			ORDER BY ord
+		)
+	END
+$$;
+
+-- The original version part columns cast split_part(version, '.', 3) and fail on
+-- "2.0.0-beta.1", so they are re-derived from the numeric prefix only. Dropping
+-- them also drops the semver indexes, which are recreated below.
+ALTER TABLE policy_version
+	DROP COLUMN major_version,
+	DROP COLUMN minor_version,
+	DROP COLUMN patch_version;
+
+ALTER TABLE policy_version
+	ADD COLUMN major_version INT GENERATED ALWAYS AS (substring(version FROM '^(\d+)\.\d+\.\d+')::INT) STORED,
+	ADD COLUMN minor_version INT GENERATED ALWAYS AS (substring(version FROM '^\d+\.(\d+)\.\d+')::INT) STORED,
+	ADD COLUMN patch_version INT GENERATED ALWAYS AS (substring(version FROM '^\d+\.\d+\.(\d+)')::INT) STORED,
+	ADD COLUMN prerelease TEXT GENERATED ALWAYS AS (substring(version FROM '^\d+\.\d+\.\d+-([0-9A-Za-z.-]+)')) STORED,
+	ADD COLUMN prerelease_key TEXT[] COLLATE "C" GENERATED ALWAYS AS (
+		semver_prerelease_key(substring(version FROM '^\d+\.\d+\.\d+-([0-9A-Za-z.-]+)'))
+	) STORED;
+
+CREATE INDEX idx_policy_version_semver
+ON policy_version (policy_name, major_version DESC, minor_version DESC, patch_version DESC, prerelease_key DESC);
+
+CREATE INDEX idx_policy_version_patch_lookup
+ON policy_version (policy_name, major_version, minor_version, patch_version DESC, prerelease_key DESC);
+
+CREATE INDEX idx_policy_version_resolvable
+ON policy_version (policy_name, major_version DESC, minor_version DESC, patch_version DESC, prerelease_key DESC)
+WHERE status <> 'yanked';
+
+-- Versions differing only in build metadata have the same precedence, so only one may be published
+CREATE UNIQUE INDEX idx_policy_version_precedence_unique
+ON policy_version (policy_name, major_version, minor_version, patch_version, prerelease_key);
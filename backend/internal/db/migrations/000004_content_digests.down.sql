@@ -0,0 +1,14 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+ALTER TABLE policy_version
+	DROP CONSTRAINT IF EXISTS policy_version_artifact_digest_check,
+	DROP CONSTRAINT IF EXISTS policy_version_definition_digest_check,
+	DROP COLUMN IF EXISTS artifact_digest,
+	DROP COLUMN IF EXISTS definition_digest;
@@ -0,0 +1,22 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+-- SHA-256 digests ("sha256:<hex>") of the definition YAML and of the artifact
+-- at download_url, computed by the sync pipeline. Existing definitions are
+-- backfilled; their artifacts were never fetched, so artifact_digest stays NULL.
+ALTER TABLE policy_version
+	ADD COLUMN definition_digest VARCHAR(71),
+	ADD COLUMN artifact_digest VARCHAR(71);
+
+UPDATE policy_version
+SET definition_digest = 'sha256:' || encode(sha256(convert_to(definition_yaml, 'UTF8')), 'hex');
+
+ALTER TABLE policy_version
+	ADD CONSTRAINT policy_version_definition_digest_check CHECK (definition_digest ~ '^sha256:[0-9a-f]{64}$'),
+	ADD CONSTRAINT policy_version_artifact_digest_check CHECK (artifact_digest ~ '^sha256:[0-9a-f]{64}$');
@@ -0,0 +1,19 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+-- Detached release signatures verified at sync time against provider keys.
+-- Only verified signatures are stored; invalid signatures fail the sync.
+ALTER TABLE policy_version
+	ADD COLUMN signature_status VARCHAR(20) NOT NULL DEFAULT 'unsigned',
+	ADD COLUMN signature_key_id VARCHAR(100),
+	ADD COLUMN signature TEXT,
+	ADD COLUMN signature_verified_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE policy_version
+	ADD CONSTRAINT policy_version_signature_status_check CHECK (signature_status IN ('unsigned', 'verified'));
@@ -0,0 +1,41 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+-- Asynchronous sync jobs. A job is claimed by a worker for a lease
+-- (locked_until); jobs whose lease expires, e.g. because the server restarted
+-- mid-sync, are claimed again. Failed attempts are retried from next_run_at.
+CREATE TABLE sync_job (
+	id BIGSERIAL PRIMARY KEY,
+	policy_name VARCHAR(100) NOT NULL,
+	version VARCHAR(50) NOT NULL,
+	provider VARCHAR(100) NOT NULL,
+	request JSONB NOT NULL,
+	status VARCHAR(20) NOT NULL DEFAULT 'queued',
+	attempts INT NOT NULL DEFAULT 0,
+	max_attempts INT NOT NULL,
+	steps JSONB NOT NULL DEFAULT '[]',
+	result JSONB,
+	last_error JSONB,
+	next_run_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+	locked_until TIMESTAMP WITH TIME ZONE,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+	started_at TIMESTAMP WITH TIME ZONE,
+	finished_at TIMESTAMP WITH TIME ZONE,
+
+	CONSTRAINT sync_job_status_check CHECK (status IN ('queued', 'running', 'succeeded', 'failed'))
+);
+
+-- Jobs waiting for a worker or holding a lease
+CREATE INDEX idx_sync_job_pending ON sync_job (next_run_at, id)
+WHERE status IN ('queued', 'running');
+
+-- At most one unfinished job per policy version
+CREATE UNIQUE INDEX idx_sync_job_active ON sync_job (policy_name, version)
+WHERE status IN ('queued', 'running');
@@ -0,0 +1,37 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+-- Policy bundles uploaded to the internal API. The archive is kept as the
+-- artifact of the version it publishes; its unpacked files are read by the
+-- sync job and serve the version's assets.
+CREATE TABLE policy_bundle (
+	id BIGSERIAL PRIMARY KEY,
+	policy_name VARCHAR(100) NOT NULL,
+	version VARCHAR(50) NOT NULL,
+	format VARCHAR(10) NOT NULL,
+	archive BYTEA NOT NULL,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+	CONSTRAINT policy_bundle_format_check CHECK (format IN ('tar.gz', 'zip'))
+);
+
+CREATE TABLE policy_bundle_file (
+	bundle_id BIGINT NOT NULL REFERENCES policy_bundle(id) ON DELETE CASCADE,
+	path VARCHAR(512) NOT NULL,
+	content BYTEA NOT NULL,
+
+	PRIMARY KEY (bundle_id, path)
+);
+
+-- The bundle a version was published from, if any
+ALTER TABLE policy_version
+	ADD COLUMN bundle_id BIGINT REFERENCES policy_bundle(id);
+
+CREATE INDEX idx_policy_version_bundle ON policy_version (bundle_id)
+WHERE bundle_id IS NOT NULL;
@@ -0,0 +1,20 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+-- Doc pages fetched by syncs, by URL, with the ETag they were served with.
+-- Later syncs of the same URL send If-None-Match and reuse the content when
+-- the server answers 304 Not Modified.
+CREATE TABLE doc_fetch_cache (
+	url TEXT PRIMARY KEY,
+	etag TEXT NOT NULL,
+	content TEXT NOT NULL,
+	used_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_doc_fetch_cache_used_at ON doc_fetch_cache (used_at);
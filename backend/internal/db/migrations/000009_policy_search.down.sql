@@ -0,0 +1,18 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+DROP TABLE IF EXISTS policy_version_search;
+
+DROP FUNCTION IF EXISTS policy_search_query(TEXT);
+DROP FUNCTION IF EXISTS policy_search_document(INTEGER);
+DROP FUNCTION IF EXISTS policy_search_vector(TEXT, "char");
+
+CREATE INDEX IF NOT EXISTS idx_policy_version_search
+ON policy_version USING gin (to_tsvector('english', display_name || ' ' || coalesce(description, '')))
+WHERE is_latest = TRUE;
@@ -0,0 +1,61 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+-- Full-text search over policy versions. Each version's search document
+-- weights its name and display name (A) over its tags (B), description (C)
+-- and doc pages (D). Doc pages live in policy_docs, so the document cannot be
+-- an expression index on policy_version; it is stored in policy_version_search
+-- and refreshed whenever a version is published.
+--
+-- Text is indexed with both the english configuration, so "policies" matches
+-- "policy", and the simple one, so that a prefix of an unstemmed word, such as
+-- "authenticat", still matches it.
+CREATE OR REPLACE FUNCTION policy_search_vector(body TEXT, weight "char") RETURNS TSVECTOR
+LANGUAGE SQL IMMUTABLE PARALLEL SAFE AS $$
+	SELECT setweight(to_tsvector('english', coalesce(body, '')) || to_tsvector('simple', coalesce(body, '')), weight)
+$$;
+
+CREATE OR REPLACE FUNCTION policy_search_document(version_id INTEGER) RETURNS TSVECTOR
+LANGUAGE SQL STABLE PARALLEL SAFE AS $$
+	SELECT
+		policy_search_vector(pv.policy_name || ' ' || pv.display_name, 'A') ||
+		policy_search_vector(CASE WHEN jsonb_typeof(pv.tags) = 'array' THEN (
+			SELECT string_agg(tag, ' ') FROM jsonb_array_elements_text(pv.tags) AS tag
+		) END, 'B') ||
+		policy_search_vector(pv.description, 'C') ||
+		policy_search_vector((
+			SELECT string_agg(d.content_md, E'\n' ORDER BY d.page) FROM policy_docs d WHERE d.policy_version_id = pv.id
+		), 'D')
+	FROM policy_version pv
+	WHERE pv.id = version_id
+$$;
+
+-- policy_search_query parses a web-style search (quoted phrases, "or" and
+-- -excluded terms, as websearch_to_tsquery does) and matches every term as a
+-- prefix, so "rate lim" finds "rate-limiting". Searches of only stop words
+-- parse to an empty query, which matches nothing.
+CREATE OR REPLACE FUNCTION policy_search_query(search TEXT) RETURNS TSQUERY
+LANGUAGE SQL IMMUTABLE PARALLEL SAFE AS $$
+	SELECT
+		regexp_replace(websearch_to_tsquery('english', search)::TEXT, '''((?:[^'']|'''')+)''', '''\1'':*', 'g')::TSQUERY ||
+		regexp_replace(websearch_to_tsquery('simple', search)::TEXT, '''((?:[^'']|'''')+)''', '''\1'':*', 'g')::TSQUERY
+$$;
+
+CREATE TABLE policy_version_search (
+	policy_version_id INTEGER PRIMARY KEY REFERENCES policy_version(id) ON DELETE CASCADE,
+	document TSVECTOR NOT NULL
+);
+
+INSERT INTO policy_version_search (policy_version_id, document)
+SELECT id, policy_search_document(id) FROM policy_version;
+
+CREATE INDEX idx_policy_version_search_document ON policy_version_search USING gin (document);
+
+-- The display name and description index was never used by the search queries
+DROP INDEX IF EXISTS idx_policy_version_search;
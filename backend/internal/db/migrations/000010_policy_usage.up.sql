@@ -0,0 +1,19 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+-- How often each policy is used, for sorting the catalog by popularity:
+-- downloads of its definitions, engine payloads and bundles, and the times a
+-- resolve or lock request resolved it. Counts are kept per policy rather than
+-- per version so they survive new releases.
+CREATE TABLE policy_usage (
+	policy_name VARCHAR(100) PRIMARY KEY,
+	download_count BIGINT NOT NULL DEFAULT 0,
+	resolve_count BIGINT NOT NULL DEFAULT 0,
+	updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,15 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+DROP FUNCTION IF EXISTS policy_supports_platforms(JSONB, TEXT[], BOOLEAN);
+DROP FUNCTION IF EXISTS policy_has_values(JSONB, TEXT[], BOOLEAN);
+DROP FUNCTION IF EXISTS platform_supports(TEXT, TEXT);
+DROP FUNCTION IF EXISTS platform_version_end(TEXT);
+DROP FUNCTION IF EXISTS platform_version(TEXT);
+DROP FUNCTION IF EXISTS platform_product(TEXT);
@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+-- Catalog filters over a policy version's JSONB string arrays.
+--
+-- Supported platforms are declared as "<product>-<version>", covering that
+-- version of the product and its patch versions ("apim-4.4" covers 4.4 and
+-- 4.4.1, up to 4.5), or "<product>-<version>+", covering that version and
+-- every later one. A platform without a version covers every version of the
+-- product. Products compare case-insensitively and versions numerically, with
+-- trailing zeros ignored, so 4, 4.0 and 4.0.0 are the same version.
+CREATE OR REPLACE FUNCTION platform_product(platform TEXT) RETURNS TEXT
+LANGUAGE SQL IMMUTABLE PARALLEL SAFE AS $$
+	SELECT lower(coalesce(substring(platform FROM '^(.+)-\d{1,9}(?:\.\d{1,9})*\+?$'), platform))
+$$;
+
+-- platform_version is the version of a platform without its trailing zeros,
+-- NULL for a platform without a version
+CREATE OR REPLACE FUNCTION platform_version(platform TEXT) RETURNS INT[]
+LANGUAGE SQL IMMUTABLE PARALLEL SAFE AS $$
+	SELECT string_to_array(regexp_replace(substring(platform FROM '-(\d{1,9}(?:\.\d{1,9})*)\+?$'), '(\.0+)+$', ''), '.')::INT[]
+$$;
+
+-- platform_version_end is the first version after those a platform covers:
+-- 4.5 for "apim-4.4", NULL for "apim-4.4+" and platforms without a version
+CREATE OR REPLACE FUNCTION platform_version_end(platform TEXT) RETURNS INT[]
+LANGUAGE SQL IMMUTABLE PARALLEL SAFE AS $$
+	SELECT v[1:cardinality(v) - 1] || (v[cardinality(v)] + 1)
+	FROM (SELECT string_to_array(substring(platform FROM '-(\d{1,9}(?:\.\d{1,9})*)$'), '.')::INT[] AS v) AS version
+$$;
+
+-- platform_supports reports whether a declared platform supports a requested
+-- one: "apim-4.4" requests that version, "apim-4.4+" any version from 4.4 on
+-- and "apim" any version at all. "apim-4.4+" is thus supported by "apim-4.3+",
+-- "apim-4.4", "apim-4.6" and "apim", but not by "apim-4.3".
+CREATE OR REPLACE FUNCTION platform_supports(declared TEXT, requested TEXT) RETURNS BOOLEAN
+LANGUAGE SQL IMMUTABLE PARALLEL SAFE AS $$
+	SELECT platform_product(declared) = platform_product(requested) AND CASE
+		WHEN platform_version(declared) IS NULL OR platform_version(requested) IS NULL THEN TRUE
+		WHEN requested LIKE '%+' THEN
+			platform_version_end(declared) IS NULL OR platform_version(requested) < platform_version_end(declared)
+		ELSE
+			platform_version(declared) <= platform_version(requested)
+			AND (platform_version_end(declared) IS NULL OR platform_version(requested) < platform_version_end(declared))
+	END
+$$;
+
+-- policy_has_values reports whether a JSONB string array holds any of the
+-- wanted values, or all of them with match_all. No wanted values match any
+-- array.
+CREATE OR REPLACE FUNCTION policy_has_values(policy_values JSONB, wanted TEXT[], match_all BOOLEAN) RETURNS BOOLEAN
+LANGUAGE SQL IMMUTABLE PARALLEL SAFE AS $$
+	SELECT coalesce(cardinality(wanted), 0) = 0 OR (
+		jsonb_typeof(policy_values) = 'array'
+		AND CASE WHEN match_all THEN policy_values ?& wanted ELSE policy_values ?| wanted END
+	)
+$$;
+
+-- policy_supports_platforms is policy_has_values for supported platforms,
+-- matching requested platforms with platform_supports
+CREATE OR REPLACE FUNCTION policy_supports_platforms(platforms JSONB, requested TEXT[], match_all BOOLEAN) RETURNS BOOLEAN
+LANGUAGE SQL IMMUTABLE PARALLEL SAFE AS $$
+	WITH declared AS (
+		SELECT platform FROM jsonb_array_elements_text(CASE WHEN jsonb_typeof(platforms) = 'array' THEN platforms END) AS d(platform)
+	)
+	SELECT coalesce(cardinality(requested), 0) = 0 OR CASE
+		WHEN match_all THEN NOT EXISTS (
+			SELECT 1 FROM unnest(requested) AS r(platform)
+			WHERE NOT EXISTS (SELECT 1 FROM declared d WHERE platform_supports(d.platform, r.platform))
+		)
+		ELSE EXISTS (
+			SELECT 1 FROM unnest(requested) AS r(platform), declared d
+			WHERE platform_supports(d.platform, r.platform)
+		)
+	END
+$$;
@@ -0,0 +1,10 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+DROP FUNCTION IF EXISTS policy_listing_candidates(TEXT, TEXT[], BOOLEAN, TEXT[], TEXT[], BOOLEAN, TEXT[], BOOLEAN, DATE, DATE);
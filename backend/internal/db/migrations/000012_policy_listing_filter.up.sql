@@ -0,0 +1,45 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+-- policy_listing_candidates is the one definition of the catalog listing's
+-- filters, shared by the listing, its total and its facet counts. It returns
+-- the listable versions matching the search and release dates, with whether
+-- each matches the category, provider, platform and tag filters: the listing
+-- keeps versions matching all four, and a facet's counts leave its own out.
+-- A single-statement SQL function, so the planner inlines it into its callers.
+CREATE OR REPLACE FUNCTION policy_listing_candidates(
+	search TEXT,
+	wanted_categories TEXT[], all_categories BOOLEAN,
+	wanted_providers TEXT[],
+	wanted_platforms TEXT[], all_platforms BOOLEAN,
+	wanted_tags TEXT[], all_tags BOOLEAN,
+	released_after DATE, released_before DATE
+) RETURNS TABLE (
+	policy_version_id INTEGER,
+	search_rank REAL,
+	category_match BOOLEAN,
+	provider_match BOOLEAN,
+	platform_match BOOLEAN,
+	tag_match BOOLEAN
+)
+LANGUAGE SQL STABLE PARALLEL SAFE AS $$
+	SELECT
+		pv.id,
+		CASE WHEN search = '' THEN 0::real ELSE ts_rank(pvs.document, policy_search_query(search)) END,
+		policy_has_values(pv.categories, wanted_categories, all_categories),
+		coalesce(cardinality(wanted_providers), 0) = 0 OR pv.provider = ANY(wanted_providers),
+		policy_supports_platforms(pv.supported_platforms, wanted_platforms, all_platforms),
+		policy_has_values(pv.tags, wanted_tags, all_tags)
+	FROM policy_version pv
+	LEFT JOIN policy_version_search pvs ON pvs.policy_version_id = pv.id
+	WHERE (search = '' OR pvs.document @@ policy_search_query(search))
+		AND (released_after IS NULL OR pv.release_date >= released_after)
+		AND (released_before IS NULL OR pv.release_date <= released_before)
+		AND pv.status <> 'yanked'
+$$;
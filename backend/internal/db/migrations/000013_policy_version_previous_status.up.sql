@@ -0,0 +1,22 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+-- Status and reason a version had before it was yanked, restored when it is
+-- un-yanked. Versions yanked earlier kept the replacement of a deprecation, so
+-- those are restored as deprecated; their deprecation reason is lost.
+ALTER TABLE policy_version
+	ADD COLUMN previous_status VARCHAR(20),
+	ADD COLUMN previous_status_reason TEXT;
+
+ALTER TABLE policy_version
+	ADD CONSTRAINT policy_version_previous_status_check CHECK (previous_status IN ('active', 'deprecated'));
+
+UPDATE policy_version
+SET previous_status = CASE WHEN replacement_version IS NULL THEN 'active' ELSE 'deprecated' END
+WHERE status = 'yanked';
@@ -0,0 +1,15 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+-- Digest of the source files a scan queued a job from. A scan only queues a
+-- version again after a failed job when its files changed or the failure was transient.
+ALTER TABLE sync_job ADD COLUMN source_revision TEXT;
+
+-- Latest job of each policy version
+CREATE INDEX idx_sync_job_version ON sync_job (policy_name, version, id DESC);
@@ -0,0 +1,30 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+-- name: GetDocFetchCache :one
+SELECT * FROM doc_fetch_cache
+WHERE url = $1;
+
+-- name: UpsertDocFetchCache :exec
+INSERT INTO doc_fetch_cache (
+    url,
+    etag,
+    content
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (url) DO UPDATE SET
+    etag = EXCLUDED.etag,
+    content = EXCLUDED.content,
+    used_at = NOW();
+
+-- name: DeleteStaleDocFetchCache :execrows
+-- Deletes cached doc pages no sync has used for max_age_seconds
+DELETE FROM doc_fetch_cache
+WHERE used_at < NOW() - sqlc.arg(max_age_seconds)::int * INTERVAL '1 second';
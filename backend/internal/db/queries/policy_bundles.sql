@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+-- name: InsertPolicyBundle :one
+INSERT INTO policy_bundle (
+    policy_name,
+    version,
+    format,
+    archive
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING id;
+
+-- name: InsertPolicyBundleFile :exec
+INSERT INTO policy_bundle_file (
+    bundle_id,
+    path,
+    content
+) VALUES (
+    $1, $2, $3
+);
+
+-- name: GetPolicyBundle :one
+SELECT * FROM policy_bundle
+WHERE id = $1;
+
+-- name: GetPolicyBundleFile :one
+SELECT content FROM policy_bundle_file
+WHERE bundle_id = $1 AND path = $2;
+
+-- name: DeletePolicyBundle :exec
+-- Deletes a bundle unless a version was published from it
+DELETE FROM policy_bundle b
+WHERE b.id = $1
+  AND NOT EXISTS (SELECT 1 FROM policy_version pv WHERE pv.bundle_id = b.id);
+
+-- name: DeleteOrphanedPolicyBundles :execrows
+-- Deletes bundles older than min_age_seconds that no version was published
+-- from and no unfinished sync job reads
+DELETE FROM policy_bundle b
+WHERE b.created_at < NOW() - sqlc.arg(min_age_seconds)::int * INTERVAL '1 second'
+  AND NOT EXISTS (SELECT 1 FROM policy_version pv WHERE pv.bundle_id = b.id)
+  AND NOT EXISTS (
+      SELECT 1 FROM sync_job j
+      WHERE j.status IN ('queued', 'running')
+        AND (j.request->>'bundleId')::bigint = b.id
+  );
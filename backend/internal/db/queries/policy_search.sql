@@ -0,0 +1,15 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+-- name: RefreshPolicyVersionSearch :exec
+-- Rebuilds the search document of a version from its metadata and doc pages
+INSERT INTO policy_version_search (policy_version_id, document)
+VALUES ($1, policy_search_document($1))
+ON CONFLICT (policy_version_id) DO UPDATE SET
+    document = EXCLUDED.document;
@@ -0,0 +1,26 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+-- name: IncrementPolicyDownloads :exec
+INSERT INTO policy_usage (policy_name, download_count)
+VALUES ($1, 1)
+ON CONFLICT (policy_name) DO UPDATE SET
+    download_count = policy_usage.download_count + 1,
+    updated_at = NOW();
+
+-- name: IncrementPolicyResolves :exec
+-- Counts one resolve per occurrence of a name; rows are locked in name order
+-- so concurrent batches cannot deadlock
+INSERT INTO policy_usage (policy_name, resolve_count)
+SELECT name, COUNT(*) FROM unnest(sqlc.arg(policy_names)::text[]) AS name
+GROUP BY name
+ORDER BY name
+ON CONFLICT (policy_name) DO UPDATE SET
+    resolve_count = policy_usage.resolve_count + EXCLUDED.resolve_count,
+    updated_at = NOW();
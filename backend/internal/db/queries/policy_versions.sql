@@ -28,46 +28,186 @@ WHERE policy_name = $1 AND is_latest = TRUE;
 -- name: ListPolicyVersions :many
 SELECT * FROM policy_version
 WHERE policy_name = $1
-ORDER BY created_at DESC
+ORDER BY major_version DESC NULLS LAST, minor_version DESC, patch_version DESC, prerelease_key DESC, created_at DESC, id DESC
 LIMIT $2 OFFSET $3;
 
+-- name: ListPolicyVersionsAfter :many
+-- Versions that come after the version after_id in the ListPolicyVersions order
+SELECT pv.* FROM policy_version pv
+WHERE pv.policy_name = sqlc.arg(policy_name)
+    AND (coalesce(pv.major_version, -1), coalesce(pv.minor_version, -1), coalesce(pv.patch_version, -1), pv.prerelease_key, pv.created_at, pv.id) < (
+        SELECT coalesce(b.major_version, -1), coalesce(b.minor_version, -1), coalesce(b.patch_version, -1), b.prerelease_key, b.created_at, b.id
+        FROM policy_version b WHERE b.id = sqlc.arg(after_id)
+    )
+ORDER BY pv.major_version DESC NULLS LAST, pv.minor_version DESC, pv.patch_version DESC, pv.prerelease_key DESC, pv.created_at DESC, pv.id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListPolicyVersionsBefore :many
+-- Versions that come before the version before_id in the ListPolicyVersions
+-- order, nearest first; the caller reverses them
+SELECT pv.* FROM policy_version pv
+WHERE pv.policy_name = sqlc.arg(policy_name)
+    AND (coalesce(pv.major_version, -1), coalesce(pv.minor_version, -1), coalesce(pv.patch_version, -1), pv.prerelease_key, pv.created_at, pv.id) > (
+        SELECT coalesce(b.major_version, -1), coalesce(b.minor_version, -1), coalesce(b.patch_version, -1), b.prerelease_key, b.created_at, b.id
+        FROM policy_version b WHERE b.id = sqlc.arg(before_id)
+    )
+ORDER BY pv.major_version ASC NULLS FIRST, pv.minor_version ASC, pv.patch_version ASC, pv.prerelease_key ASC, pv.created_at ASC, pv.id ASC
+LIMIT sqlc.arg(row_limit);
+
 -- name: CountPolicyVersions :one
 SELECT COUNT(*) FROM policy_version
 WHERE policy_name = $1;
 
 -- name: FilterPoliciesByMultiple :many
-WITH ranked_versions AS (
+-- Lists the best matching version of each policy, sorted by sort_key (the
+-- value of the sort_by key as text that collates in its order) in sort_order,
+-- then newest and by name, so every policy has a stable position. The page is
+-- read walking the listing from a cursor: key_ascending is the direction of
+-- the walk over sort_key, and walk_backward is set when walking towards the
+-- start, in which case the caller restores the listing order. Without a
+-- cursor, row_offset skips rows instead. The cursor is only applied once
+-- every matching version is ranked: a policy's sort_key is that of its best
+-- matching version, and filtering versions by it first could change which
+-- version is best. With a search, search_snippet wraps the matches in <mark>
+-- tags.
+--
+-- The counts are read by the same statement, so they agree with the page.
+-- With include_total, total_count counts the listed policies. facet_counts
+-- counts them per value of each facet named in facets, as an array of
+-- {"facet", "value", "count"} objects, most common first; a facet's own
+-- filter is left out of its counts, so each count is what selecting only that
+-- value would list. Counts that are not requested are not computed. Every
+-- row carries the counts, and an empty page is a single row without a policy.
+WITH candidates AS (
+    SELECT policy_version_id, search_rank, category_match, provider_match, platform_match, tag_match
+    FROM policy_listing_candidates(
+        sqlc.arg(search)::text,
+        sqlc.arg(categories)::text[], sqlc.arg(match_all_categories)::boolean,
+        sqlc.arg(providers)::text[],
+        sqlc.arg(platforms)::text[], sqlc.arg(match_all_platforms)::boolean,
+        sqlc.arg(tags)::text[], sqlc.arg(match_all_tags)::boolean,
+        sqlc.narg(released_after)::date, sqlc.narg(released_before)::date
+    )
+),
+ranked_versions AS (
     SELECT 
         pv.*,
+        c.search_rank,
+        coalesce(pu.download_count + pu.resolve_count, 0)::bigint AS popularity,
         ROW_NUMBER() OVER (
             PARTITION BY pv.policy_name 
             ORDER BY 
                 pv.is_latest DESC,
+                (pv.prerelease IS NULL) DESC,
+                pv.major_version DESC NULLS LAST,
+                pv.minor_version DESC,
+                pv.patch_version DESC,
+                pv.prerelease_key DESC,
                 pv.created_at DESC
         ) as version_rank
-    FROM policy_version pv
-    WHERE ($1::text = '' OR LOWER(pv.display_name) LIKE LOWER('%' || $1 || '%') OR LOWER(pv.description) LIKE LOWER('%' || $1 || '%'))
-        AND ($2::text[] IS NULL OR array_length($2::text[], 1) = 0 OR EXISTS (SELECT 1 FROM unnest($2::text[]) AS cat WHERE pv.categories ? cat))
-        AND ($3::text[] IS NULL OR array_length($3::text[], 1) = 0 OR pv.provider = ANY($3::text[]))
-        AND ($4::text[] IS NULL OR array_length($4::text[], 1) = 0 OR EXISTS (SELECT 1 FROM unnest($4::text[]) AS plat WHERE pv.supported_platforms ? plat))
+    FROM candidates c
+    JOIN policy_version pv ON pv.id = c.policy_version_id
+    LEFT JOIN policy_usage pu ON pu.policy_name = pv.policy_name
+    WHERE c.category_match AND c.provider_match AND c.platform_match AND c.tag_match
+),
+keyed_versions AS (
+    SELECT
+        *,
+        coalesce(CASE sqlc.arg(sort_by)::text
+            WHEN 'name' THEN lower(policy_name)
+            WHEN 'displayName' THEN lower(display_name)
+            WHEN 'provider' THEN lower(provider)
+            WHEN 'releaseDate' THEN coalesce(to_char(release_date, 'YYYY-MM-DD'), CASE WHEN sqlc.arg(sort_order)::text = 'asc' THEN '~' ELSE '' END)
+            WHEN 'updated' THEN to_char(updated_at AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS.US')
+            WHEN 'popularity' THEN lpad(popularity::text, 20, '0')
+            WHEN 'relevance' THEN to_char(search_rank, 'FM000000.000000000')
+            ELSE to_char(created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS.US')
+        END, '') COLLATE "C" AS sort_key
+    FROM ranked_versions
+    WHERE version_rank = 1
+),
+walked_versions AS (
+    SELECT
+        *,
+        ROW_NUMBER() OVER (
+            ORDER BY
+                CASE WHEN sqlc.arg(key_ascending)::boolean THEN sort_key END ASC,
+                CASE WHEN NOT sqlc.arg(key_ascending)::boolean THEN sort_key END DESC,
+                CASE WHEN sqlc.arg(walk_backward)::boolean THEN created_at END ASC,
+                CASE WHEN NOT sqlc.arg(walk_backward)::boolean THEN created_at END DESC,
+                CASE WHEN sqlc.arg(walk_backward)::boolean THEN policy_name END DESC,
+                CASE WHEN NOT sqlc.arg(walk_backward)::boolean THEN policy_name END ASC
+        ) AS walk_position
+    FROM keyed_versions
+    WHERE sqlc.narg(cursor_key)::text IS NULL
+        OR (sqlc.arg(key_ascending)::boolean AND sort_key > sqlc.narg(cursor_key)::text)
+        OR (NOT sqlc.arg(key_ascending)::boolean AND sort_key < sqlc.narg(cursor_key)::text)
+        OR (sort_key = sqlc.narg(cursor_key)::text AND CASE WHEN sqlc.arg(walk_backward)::boolean
+            THEN created_at > sqlc.narg(cursor_created)::timestamptz OR (created_at = sqlc.narg(cursor_created)::timestamptz AND policy_name < sqlc.narg(cursor_name)::text)
+            ELSE created_at < sqlc.narg(cursor_created)::timestamptz OR (created_at = sqlc.narg(cursor_created)::timestamptz AND policy_name > sqlc.narg(cursor_name)::text)
+        END)
+),
+page_versions AS (
+    SELECT * FROM walked_versions
+    ORDER BY walk_position
+    LIMIT sqlc.arg(row_limit)::int OFFSET sqlc.arg(row_offset)::int
+),
+matching AS (
+    SELECT
+        pv.policy_name, pv.provider, pv.categories, pv.supported_platforms, pv.tags,
+        c.category_match, c.provider_match, c.platform_match, c.tag_match
+    FROM candidates c
+    JOIN policy_version pv ON pv.id = c.policy_version_id
+),
+facet_values AS (
+    SELECT 'category'::text AS facet, category.value::text AS value, COUNT(DISTINCT m.policy_name) AS policy_count
+    FROM matching m, jsonb_array_elements_text(CASE WHEN jsonb_typeof(m.categories) = 'array' THEN m.categories END) AS category(value)
+    WHERE 'category' = ANY(sqlc.arg(facets)::text[]) AND m.provider_match AND m.platform_match AND m.tag_match
+    GROUP BY category.value
+    UNION ALL
+    SELECT 'provider', m.provider, COUNT(DISTINCT m.policy_name)
+    FROM matching m
+    WHERE 'provider' = ANY(sqlc.arg(facets)::text[]) AND m.category_match AND m.platform_match AND m.tag_match
+    GROUP BY m.provider
+    UNION ALL
+    SELECT 'platform', platform.value, COUNT(DISTINCT m.policy_name)
+    FROM matching m, jsonb_array_elements_text(CASE WHEN jsonb_typeof(m.supported_platforms) = 'array' THEN m.supported_platforms END) AS platform(value)
+    WHERE 'platform' = ANY(sqlc.arg(facets)::text[]) AND m.category_match AND m.provider_match AND m.tag_match
+    GROUP BY platform.value
+    UNION ALL
+    SELECT 'tag', tag.value, COUNT(DISTINCT m.policy_name)
+    FROM matching m, jsonb_array_elements_text(CASE WHEN jsonb_typeof(m.tags) = 'array' THEN m.tags END) AS tag(value)
+    WHERE 'tag' = ANY(sqlc.arg(facets)::text[]) AND m.category_match AND m.provider_match AND m.platform_match
+    GROUP BY tag.value
+),
+listing_counts AS (
+    SELECT
+        CASE WHEN sqlc.arg(include_total)::boolean THEN (SELECT COUNT(*) FROM keyed_versions) END AS total_count,
+        CASE WHEN cardinality(sqlc.arg(facets)::text[]) > 0 THEN coalesce((
+            SELECT jsonb_agg(jsonb_build_object('facet', facet, 'value', value, 'count', policy_count) ORDER BY facet, policy_count DESC, value)
+            FROM facet_values
+        ), '[]'::jsonb) END AS facet_counts
 )
 SELECT 
-    id, policy_name, version, is_latest, display_name, provider, description, 
-    categories, tags, logo_path, banner_path, supported_platforms, 
-    release_date, definition_yaml, icon_path, source_type, download_url, 
-    created_at, updated_at
-FROM ranked_versions 
-WHERE version_rank = 1
-ORDER BY created_at DESC
-LIMIT $5 OFFSET $6;
-
--- name: CountPoliciesByMultiple :one
-SELECT COUNT(DISTINCT pv.policy_name) FROM policy_version pv
-WHERE ($1::text = '' OR LOWER(pv.display_name) LIKE LOWER('%' || $1 || '%') OR LOWER(pv.description) LIKE LOWER('%' || $1 || '%'))
-    AND ($2::text[] IS NULL OR array_length($2::text[], 1) = 0 OR EXISTS (SELECT 1 FROM unnest($2::text[]) AS cat WHERE pv.categories ? cat))
-    AND ($3::text[] IS NULL OR array_length($3::text[], 1) = 0 OR pv.provider = ANY($3::text[]))
-    AND ($4::text[] IS NULL or array_length($4::text[], 1) = 0 OR EXISTS (SELECT 1 FROM unnest($4::text[]) AS plat WHERE pv.supported_platforms ? plat))
-;
+    p.id, p.policy_name, p.version, p.is_latest, p.display_name, p.provider, p.description, 
+    p.categories, p.tags, p.logo_path, p.banner_path, p.supported_platforms, 
+    p.release_date, p.definition_yaml, p.icon_path, p.source_type, p.download_url, 
+    p.created_at, p.updated_at, p.status, p.status_reason, p.replacement_version,
+    p.definition_digest, p.artifact_digest, p.signature_status, p.signature_key_id,
+    p.sort_key::text AS sort_key,
+    (CASE WHEN sqlc.arg(search)::text = '' OR p.id IS NULL THEN '' ELSE ts_headline(
+        'english',
+        coalesce(p.description, '') || E'\n' || coalesce((
+            SELECT string_agg(d.content_md, E'\n' ORDER BY d.page) FROM policy_docs d WHERE d.policy_version_id = p.id
+        ), ''),
+        policy_search_query(sqlc.arg(search)::text),
+        'StartSel=<mark>, StopSel=</mark>, MinWords=15, MaxWords=35, MaxFragments=2, FragmentDelimiter=" ... "'
+    ) END)::text AS search_snippet,
+    l.total_count::bigint AS total_count,
+    l.facet_counts
+FROM listing_counts l
+LEFT JOIN page_versions p ON TRUE
+ORDER BY p.walk_position;
 
 -- =============================================================================
 -- METADATA OPERATIONS
@@ -103,6 +243,16 @@ SET is_latest = CASE
 END
 WHERE policy_name = $1;
 
+-- name: LockPolicy :exec
+SELECT pg_advisory_xact_lock(hashtext(sqlc.arg(policy_name)::text));
+
+-- name: GetPolicyProvider :one
+-- Returns the provider that first published the policy; later versions must match it.
+SELECT provider FROM policy_version
+WHERE policy_name = $1
+ORDER BY created_at ASC, id ASC
+LIMIT 1;
+
 -- name: InsertPolicyVersion :one
 INSERT INTO policy_version (
     policy_name,
@@ -121,13 +271,45 @@ INSERT INTO policy_version (
     icon_path,
     source_type,
     download_url,
+    definition_digest,
+    artifact_digest,
+    signature_status,
+    signature_key_id,
+    signature,
+    signature_verified_at,
+    bundle_id,
     created_at,
     updated_at
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, NOW(), NOW()
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, NOW(), NOW()
 )
 RETURNING *;
 
+-- =============================================================================
+-- VERSION LIFECYCLE (DEPRECATE / YANK)
+-- =============================================================================
+
+-- name: UpdatePolicyVersionStatus :one
+UPDATE policy_version
+SET status = $3,
+    status_reason = $4,
+    replacement_version = $5,
+    previous_status = $6,
+    previous_status_reason = $7,
+    status_updated_at = NOW(),
+    updated_at = NOW()
+WHERE policy_name = $1 AND version = $2
+RETURNING *;
+
+-- name: ListResolvableVersions :many
+SELECT version FROM policy_version
+WHERE policy_name = $1 AND status <> 'yanked';
+
+-- name: ClearLatestVersion :exec
+UPDATE policy_version
+SET is_latest = FALSE
+WHERE policy_name = $1 AND is_latest = TRUE;
+
 -- =============================================================================
 -- STRATEGY-BASED POLICY RETRIEVAL
 -- =============================================================================
@@ -141,6 +323,8 @@ SELECT * FROM policy_version
 WHERE policy_name = $1 
   AND major_version = $2 
   AND minor_version = $3
+  AND status <> 'yanked'
+  AND prerelease IS NULL
 ORDER BY patch_version DESC
 LIMIT 1;
 
@@ -148,12 +332,16 @@ LIMIT 1;
 SELECT * FROM policy_version
 WHERE policy_name = $1 
   AND major_version = $2
+  AND status <> 'yanked'
+  AND prerelease IS NULL
 ORDER BY minor_version DESC, patch_version DESC
 LIMIT 1;
 
 -- name: GetPolicyVersionByLatestMajor :one
 SELECT * FROM policy_version
 WHERE policy_name = $1
+  AND status <> 'yanked'
+  AND prerelease IS NULL
 ORDER BY major_version DESC, minor_version DESC, patch_version DESC
 LIMIT 1;
 
@@ -161,6 +349,59 @@ LIMIT 1;
 -- BULK STRATEGY-BASED POLICY RETRIEVAL
 -- =============================================================================
 
--- name: BulkGetPolicyVersionsByNames :many
-SELECT * FROM policy_version
-WHERE policy_name = ANY($1::text[]);
+-- Resolves many version range requests in one round trip. Each unnested row is
+-- one range of one request (a constraint with "||" contributes several rows for
+-- the same request_index); DISTINCT ON keeps the highest matching version per
+-- request. Bounds compare (major, minor, patch, prerelease_key) so pre-releases
+-- order below their release; an empty bound pre-release means a release bound.
+-- Unbounded ends are passed as -1.0.0 / max int bounds. Yanked versions only
+-- match requests that pin an exact version, and pre-releases only match when the
+-- request opts in or a bound is a pre-release of the same major.minor.patch.
+-- verified_only restricts a request to releases with a verified signature.
+-- name: BulkGetPolicyVersionsByRanges :many
+SELECT DISTINCT ON (req.request_index) req.request_index::int AS request_index, sqlc.embed(pv)
+FROM unnest(
+    sqlc.arg(request_indexes)::int[],
+    sqlc.arg(policy_names)::text[],
+    sqlc.arg(lower_majors)::int[],
+    sqlc.arg(lower_minors)::int[],
+    sqlc.arg(lower_patches)::int[],
+    sqlc.arg(lower_prereleases)::text[],
+    sqlc.arg(lower_inclusive)::bool[],
+    sqlc.arg(upper_majors)::int[],
+    sqlc.arg(upper_minors)::int[],
+    sqlc.arg(upper_patches)::int[],
+    sqlc.arg(upper_prereleases)::text[],
+    sqlc.arg(upper_inclusive)::bool[],
+    sqlc.arg(allow_yanked)::bool[],
+    sqlc.arg(include_prerelease)::bool[],
+    sqlc.arg(verified_only)::bool[]
+  ) AS req(request_index, policy_name, lower_major, lower_minor, lower_patch, lower_prerelease, lower_inclusive,
+           upper_major, upper_minor, upper_patch, upper_prerelease, upper_inclusive, allow_yanked, include_prerelease,
+           verified_only)
+JOIN policy_version pv
+  ON pv.policy_name = req.policy_name
+WHERE (pv.status <> 'yanked' OR req.allow_yanked)
+  AND (pv.signature_status = 'verified' OR NOT req.verified_only)
+  AND ((pv.major_version, pv.minor_version, pv.patch_version, pv.prerelease_key)
+         > (req.lower_major, req.lower_minor, req.lower_patch, semver_prerelease_key(req.lower_prerelease))
+    OR (req.lower_inclusive AND (pv.major_version, pv.minor_version, pv.patch_version, pv.prerelease_key)
+         = (req.lower_major, req.lower_minor, req.lower_patch, semver_prerelease_key(req.lower_prerelease))))
+  AND ((pv.major_version, pv.minor_version, pv.patch_version, pv.prerelease_key)
+         < (req.upper_major, req.upper_minor, req.upper_patch, semver_prerelease_key(req.upper_prerelease))
+    OR (req.upper_inclusive AND (pv.major_version, pv.minor_version, pv.patch_version, pv.prerelease_key)
+         = (req.upper_major, req.upper_minor, req.upper_patch, semver_prerelease_key(req.upper_prerelease))))
+  AND (pv.prerelease IS NULL
+    OR req.include_prerelease
+    OR (req.lower_prerelease <> '' AND (pv.major_version, pv.minor_version, pv.patch_version) = (req.lower_major, req.lower_minor, req.lower_patch))
+    OR (req.upper_prerelease <> '' AND (pv.major_version, pv.minor_version, pv.patch_version) = (req.upper_major, req.upper_minor, req.upper_patch)))
+ORDER BY req.request_index, pv.major_version DESC, pv.minor_version DESC, pv.patch_version DESC, pv.prerelease_key DESC;
+
+-- name: GetExistingPolicyNames :many
+SELECT DISTINCT policy_name FROM policy_version
+WHERE policy_name = ANY(sqlc.arg(policy_names)::text[]);
+
+-- name: ListExistingVersions :many
+-- Lists the stored versions of the given policies, whatever their status
+SELECT policy_name, version FROM policy_version
+WHERE policy_name = ANY(sqlc.arg(policy_names)::text[]);
@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+-- name: InsertSyncJob :one
+INSERT INTO sync_job (
+    policy_name,
+    version,
+    provider,
+    request,
+    max_attempts,
+    steps,
+    source_revision
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: GetSyncJob :one
+SELECT * FROM sync_job
+WHERE id = $1;
+
+-- name: GetActiveSyncJob :one
+SELECT * FROM sync_job
+WHERE policy_name = $1 AND version = $2 AND status IN ('queued', 'running');
+
+-- name: ListLatestSyncJobs :many
+-- Lists the most recent job of each version of the given policies
+SELECT DISTINCT ON (policy_name, version) * FROM sync_job
+WHERE policy_name = ANY(sqlc.arg(policy_names)::text[])
+ORDER BY policy_name, version, id DESC;
+
+-- name: ClaimSyncJob :one
+-- Claims the oldest due job: a queued job whose retry time has come, or a
+-- running job whose worker lost its lease with attempts left
+UPDATE sync_job
+SET status = 'running',
+    attempts = attempts + 1,
+    locked_until = NOW() + sqlc.arg(lease_seconds)::int * INTERVAL '1 second',
+    started_at = COALESCE(started_at, NOW()),
+    updated_at = NOW()
+WHERE id = (
+    SELECT id FROM sync_job
+    WHERE (status = 'queued' AND next_run_at <= NOW())
+       OR (status = 'running' AND locked_until < NOW() AND attempts < max_attempts)
+    ORDER BY next_run_at, id
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: UpdateSyncJobSteps :exec
+-- This and the updates below only apply while the caller still holds the attempt it claimed
+UPDATE sync_job
+SET steps = sqlc.arg(steps),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id) AND attempts = sqlc.arg(attempt) AND status = 'running';
+
+-- name: CompleteSyncJob :exec
+UPDATE sync_job
+SET status = 'succeeded',
+    result = sqlc.arg(result),
+    last_error = NULL,
+    locked_until = NULL,
+    finished_at = NOW(),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id) AND attempts = sqlc.arg(attempt) AND status = 'running';
+
+-- name: RetrySyncJob :exec
+UPDATE sync_job
+SET status = 'queued',
+    last_error = sqlc.arg(last_error),
+    next_run_at = NOW() + sqlc.arg(delay_seconds)::int * INTERVAL '1 second',
+    locked_until = NULL,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id) AND attempts = sqlc.arg(attempt) AND status = 'running';
+
+-- name: FailSyncJob :exec
+UPDATE sync_job
+SET status = 'failed',
+    last_error = sqlc.arg(last_error),
+    locked_until = NULL,
+    finished_at = NOW(),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id) AND attempts = sqlc.arg(attempt) AND status = 'running';
+
+-- name: ReleaseSyncJob :exec
+-- Hands an interrupted attempt back to the queue without counting it
+UPDATE sync_job
+SET status = 'queued',
+    attempts = attempts - 1,
+    next_run_at = NOW(),
+    locked_until = NULL,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id) AND attempts = sqlc.arg(attempt) AND status = 'running';
+
+-- name: FailAbandonedSyncJobs :execrows
+-- Fails jobs whose last attempt lost its lease, so they are not left running forever
+UPDATE sync_job
+SET status = 'failed',
+    last_error = $1,
+    locked_until = NULL,
+    finished_at = NOW(),
+    updated_at = NOW()
+WHERE status = 'running' AND locked_until < NOW() AND attempts >= max_attempts;
+
+-- name: DeleteFinishedSyncJobs :execrows
+-- Deletes succeeded and failed jobs that finished more than min_age_seconds ago
+DELETE FROM sync_job
+WHERE status IN ('succeeded', 'failed')
+  AND finished_at < NOW() - sqlc.arg(min_age_seconds)::int * INTERVAL '1 second';
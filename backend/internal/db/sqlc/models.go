@@ -8,6 +8,28 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type DocFetchCache struct {
+	Url     string             `json:"url"`
+	Etag    string             `json:"etag"`
+	Content string             `json:"content"`
+	UsedAt  pgtype.Timestamptz `json:"used_at"`
+}
+
+type PolicyBundle struct {
+	ID         int64              `json:"id"`
+	PolicyName string             `json:"policy_name"`
+	Version    string             `json:"version"`
+	Format     string             `json:"format"`
+	Archive    []byte             `json:"archive"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type PolicyBundleFile struct {
+	BundleID int64  `json:"bundle_id"`
+	Path     string `json:"path"`
+	Content  []byte `json:"content"`
+}
+
 type PolicyDoc struct {
 	ID              int32              `json:"id"`
 	PolicyVersionID int32              `json:"policy_version_id"`
@@ -17,27 +39,75 @@ type PolicyDoc struct {
 	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
 }
 
+type PolicyUsage struct {
+	PolicyName    string             `json:"policy_name"`
+	DownloadCount int64              `json:"download_count"`
+	ResolveCount  int64              `json:"resolve_count"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+}
+
 type PolicyVersion struct {
-	ID                 int32              `json:"id"`
-	PolicyName         string             `json:"policy_name"`
-	Version            string             `json:"version"`
-	IsLatest           pgtype.Bool        `json:"is_latest"`
-	DisplayName        string             `json:"display_name"`
-	Provider           string             `json:"provider"`
-	Description        pgtype.Text        `json:"description"`
-	Categories         []byte             `json:"categories"`
-	Tags               []byte             `json:"tags"`
-	LogoPath           pgtype.Text        `json:"logo_path"`
-	BannerPath         pgtype.Text        `json:"banner_path"`
-	SupportedPlatforms []byte             `json:"supported_platforms"`
-	ReleaseDate        pgtype.Date        `json:"release_date"`
-	DefinitionYaml     string             `json:"definition_yaml"`
-	IconPath           pgtype.Text        `json:"icon_path"`
-	SourceType         pgtype.Text        `json:"source_type"`
-	DownloadUrl        pgtype.Text        `json:"download_url"`
-	CreatedAt          pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
-	MajorVersion       pgtype.Int4        `json:"major_version"`
-	MinorVersion       pgtype.Int4        `json:"minor_version"`
-	PatchVersion       pgtype.Int4        `json:"patch_version"`
+	ID                   int32              `json:"id"`
+	PolicyName           string             `json:"policy_name"`
+	Version              string             `json:"version"`
+	IsLatest             pgtype.Bool        `json:"is_latest"`
+	DisplayName          string             `json:"display_name"`
+	Provider             string             `json:"provider"`
+	Description          pgtype.Text        `json:"description"`
+	Categories           []byte             `json:"categories"`
+	Tags                 []byte             `json:"tags"`
+	LogoPath             pgtype.Text        `json:"logo_path"`
+	BannerPath           pgtype.Text        `json:"banner_path"`
+	SupportedPlatforms   []byte             `json:"supported_platforms"`
+	ReleaseDate          pgtype.Date        `json:"release_date"`
+	DefinitionYaml       string             `json:"definition_yaml"`
+	IconPath             pgtype.Text        `json:"icon_path"`
+	SourceType           pgtype.Text        `json:"source_type"`
+	DownloadUrl          pgtype.Text        `json:"download_url"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+	Status               string             `json:"status"`
+	StatusReason         pgtype.Text        `json:"status_reason"`
+	ReplacementVersion   pgtype.Text        `json:"replacement_version"`
+	StatusUpdatedAt      pgtype.Timestamptz `json:"status_updated_at"`
+	MajorVersion         pgtype.Int4        `json:"major_version"`
+	MinorVersion         pgtype.Int4        `json:"minor_version"`
+	PatchVersion         pgtype.Int4        `json:"patch_version"`
+	Prerelease           pgtype.Text        `json:"prerelease"`
+	PrereleaseKey        []string           `json:"prerelease_key"`
+	DefinitionDigest     pgtype.Text        `json:"definition_digest"`
+	ArtifactDigest       pgtype.Text        `json:"artifact_digest"`
+	SignatureStatus      string             `json:"signature_status"`
+	SignatureKeyID       pgtype.Text        `json:"signature_key_id"`
+	Signature            pgtype.Text        `json:"signature"`
+	SignatureVerifiedAt  pgtype.Timestamptz `json:"signature_verified_at"`
+	BundleID             pgtype.Int8        `json:"bundle_id"`
+	PreviousStatus       pgtype.Text        `json:"previous_status"`
+	PreviousStatusReason pgtype.Text        `json:"previous_status_reason"`
+}
+
+type PolicyVersionSearch struct {
+	PolicyVersionID int32       `json:"policy_version_id"`
+	Document        interface{} `json:"document"`
+}
+
+type SyncJob struct {
+	ID             int64              `json:"id"`
+	PolicyName     string             `json:"policy_name"`
+	Version        string             `json:"version"`
+	Provider       string             `json:"provider"`
+	Request        []byte             `json:"request"`
+	Status         string             `json:"status"`
+	Attempts       int32              `json:"attempts"`
+	MaxAttempts    int32              `json:"max_attempts"`
+	Steps          []byte             `json:"steps"`
+	Result         []byte             `json:"result"`
+	LastError      []byte             `json:"last_error"`
+	NextRunAt      pgtype.Timestamptz `json:"next_run_at"`
+	LockedUntil    pgtype.Timestamptz `json:"locked_until"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	StartedAt      pgtype.Timestamptz `json:"started_at"`
+	FinishedAt     pgtype.Timestamptz `json:"finished_at"`
+	SourceRevision pgtype.Text        `json:"source_revision"`
 }
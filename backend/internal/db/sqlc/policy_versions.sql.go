@@ -11,47 +11,146 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const bulkGetPolicyVersionsByNames = `-- name: BulkGetPolicyVersionsByNames :many
+const bulkGetPolicyVersionsByRanges = `-- name: BulkGetPolicyVersionsByRanges :many
 
-SELECT id, policy_name, version, is_latest, display_name, provider, description, categories, tags, logo_path, banner_path, supported_platforms, release_date, definition_yaml, icon_path, source_type, download_url, created_at, updated_at, major_version, minor_version, patch_version FROM policy_version
-WHERE policy_name = ANY($1::text[])
+SELECT DISTINCT ON (req.request_index) req.request_index::int AS request_index, pv.id, pv.policy_name, pv.version, pv.is_latest, pv.display_name, pv.provider, pv.description, pv.categories, pv.tags, pv.logo_path, pv.banner_path, pv.supported_platforms, pv.release_date, pv.definition_yaml, pv.icon_path, pv.source_type, pv.download_url, pv.created_at, pv.updated_at, pv.status, pv.status_reason, pv.replacement_version, pv.status_updated_at, pv.major_version, pv.minor_version, pv.patch_version, pv.prerelease, pv.prerelease_key, pv.definition_digest, pv.artifact_digest, pv.signature_status, pv.signature_key_id, pv.signature, pv.signature_verified_at, pv.bundle_id, pv.previous_status, pv.previous_status_reason
+FROM unnest(
+    $1::int[],
+    $2::text[],
+    $3::int[],
+    $4::int[],
+    $5::int[],
+    $6::text[],
+    $7::bool[],
+    $8::int[],
+    $9::int[],
+    $10::int[],
+    $11::text[],
+    $12::bool[],
+    $13::bool[],
+    $14::bool[],
+    $15::bool[]
+  ) AS req(request_index, policy_name, lower_major, lower_minor, lower_patch, lower_prerelease, lower_inclusive,
+           upper_major, upper_minor, upper_patch, upper_prerelease, upper_inclusive, allow_yanked, include_prerelease,
+           verified_only)
+JOIN policy_version pv
+  ON pv.policy_name = req.policy_name
+WHERE (pv.status <> 'yanked' OR req.allow_yanked)
+  AND (pv.signature_status = 'verified' OR NOT req.verified_only)
+  AND ((pv.major_version, pv.minor_version, pv.patch_version, pv.prerelease_key)
+         > (req.lower_major, req.lower_minor, req.lower_patch, semver_prerelease_key(req.lower_prerelease))
+    OR (req.lower_inclusive AND (pv.major_version, pv.minor_version, pv.patch_version, pv.prerelease_key)
+         = (req.lower_major, req.lower_minor, req.lower_patch, semver_prerelease_key(req.lower_prerelease))))
+  AND ((pv.major_version, pv.minor_version, pv.patch_version, pv.prerelease_key)
+         < (req.upper_major, req.upper_minor, req.upper_patch, semver_prerelease_key(req.upper_prerelease))
+    OR (req.upper_inclusive AND (pv.major_version, pv.minor_version, pv.patch_version, pv.prerelease_key)
+         = (req.upper_major, req.upper_minor, req.upper_patch, semver_prerelease_key(req.upper_prerelease))))
+  AND (pv.prerelease IS NULL
+    OR req.include_prerelease
+    OR (req.lower_prerelease <> '' AND (pv.major_version, pv.minor_version, pv.patch_version) = (req.lower_major, req.lower_minor, req.lower_patch))
+    OR (req.upper_prerelease <> '' AND (pv.major_version, pv.minor_version, pv.patch_version) = (req.upper_major, req.upper_minor, req.upper_patch)))
+ORDER BY req.request_index, pv.major_version DESC, pv.minor_version DESC, pv.patch_version DESC, pv.prerelease_key DESC
 `
 
+type BulkGetPolicyVersionsByRangesParams struct {
+	RequestIndexes    []int32  `json:"request_indexes"`
+	PolicyNames       []string `json:"policy_names"`
+	LowerMajors       []int32  `json:"lower_majors"`
+	LowerMinors       []int32  `json:"lower_minors"`
+	LowerPatches      []int32  `json:"lower_patches"`
+	LowerPrereleases  []string `json:"lower_prereleases"`
+	LowerInclusive    []bool   `json:"lower_inclusive"`
+	UpperMajors       []int32  `json:"upper_majors"`
+	UpperMinors       []int32  `json:"upper_minors"`
+	UpperPatches      []int32  `json:"upper_patches"`
+	UpperPrereleases  []string `json:"upper_prereleases"`
+	UpperInclusive    []bool   `json:"upper_inclusive"`
+	AllowYanked       []bool   `json:"allow_yanked"`
+	IncludePrerelease []bool   `json:"include_prerelease"`
+	VerifiedOnly      []bool   `json:"verified_only"`
+}
+
+type BulkGetPolicyVersionsByRangesRow struct {
+	RequestIndex  int32         `json:"request_index"`
+	PolicyVersion PolicyVersion `json:"policy_version"`
+}
+
 // =============================================================================
 // BULK STRATEGY-BASED POLICY RETRIEVAL
 // =============================================================================
-func (q *Queries) BulkGetPolicyVersionsByNames(ctx context.Context, dollar_1 []string) ([]PolicyVersion, error) {
-	rows, err := q.db.Query(ctx, bulkGetPolicyVersionsByNames, dollar_1)
+// Resolves many version range requests in one round trip. Each unnested row is
+// one range of one request (a constraint with "||" contributes several rows for
+// the same request_index); DISTINCT ON keeps the highest matching version per
+// request. Bounds compare (major, minor, patch, prerelease_key) so pre-releases
+// order below their release; an empty bound pre-release means a release bound.
+// Unbounded ends are passed as -1.0.0 / max int bounds. Yanked versions only
+// match requests that pin an exact version, and pre-releases only match when the
+// request opts in or a bound is a pre-release of the same major.minor.patch.
+// verified_only restricts a request to releases with a verified signature.
+func (q *Queries) BulkGetPolicyVersionsByRanges(ctx context.Context, arg BulkGetPolicyVersionsByRangesParams) ([]BulkGetPolicyVersionsByRangesRow, error) {
+	rows, err := q.db.Query(ctx, bulkGetPolicyVersionsByRanges,
+		arg.RequestIndexes,
+		arg.PolicyNames,
+		arg.LowerMajors,
+		arg.LowerMinors,
+		arg.LowerPatches,
+		arg.LowerPrereleases,
+		arg.LowerInclusive,
+		arg.UpperMajors,
+		arg.UpperMinors,
+		arg.UpperPatches,
+		arg.UpperPrereleases,
+		arg.UpperInclusive,
+		arg.AllowYanked,
+		arg.IncludePrerelease,
+		arg.VerifiedOnly,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []PolicyVersion{}
+	items := []BulkGetPolicyVersionsByRangesRow{}
 	for rows.Next() {
-		var i PolicyVersion
+		var i BulkGetPolicyVersionsByRangesRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.PolicyName,
-			&i.Version,
-			&i.IsLatest,
-			&i.DisplayName,
-			&i.Provider,
-			&i.Description,
-			&i.Categories,
-			&i.Tags,
-			&i.LogoPath,
-			&i.BannerPath,
-			&i.SupportedPlatforms,
-			&i.ReleaseDate,
-			&i.DefinitionYaml,
-			&i.IconPath,
-			&i.SourceType,
-			&i.DownloadUrl,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.MajorVersion,
-			&i.MinorVersion,
-			&i.PatchVersion,
+			&i.RequestIndex,
+			&i.PolicyVersion.ID,
+			&i.PolicyVersion.PolicyName,
+			&i.PolicyVersion.Version,
+			&i.PolicyVersion.IsLatest,
+			&i.PolicyVersion.DisplayName,
+			&i.PolicyVersion.Provider,
+			&i.PolicyVersion.Description,
+			&i.PolicyVersion.Categories,
+			&i.PolicyVersion.Tags,
+			&i.PolicyVersion.LogoPath,
+			&i.PolicyVersion.BannerPath,
+			&i.PolicyVersion.SupportedPlatforms,
+			&i.PolicyVersion.ReleaseDate,
+			&i.PolicyVersion.DefinitionYaml,
+			&i.PolicyVersion.IconPath,
+			&i.PolicyVersion.SourceType,
+			&i.PolicyVersion.DownloadUrl,
+			&i.PolicyVersion.CreatedAt,
+			&i.PolicyVersion.UpdatedAt,
+			&i.PolicyVersion.Status,
+			&i.PolicyVersion.StatusReason,
+			&i.PolicyVersion.ReplacementVersion,
+			&i.PolicyVersion.StatusUpdatedAt,
+			&i.PolicyVersion.MajorVersion,
+			&i.PolicyVersion.MinorVersion,
+			&i.PolicyVersion.PatchVersion,
+			&i.PolicyVersion.Prerelease,
+			&i.PolicyVersion.PrereleaseKey,
+			&i.PolicyVersion.DefinitionDigest,
+			&i.PolicyVersion.ArtifactDigest,
+			&i.PolicyVersion.SignatureStatus,
+			&i.PolicyVersion.SignatureKeyID,
+			&i.PolicyVersion.Signature,
+			&i.PolicyVersion.SignatureVerifiedAt,
+			&i.PolicyVersion.BundleID,
+			&i.PolicyVersion.PreviousStatus,
+			&i.PolicyVersion.PreviousStatusReason,
 		); err != nil {
 			return nil, err
 		}
@@ -63,31 +162,15 @@ func (q *Queries) BulkGetPolicyVersionsByNames(ctx context.Context, dollar_1 []s
 	return items, nil
 }
 
-const countPoliciesByMultiple = `-- name: CountPoliciesByMultiple :one
-SELECT COUNT(DISTINCT pv.policy_name) FROM policy_version pv
-WHERE ($1::text = '' OR LOWER(pv.display_name) LIKE LOWER('%' || $1 || '%') OR LOWER(pv.description) LIKE LOWER('%' || $1 || '%'))
-    AND ($2::text[] IS NULL OR array_length($2::text[], 1) = 0 OR EXISTS (SELECT 1 FROM unnest($2::text[]) AS cat WHERE pv.categories ? cat))
-    AND ($3::text[] IS NULL OR array_length($3::text[], 1) = 0 OR pv.provider = ANY($3::text[]))
-    AND ($4::text[] IS NULL or array_length($4::text[], 1) = 0 OR EXISTS (SELECT 1 FROM unnest($4::text[]) AS plat WHERE pv.supported_platforms ? plat))
+const clearLatestVersion = `-- name: ClearLatestVersion :exec
+UPDATE policy_version
+SET is_latest = FALSE
+WHERE policy_name = $1 AND is_latest = TRUE
 `
 
-type CountPoliciesByMultipleParams struct {
-	Column1 string   `json:"column_1"`
-	Column2 []string `json:"column_2"`
-	Column3 []string `json:"column_3"`
-	Column4 []string `json:"column_4"`
-}
-
-func (q *Queries) CountPoliciesByMultiple(ctx context.Context, arg CountPoliciesByMultipleParams) (int64, error) {
-	row := q.db.QueryRow(ctx, countPoliciesByMultiple,
-		arg.Column1,
-		arg.Column2,
-		arg.Column3,
-		arg.Column4,
-	)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
+func (q *Queries) ClearLatestVersion(ctx context.Context, policyName string) error {
+	_, err := q.db.Exec(ctx, clearLatestVersion, policyName)
+	return err
 }
 
 const countPolicyVersions = `-- name: CountPolicyVersions :one
@@ -103,48 +186,169 @@ func (q *Queries) CountPolicyVersions(ctx context.Context, policyName string) (i
 }
 
 const filterPoliciesByMultiple = `-- name: FilterPoliciesByMultiple :many
-WITH ranked_versions AS (
+WITH candidates AS (
+    SELECT policy_version_id, search_rank, category_match, provider_match, platform_match, tag_match
+    FROM policy_listing_candidates(
+        $1::text,
+        $2::text[], $3::boolean,
+        $4::text[],
+        $5::text[], $6::boolean,
+        $7::text[], $8::boolean,
+        $9::date, $10::date
+    )
+),
+ranked_versions AS (
     SELECT 
-        pv.id, pv.policy_name, pv.version, pv.is_latest, pv.display_name, pv.provider, pv.description, pv.categories, pv.tags, pv.logo_path, pv.banner_path, pv.supported_platforms, pv.release_date, pv.definition_yaml, pv.icon_path, pv.source_type, pv.download_url, pv.created_at, pv.updated_at, pv.major_version, pv.minor_version, pv.patch_version,
+        pv.id, pv.policy_name, pv.version, pv.is_latest, pv.display_name, pv.provider, pv.description, pv.categories, pv.tags, pv.logo_path, pv.banner_path, pv.supported_platforms, pv.release_date, pv.definition_yaml, pv.icon_path, pv.source_type, pv.download_url, pv.created_at, pv.updated_at, pv.status, pv.status_reason, pv.replacement_version, pv.status_updated_at, pv.major_version, pv.minor_version, pv.patch_version, pv.prerelease, pv.prerelease_key, pv.definition_digest, pv.artifact_digest, pv.signature_status, pv.signature_key_id, pv.signature, pv.signature_verified_at, pv.bundle_id, pv.previous_status, pv.previous_status_reason,
+        c.search_rank,
+        coalesce(pu.download_count + pu.resolve_count, 0)::bigint AS popularity,
         ROW_NUMBER() OVER (
             PARTITION BY pv.policy_name 
             ORDER BY 
                 pv.is_latest DESC,
+                (pv.prerelease IS NULL) DESC,
+                pv.major_version DESC NULLS LAST,
+                pv.minor_version DESC,
+                pv.patch_version DESC,
+                pv.prerelease_key DESC,
                 pv.created_at DESC
         ) as version_rank
-    FROM policy_version pv
-    WHERE ($1::text = '' OR LOWER(pv.display_name) LIKE LOWER('%' || $1 || '%') OR LOWER(pv.description) LIKE LOWER('%' || $1 || '%'))
-        AND ($2::text[] IS NULL OR array_length($2::text[], 1) = 0 OR EXISTS (SELECT 1 FROM unnest($2::text[]) AS cat WHERE pv.categories ? cat))
-        AND ($3::text[] IS NULL OR array_length($3::text[], 1) = 0 OR pv.provider = ANY($3::text[]))
-        AND ($4::text[] IS NULL OR array_length($4::text[], 1) = 0 OR EXISTS (SELECT 1 FROM unnest($4::text[]) AS plat WHERE pv.supported_platforms ? plat))
+    FROM candidates c
+    JOIN policy_version pv ON pv.id = c.policy_version_id
+    LEFT JOIN policy_usage pu ON pu.policy_name = pv.policy_name
+    WHERE c.category_match AND c.provider_match AND c.platform_match AND c.tag_match
+),
+keyed_versions AS (
+    SELECT
+        id, policy_name, version, is_latest, display_name, provider, description, categories, tags, logo_path, banner_path, supported_platforms, release_date, definition_yaml, icon_path, source_type, download_url, created_at, updated_at, status, status_reason, replacement_version, status_updated_at, major_version, minor_version, patch_version, prerelease, prerelease_key, definition_digest, artifact_digest, signature_status, signature_key_id, signature, signature_verified_at, bundle_id, previous_status, previous_status_reason, search_rank, popularity, version_rank,
+        coalesce(CASE $11::text
+            WHEN 'name' THEN lower(policy_name)
+            WHEN 'displayName' THEN lower(display_name)
+            WHEN 'provider' THEN lower(provider)
+            WHEN 'releaseDate' THEN coalesce(to_char(release_date, 'YYYY-MM-DD'), CASE WHEN $12::text = 'asc' THEN '~' ELSE '' END)
+            WHEN 'updated' THEN to_char(updated_at AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS.US')
+            WHEN 'popularity' THEN lpad(popularity::text, 20, '0')
+            WHEN 'relevance' THEN to_char(search_rank, 'FM000000.000000000')
+            ELSE to_char(created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS.US')
+        END, '') COLLATE "C" AS sort_key
+    FROM ranked_versions
+    WHERE version_rank = 1
+),
+walked_versions AS (
+    SELECT
+        id, policy_name, version, is_latest, display_name, provider, description, categories, tags, logo_path, banner_path, supported_platforms, release_date, definition_yaml, icon_path, source_type, download_url, created_at, updated_at, status, status_reason, replacement_version, status_updated_at, major_version, minor_version, patch_version, prerelease, prerelease_key, definition_digest, artifact_digest, signature_status, signature_key_id, signature, signature_verified_at, bundle_id, previous_status, previous_status_reason, search_rank, popularity, version_rank, sort_key,
+        ROW_NUMBER() OVER (
+            ORDER BY
+                CASE WHEN $13::boolean THEN sort_key END ASC,
+                CASE WHEN NOT $13::boolean THEN sort_key END DESC,
+                CASE WHEN $14::boolean THEN created_at END ASC,
+                CASE WHEN NOT $14::boolean THEN created_at END DESC,
+                CASE WHEN $14::boolean THEN policy_name END DESC,
+                CASE WHEN NOT $14::boolean THEN policy_name END ASC
+        ) AS walk_position
+    FROM keyed_versions
+    WHERE $15::text IS NULL
+        OR ($13::boolean AND sort_key > $15::text)
+        OR (NOT $13::boolean AND sort_key < $15::text)
+        OR (sort_key = $15::text AND CASE WHEN $14::boolean
+            THEN created_at > $16::timestamptz OR (created_at = $16::timestamptz AND policy_name < $17::text)
+            ELSE created_at < $16::timestamptz OR (created_at = $16::timestamptz AND policy_name > $17::text)
+        END)
+),
+page_versions AS (
+    SELECT id, policy_name, version, is_latest, display_name, provider, description, categories, tags, logo_path, banner_path, supported_platforms, release_date, definition_yaml, icon_path, source_type, download_url, created_at, updated_at, status, status_reason, replacement_version, status_updated_at, major_version, minor_version, patch_version, prerelease, prerelease_key, definition_digest, artifact_digest, signature_status, signature_key_id, signature, signature_verified_at, bundle_id, previous_status, previous_status_reason, search_rank, popularity, version_rank, sort_key, walk_position FROM walked_versions
+    ORDER BY walk_position
+    LIMIT $18::int OFFSET $19::int
+),
+matching AS (
+    SELECT
+        pv.policy_name, pv.provider, pv.categories, pv.supported_platforms, pv.tags,
+        c.category_match, c.provider_match, c.platform_match, c.tag_match
+    FROM candidates c
+    JOIN policy_version pv ON pv.id = c.policy_version_id
+),
+facet_values AS (
+    SELECT 'category'::text AS facet, category.value::text AS value, COUNT(DISTINCT m.policy_name) AS policy_count
+    FROM matching m, jsonb_array_elements_text(CASE WHEN jsonb_typeof(m.categories) = 'array' THEN m.categories END) AS category(value)
+    WHERE 'category' = ANY($20::text[]) AND m.provider_match AND m.platform_match AND m.tag_match
+    GROUP BY category.value
+    UNION ALL
+    SELECT 'provider', m.provider, COUNT(DISTINCT m.policy_name)
+    FROM matching m
+    WHERE 'provider' = ANY($20::text[]) AND m.category_match AND m.platform_match AND m.tag_match
+    GROUP BY m.provider
+    UNION ALL
+    SELECT 'platform', platform.value, COUNT(DISTINCT m.policy_name)
+    FROM matching m, jsonb_array_elements_text(CASE WHEN jsonb_typeof(m.supported_platforms) = 'array' THEN m.supported_platforms END) AS platform(value)
+    WHERE 'platform' = ANY($20::text[]) AND m.category_match AND m.provider_match AND m.tag_match
+    GROUP BY platform.value
+    UNION ALL
+    SELECT 'tag', tag.value, COUNT(DISTINCT m.policy_name)
+    FROM matching m, jsonb_array_elements_text(CASE WHEN jsonb_typeof(m.tags) = 'array' THEN m.tags END) AS tag(value)
+    WHERE 'tag' = ANY($20::text[]) AND m.category_match AND m.provider_match AND m.platform_match
+    GROUP BY tag.value
+),
+listing_counts AS (
+    SELECT
+        CASE WHEN $21::boolean THEN (SELECT COUNT(*) FROM keyed_versions) END AS total_count,
+        CASE WHEN cardinality($20::text[]) > 0 THEN coalesce((
+            SELECT jsonb_agg(jsonb_build_object('facet', facet, 'value', value, 'count', policy_count) ORDER BY facet, policy_count DESC, value)
+            FROM facet_values
+        ), '[]'::jsonb) END AS facet_counts
 )
 SELECT 
-    id, policy_name, version, is_latest, display_name, provider, description, 
-    categories, tags, logo_path, banner_path, supported_platforms, 
-    release_date, definition_yaml, icon_path, source_type, download_url, 
-    created_at, updated_at
-FROM ranked_versions 
-WHERE version_rank = 1
-ORDER BY created_at DESC
-LIMIT $5 OFFSET $6
+    p.id, p.policy_name, p.version, p.is_latest, p.display_name, p.provider, p.description, 
+    p.categories, p.tags, p.logo_path, p.banner_path, p.supported_platforms, 
+    p.release_date, p.definition_yaml, p.icon_path, p.source_type, p.download_url, 
+    p.created_at, p.updated_at, p.status, p.status_reason, p.replacement_version,
+    p.definition_digest, p.artifact_digest, p.signature_status, p.signature_key_id,
+    p.sort_key::text AS sort_key,
+    (CASE WHEN $1::text = '' OR p.id IS NULL THEN '' ELSE ts_headline(
+        'english',
+        coalesce(p.description, '') || E'\n' || coalesce((
+            SELECT string_agg(d.content_md, E'\n' ORDER BY d.page) FROM policy_docs d WHERE d.policy_version_id = p.id
+        ), ''),
+        policy_search_query($1::text),
+        'StartSel=<mark>, StopSel=</mark>, MinWords=15, MaxWords=35, MaxFragments=2, FragmentDelimiter=" ... "'
+    ) END)::text AS search_snippet,
+    l.total_count::bigint AS total_count,
+    l.facet_counts
+FROM listing_counts l
+LEFT JOIN page_versions p ON TRUE
+ORDER BY p.walk_position
 `
 
 type FilterPoliciesByMultipleParams struct {
-	Column1 string   `json:"column_1"`
-	Column2 []string `json:"column_2"`
-	Column3 []string `json:"column_3"`
-	Column4 []string `json:"column_4"`
-	Limit   int32    `json:"limit"`
-	Offset  int32    `json:"offset"`
+	Search             string             `json:"search"`
+	Categories         []string           `json:"categories"`
+	MatchAllCategories bool               `json:"match_all_categories"`
+	Providers          []string           `json:"providers"`
+	Platforms          []string           `json:"platforms"`
+	MatchAllPlatforms  bool               `json:"match_all_platforms"`
+	Tags               []string           `json:"tags"`
+	MatchAllTags       bool               `json:"match_all_tags"`
+	ReleasedAfter      pgtype.Date        `json:"released_after"`
+	ReleasedBefore     pgtype.Date        `json:"released_before"`
+	SortBy             string             `json:"sort_by"`
+	SortOrder          string             `json:"sort_order"`
+	KeyAscending       bool               `json:"key_ascending"`
+	WalkBackward       bool               `json:"walk_backward"`
+	CursorKey          pgtype.Text        `json:"cursor_key"`
+	CursorCreated      pgtype.Timestamptz `json:"cursor_created"`
+	CursorName         pgtype.Text        `json:"cursor_name"`
+	RowLimit           int32              `json:"row_limit"`
+	RowOffset          int32              `json:"row_offset"`
+	Facets             []string           `json:"facets"`
+	IncludeTotal       bool               `json:"include_total"`
 }
 
 type FilterPoliciesByMultipleRow struct {
-	ID                 int32              `json:"id"`
-	PolicyName         string             `json:"policy_name"`
-	Version            string             `json:"version"`
+	ID                 pgtype.Int4        `json:"id"`
+	PolicyName         pgtype.Text        `json:"policy_name"`
+	Version            pgtype.Text        `json:"version"`
 	IsLatest           pgtype.Bool        `json:"is_latest"`
-	DisplayName        string             `json:"display_name"`
-	Provider           string             `json:"provider"`
+	DisplayName        pgtype.Text        `json:"display_name"`
+	Provider           pgtype.Text        `json:"provider"`
 	Description        pgtype.Text        `json:"description"`
 	Categories         []byte             `json:"categories"`
 	Tags               []byte             `json:"tags"`
@@ -152,22 +356,67 @@ type FilterPoliciesByMultipleRow struct {
 	BannerPath         pgtype.Text        `json:"banner_path"`
 	SupportedPlatforms []byte             `json:"supported_platforms"`
 	ReleaseDate        pgtype.Date        `json:"release_date"`
-	DefinitionYaml     string             `json:"definition_yaml"`
+	DefinitionYaml     pgtype.Text        `json:"definition_yaml"`
 	IconPath           pgtype.Text        `json:"icon_path"`
 	SourceType         pgtype.Text        `json:"source_type"`
 	DownloadUrl        pgtype.Text        `json:"download_url"`
 	CreatedAt          pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
+	Status             pgtype.Text        `json:"status"`
+	StatusReason       pgtype.Text        `json:"status_reason"`
+	ReplacementVersion pgtype.Text        `json:"replacement_version"`
+	DefinitionDigest   pgtype.Text        `json:"definition_digest"`
+	ArtifactDigest     pgtype.Text        `json:"artifact_digest"`
+	SignatureStatus    pgtype.Text        `json:"signature_status"`
+	SignatureKeyID     pgtype.Text        `json:"signature_key_id"`
+	SortKey            pgtype.Text        `json:"sort_key"`
+	SearchSnippet      string             `json:"search_snippet"`
+	TotalCount         pgtype.Int8        `json:"total_count"`
+	FacetCounts        []byte             `json:"facet_counts"`
 }
 
+// Lists the best matching version of each policy, sorted by sort_key (the
+// value of the sort_by key as text that collates in its order) in sort_order,
+// then newest and by name, so every policy has a stable position. The page is
+// read walking the listing from a cursor: key_ascending is the direction of
+// the walk over sort_key, and walk_backward is set when walking towards the
+// start, in which case the caller restores the listing order. Without a
+// cursor, row_offset skips rows instead. The cursor is only applied once
+// every matching version is ranked: a policy's sort_key is that of its best
+// matching version, and filtering versions by it first could change which
+// version is best. With a search, search_snippet wraps the matches in <mark>
+// tags.
+//
+// The counts are read by the same statement, so they agree with the page.
+// With include_total, total_count counts the listed policies. facet_counts
+// counts them per value of each facet named in facets, as an array of
+// {"facet", "value", "count"} objects, most common first; a facet's own
+// filter is left out of its counts, so each count is what selecting only that
+// value would list. Counts that are not requested are not computed. Every
+// row carries the counts, and an empty page is a single row without a policy.
 func (q *Queries) FilterPoliciesByMultiple(ctx context.Context, arg FilterPoliciesByMultipleParams) ([]FilterPoliciesByMultipleRow, error) {
 	rows, err := q.db.Query(ctx, filterPoliciesByMultiple,
-		arg.Column1,
-		arg.Column2,
-		arg.Column3,
-		arg.Column4,
-		arg.Limit,
-		arg.Offset,
+		arg.Search,
+		arg.Categories,
+		arg.MatchAllCategories,
+		arg.Providers,
+		arg.Platforms,
+		arg.MatchAllPlatforms,
+		arg.Tags,
+		arg.MatchAllTags,
+		arg.ReleasedAfter,
+		arg.ReleasedBefore,
+		arg.SortBy,
+		arg.SortOrder,
+		arg.KeyAscending,
+		arg.WalkBackward,
+		arg.CursorKey,
+		arg.CursorCreated,
+		arg.CursorName,
+		arg.RowLimit,
+		arg.RowOffset,
+		arg.Facets,
+		arg.IncludeTotal,
 	)
 	if err != nil {
 		return nil, err
@@ -196,6 +445,17 @@ func (q *Queries) FilterPoliciesByMultiple(ctx context.Context, arg FilterPolici
 			&i.DownloadUrl,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Status,
+			&i.StatusReason,
+			&i.ReplacementVersion,
+			&i.DefinitionDigest,
+			&i.ArtifactDigest,
+			&i.SignatureStatus,
+			&i.SignatureKeyID,
+			&i.SortKey,
+			&i.SearchSnippet,
+			&i.TotalCount,
+			&i.FacetCounts,
 		); err != nil {
 			return nil, err
 		}
@@ -292,8 +552,33 @@ func (q *Queries) GetDistinctProviders(ctx context.Context) ([]string, error) {
 	return items, nil
 }
 
+const getExistingPolicyNames = `-- name: GetExistingPolicyNames :many
+SELECT DISTINCT policy_name FROM policy_version
+WHERE policy_name = ANY($1::text[])
+`
+
+func (q *Queries) GetExistingPolicyNames(ctx context.Context, policyNames []string) ([]string, error) {
+	rows, err := q.db.Query(ctx, getExistingPolicyNames, policyNames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var policy_name string
+		if err := rows.Scan(&policy_name); err != nil {
+			return nil, err
+		}
+		items = append(items, policy_name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getLatestPolicyVersion = `-- name: GetLatestPolicyVersion :one
-SELECT id, policy_name, version, is_latest, display_name, provider, description, categories, tags, logo_path, banner_path, supported_platforms, release_date, definition_yaml, icon_path, source_type, download_url, created_at, updated_at, major_version, minor_version, patch_version FROM policy_version
+SELECT id, policy_name, version, is_latest, display_name, provider, description, categories, tags, logo_path, banner_path, supported_platforms, release_date, definition_yaml, icon_path, source_type, download_url, created_at, updated_at, status, status_reason, replacement_version, status_updated_at, major_version, minor_version, patch_version, prerelease, prerelease_key, definition_digest, artifact_digest, signature_status, signature_key_id, signature, signature_verified_at, bundle_id, previous_status, previous_status_reason FROM policy_version
 WHERE policy_name = $1 AND is_latest = TRUE
 `
 
@@ -320,17 +605,47 @@ func (q *Queries) GetLatestPolicyVersion(ctx context.Context, policyName string)
 		&i.DownloadUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.StatusReason,
+		&i.ReplacementVersion,
+		&i.StatusUpdatedAt,
 		&i.MajorVersion,
 		&i.MinorVersion,
 		&i.PatchVersion,
+		&i.Prerelease,
+		&i.PrereleaseKey,
+		&i.DefinitionDigest,
+		&i.ArtifactDigest,
+		&i.SignatureStatus,
+		&i.SignatureKeyID,
+		&i.Signature,
+		&i.SignatureVerifiedAt,
+		&i.BundleID,
+		&i.PreviousStatus,
+		&i.PreviousStatusReason,
 	)
 	return i, err
 }
 
+const getPolicyProvider = `-- name: GetPolicyProvider :one
+SELECT provider FROM policy_version
+WHERE policy_name = $1
+ORDER BY created_at ASC, id ASC
+LIMIT 1
+`
+
+// Returns the provider that first published the policy; later versions must match it.
+func (q *Queries) GetPolicyProvider(ctx context.Context, policyName string) (string, error) {
+	row := q.db.QueryRow(ctx, getPolicyProvider, policyName)
+	var provider string
+	err := row.Scan(&provider)
+	return provider, err
+}
+
 const getPolicyVersion = `-- name: GetPolicyVersion :one
 
 
-SELECT id, policy_name, version, is_latest, display_name, provider, description, categories, tags, logo_path, banner_path, supported_platforms, release_date, definition_yaml, icon_path, source_type, download_url, created_at, updated_at, major_version, minor_version, patch_version FROM policy_version
+SELECT id, policy_name, version, is_latest, display_name, provider, description, categories, tags, logo_path, banner_path, supported_platforms, release_date, definition_yaml, icon_path, source_type, download_url, created_at, updated_at, status, status_reason, replacement_version, status_updated_at, major_version, minor_version, patch_version, prerelease, prerelease_key, definition_digest, artifact_digest, signature_status, signature_key_id, signature, signature_verified_at, bundle_id, previous_status, previous_status_reason FROM policy_version
 WHERE policy_name = $1 AND version = $2
 `
 
@@ -366,16 +681,31 @@ func (q *Queries) GetPolicyVersion(ctx context.Context, arg GetPolicyVersionPara
 		&i.DownloadUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.StatusReason,
+		&i.ReplacementVersion,
+		&i.StatusUpdatedAt,
 		&i.MajorVersion,
 		&i.MinorVersion,
 		&i.PatchVersion,
+		&i.Prerelease,
+		&i.PrereleaseKey,
+		&i.DefinitionDigest,
+		&i.ArtifactDigest,
+		&i.SignatureStatus,
+		&i.SignatureKeyID,
+		&i.Signature,
+		&i.SignatureVerifiedAt,
+		&i.BundleID,
+		&i.PreviousStatus,
+		&i.PreviousStatusReason,
 	)
 	return i, err
 }
 
 const getPolicyVersionByExact = `-- name: GetPolicyVersionByExact :one
 
-SELECT id, policy_name, version, is_latest, display_name, provider, description, categories, tags, logo_path, banner_path, supported_platforms, release_date, definition_yaml, icon_path, source_type, download_url, created_at, updated_at, major_version, minor_version, patch_version FROM policy_version
+SELECT id, policy_name, version, is_latest, display_name, provider, description, categories, tags, logo_path, banner_path, supported_platforms, release_date, definition_yaml, icon_path, source_type, download_url, created_at, updated_at, status, status_reason, replacement_version, status_updated_at, major_version, minor_version, patch_version, prerelease, prerelease_key, definition_digest, artifact_digest, signature_status, signature_key_id, signature, signature_verified_at, bundle_id, previous_status, previous_status_reason FROM policy_version
 WHERE policy_name = $1 AND version = $2
 `
 
@@ -410,16 +740,33 @@ func (q *Queries) GetPolicyVersionByExact(ctx context.Context, arg GetPolicyVers
 		&i.DownloadUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.StatusReason,
+		&i.ReplacementVersion,
+		&i.StatusUpdatedAt,
 		&i.MajorVersion,
 		&i.MinorVersion,
 		&i.PatchVersion,
+		&i.Prerelease,
+		&i.PrereleaseKey,
+		&i.DefinitionDigest,
+		&i.ArtifactDigest,
+		&i.SignatureStatus,
+		&i.SignatureKeyID,
+		&i.Signature,
+		&i.SignatureVerifiedAt,
+		&i.BundleID,
+		&i.PreviousStatus,
+		&i.PreviousStatusReason,
 	)
 	return i, err
 }
 
 const getPolicyVersionByLatestMajor = `-- name: GetPolicyVersionByLatestMajor :one
-SELECT id, policy_name, version, is_latest, display_name, provider, description, categories, tags, logo_path, banner_path, supported_platforms, release_date, definition_yaml, icon_path, source_type, download_url, created_at, updated_at, major_version, minor_version, patch_version FROM policy_version
+SELECT id, policy_name, version, is_latest, display_name, provider, description, categories, tags, logo_path, banner_path, supported_platforms, release_date, definition_yaml, icon_path, source_type, download_url, created_at, updated_at, status, status_reason, replacement_version, status_updated_at, major_version, minor_version, patch_version, prerelease, prerelease_key, definition_digest, artifact_digest, signature_status, signature_key_id, signature, signature_verified_at, bundle_id, previous_status, previous_status_reason FROM policy_version
 WHERE policy_name = $1
+  AND status <> 'yanked'
+  AND prerelease IS NULL
 ORDER BY major_version DESC, minor_version DESC, patch_version DESC
 LIMIT 1
 `
@@ -447,17 +794,34 @@ func (q *Queries) GetPolicyVersionByLatestMajor(ctx context.Context, policyName
 		&i.DownloadUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.StatusReason,
+		&i.ReplacementVersion,
+		&i.StatusUpdatedAt,
 		&i.MajorVersion,
 		&i.MinorVersion,
 		&i.PatchVersion,
+		&i.Prerelease,
+		&i.PrereleaseKey,
+		&i.DefinitionDigest,
+		&i.ArtifactDigest,
+		&i.SignatureStatus,
+		&i.SignatureKeyID,
+		&i.Signature,
+		&i.SignatureVerifiedAt,
+		&i.BundleID,
+		&i.PreviousStatus,
+		&i.PreviousStatusReason,
 	)
 	return i, err
 }
 
 const getPolicyVersionByLatestMinor = `-- name: GetPolicyVersionByLatestMinor :one
-SELECT id, policy_name, version, is_latest, display_name, provider, description, categories, tags, logo_path, banner_path, supported_platforms, release_date, definition_yaml, icon_path, source_type, download_url, created_at, updated_at, major_version, minor_version, patch_version FROM policy_version
+SELECT id, policy_name, version, is_latest, display_name, provider, description, categories, tags, logo_path, banner_path, supported_platforms, release_date, definition_yaml, icon_path, source_type, download_url, created_at, updated_at, status, status_reason, replacement_version, status_updated_at, major_version, minor_version, patch_version, prerelease, prerelease_key, definition_digest, artifact_digest, signature_status, signature_key_id, signature, signature_verified_at, bundle_id, previous_status, previous_status_reason FROM policy_version
 WHERE policy_name = $1 
   AND major_version = $2
+  AND status <> 'yanked'
+  AND prerelease IS NULL
 ORDER BY minor_version DESC, patch_version DESC
 LIMIT 1
 `
@@ -490,18 +854,35 @@ func (q *Queries) GetPolicyVersionByLatestMinor(ctx context.Context, arg GetPoli
 		&i.DownloadUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.StatusReason,
+		&i.ReplacementVersion,
+		&i.StatusUpdatedAt,
 		&i.MajorVersion,
 		&i.MinorVersion,
 		&i.PatchVersion,
+		&i.Prerelease,
+		&i.PrereleaseKey,
+		&i.DefinitionDigest,
+		&i.ArtifactDigest,
+		&i.SignatureStatus,
+		&i.SignatureKeyID,
+		&i.Signature,
+		&i.SignatureVerifiedAt,
+		&i.BundleID,
+		&i.PreviousStatus,
+		&i.PreviousStatusReason,
 	)
 	return i, err
 }
 
 const getPolicyVersionByLatestPatch = `-- name: GetPolicyVersionByLatestPatch :one
-SELECT id, policy_name, version, is_latest, display_name, provider, description, categories, tags, logo_path, banner_path, supported_platforms, release_date, definition_yaml, icon_path, source_type, download_url, created_at, updated_at, major_version, minor_version, patch_version FROM policy_version
+SELECT id, policy_name, version, is_latest, display_name, provider, description, categories, tags, logo_path, banner_path, supported_platforms, release_date, definition_yaml, icon_path, source_type, download_url, created_at, updated_at, status, status_reason, replacement_version, status_updated_at, major_version, minor_version, patch_version, prerelease, prerelease_key, definition_digest, artifact_digest, signature_status, signature_key_id, signature, signature_verified_at, bundle_id, previous_status, previous_status_reason FROM policy_version
 WHERE policy_name = $1 
   AND major_version = $2 
   AND minor_version = $3
+  AND status <> 'yanked'
+  AND prerelease IS NULL
 ORDER BY patch_version DESC
 LIMIT 1
 `
@@ -535,9 +916,24 @@ func (q *Queries) GetPolicyVersionByLatestPatch(ctx context.Context, arg GetPoli
 		&i.DownloadUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.StatusReason,
+		&i.ReplacementVersion,
+		&i.StatusUpdatedAt,
 		&i.MajorVersion,
 		&i.MinorVersion,
 		&i.PatchVersion,
+		&i.Prerelease,
+		&i.PrereleaseKey,
+		&i.DefinitionDigest,
+		&i.ArtifactDigest,
+		&i.SignatureStatus,
+		&i.SignatureKeyID,
+		&i.Signature,
+		&i.SignatureVerifiedAt,
+		&i.BundleID,
+		&i.PreviousStatus,
+		&i.PreviousStatusReason,
 	)
 	return i, err
 }
@@ -560,31 +956,45 @@ INSERT INTO policy_version (
     icon_path,
     source_type,
     download_url,
+    definition_digest,
+    artifact_digest,
+    signature_status,
+    signature_key_id,
+    signature,
+    signature_verified_at,
+    bundle_id,
     created_at,
     updated_at
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, NOW(), NOW()
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, NOW(), NOW()
 )
-RETURNING id, policy_name, version, is_latest, display_name, provider, description, categories, tags, logo_path, banner_path, supported_platforms, release_date, definition_yaml, icon_path, source_type, download_url, created_at, updated_at, major_version, minor_version, patch_version
+RETURNING id, policy_name, version, is_latest, display_name, provider, description, categories, tags, logo_path, banner_path, supported_platforms, release_date, definition_yaml, icon_path, source_type, download_url, created_at, updated_at, status, status_reason, replacement_version, status_updated_at, major_version, minor_version, patch_version, prerelease, prerelease_key, definition_digest, artifact_digest, signature_status, signature_key_id, signature, signature_verified_at, bundle_id, previous_status, previous_status_reason
 `
 
 type InsertPolicyVersionParams struct {
-	PolicyName         string      `json:"policy_name"`
-	Version            string      `json:"version"`
-	IsLatest           pgtype.Bool `json:"is_latest"`
-	DisplayName        string      `json:"display_name"`
-	Provider           string      `json:"provider"`
-	Description        pgtype.Text `json:"description"`
-	Categories         []byte      `json:"categories"`
-	Tags               []byte      `json:"tags"`
-	LogoPath           pgtype.Text `json:"logo_path"`
-	BannerPath         pgtype.Text `json:"banner_path"`
-	SupportedPlatforms []byte      `json:"supported_platforms"`
-	ReleaseDate        pgtype.Date `json:"release_date"`
-	DefinitionYaml     string      `json:"definition_yaml"`
-	IconPath           pgtype.Text `json:"icon_path"`
-	SourceType         pgtype.Text `json:"source_type"`
-	DownloadUrl        pgtype.Text `json:"download_url"`
+	PolicyName          string             `json:"policy_name"`
+	Version             string             `json:"version"`
+	IsLatest            pgtype.Bool        `json:"is_latest"`
+	DisplayName         string             `json:"display_name"`
+	Provider            string             `json:"provider"`
+	Description         pgtype.Text        `json:"description"`
+	Categories          []byte             `json:"categories"`
+	Tags                []byte             `json:"tags"`
+	LogoPath            pgtype.Text        `json:"logo_path"`
+	BannerPath          pgtype.Text        `json:"banner_path"`
+	SupportedPlatforms  []byte             `json:"supported_platforms"`
+	ReleaseDate         pgtype.Date        `json:"release_date"`
+	DefinitionYaml      string             `json:"definition_yaml"`
+	IconPath            pgtype.Text        `json:"icon_path"`
+	SourceType          pgtype.Text        `json:"source_type"`
+	DownloadUrl         pgtype.Text        `json:"download_url"`
+	DefinitionDigest    pgtype.Text        `json:"definition_digest"`
+	ArtifactDigest      pgtype.Text        `json:"artifact_digest"`
+	SignatureStatus     string             `json:"signature_status"`
+	SignatureKeyID      pgtype.Text        `json:"signature_key_id"`
+	Signature           pgtype.Text        `json:"signature"`
+	SignatureVerifiedAt pgtype.Timestamptz `json:"signature_verified_at"`
+	BundleID            pgtype.Int8        `json:"bundle_id"`
 }
 
 func (q *Queries) InsertPolicyVersion(ctx context.Context, arg InsertPolicyVersionParams) (PolicyVersion, error) {
@@ -605,6 +1015,13 @@ func (q *Queries) InsertPolicyVersion(ctx context.Context, arg InsertPolicyVersi
 		arg.IconPath,
 		arg.SourceType,
 		arg.DownloadUrl,
+		arg.DefinitionDigest,
+		arg.ArtifactDigest,
+		arg.SignatureStatus,
+		arg.SignatureKeyID,
+		arg.Signature,
+		arg.SignatureVerifiedAt,
+		arg.BundleID,
 	)
 	var i PolicyVersion
 	err := row.Scan(
@@ -627,18 +1044,67 @@ func (q *Queries) InsertPolicyVersion(ctx context.Context, arg InsertPolicyVersi
 		&i.DownloadUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.StatusReason,
+		&i.ReplacementVersion,
+		&i.StatusUpdatedAt,
 		&i.MajorVersion,
 		&i.MinorVersion,
 		&i.PatchVersion,
+		&i.Prerelease,
+		&i.PrereleaseKey,
+		&i.DefinitionDigest,
+		&i.ArtifactDigest,
+		&i.SignatureStatus,
+		&i.SignatureKeyID,
+		&i.Signature,
+		&i.SignatureVerifiedAt,
+		&i.BundleID,
+		&i.PreviousStatus,
+		&i.PreviousStatusReason,
 	)
 	return i, err
 }
 
+const listExistingVersions = `-- name: ListExistingVersions :many
+SELECT policy_name, version FROM policy_version
+WHERE policy_name = ANY($1::text[])
+`
+
+type ListExistingVersionsRow struct {
+	PolicyName string `json:"policy_name"`
+	Version    string `json:"version"`
+}
+
+// Lists the stored versions of the given policies, whatever their status
+func (q *Queries) ListExistingVersions(ctx context.Context, policyNames []string) ([]ListExistingVersionsRow, error) {
+	rows, err := q.db.Query(ctx, listExistingVersions, policyNames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListExistingVersionsRow{}
+	for rows.Next() {
+		var i ListExistingVersionsRow
+		if err := rows.Scan(
+			&i.PolicyName,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPolicyVersions = `-- name: ListPolicyVersions :many
 
-SELECT id, policy_name, version, is_latest, display_name, provider, description, categories, tags, logo_path, banner_path, supported_platforms, release_date, definition_yaml, icon_path, source_type, download_url, created_at, updated_at, major_version, minor_version, patch_version FROM policy_version
+SELECT id, policy_name, version, is_latest, display_name, provider, description, categories, tags, logo_path, banner_path, supported_platforms, release_date, definition_yaml, icon_path, source_type, download_url, created_at, updated_at, status, status_reason, replacement_version, status_updated_at, major_version, minor_version, patch_version, prerelease, prerelease_key, definition_digest, artifact_digest, signature_status, signature_key_id, signature, signature_verified_at, bundle_id, previous_status, previous_status_reason FROM policy_version
 WHERE policy_name = $1
-ORDER BY created_at DESC
+ORDER BY major_version DESC NULLS LAST, minor_version DESC, patch_version DESC, prerelease_key DESC, created_at DESC, id DESC
 LIMIT $2 OFFSET $3
 `
 
@@ -680,9 +1146,177 @@ func (q *Queries) ListPolicyVersions(ctx context.Context, arg ListPolicyVersions
 			&i.DownloadUrl,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Status,
+			&i.StatusReason,
+			&i.ReplacementVersion,
+			&i.StatusUpdatedAt,
+			&i.MajorVersion,
+			&i.MinorVersion,
+			&i.PatchVersion,
+			&i.Prerelease,
+			&i.PrereleaseKey,
+			&i.DefinitionDigest,
+			&i.ArtifactDigest,
+			&i.SignatureStatus,
+			&i.SignatureKeyID,
+			&i.Signature,
+			&i.SignatureVerifiedAt,
+			&i.BundleID,
+			&i.PreviousStatus,
+			&i.PreviousStatusReason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPolicyVersionsAfter = `-- name: ListPolicyVersionsAfter :many
+SELECT pv.id, pv.policy_name, pv.version, pv.is_latest, pv.display_name, pv.provider, pv.description, pv.categories, pv.tags, pv.logo_path, pv.banner_path, pv.supported_platforms, pv.release_date, pv.definition_yaml, pv.icon_path, pv.source_type, pv.download_url, pv.created_at, pv.updated_at, pv.status, pv.status_reason, pv.replacement_version, pv.status_updated_at, pv.major_version, pv.minor_version, pv.patch_version, pv.prerelease, pv.prerelease_key, pv.definition_digest, pv.artifact_digest, pv.signature_status, pv.signature_key_id, pv.signature, pv.signature_verified_at, pv.bundle_id, pv.previous_status, pv.previous_status_reason FROM policy_version pv
+WHERE pv.policy_name = $1
+    AND (coalesce(pv.major_version, -1), coalesce(pv.minor_version, -1), coalesce(pv.patch_version, -1), pv.prerelease_key, pv.created_at, pv.id) < (
+        SELECT coalesce(b.major_version, -1), coalesce(b.minor_version, -1), coalesce(b.patch_version, -1), b.prerelease_key, b.created_at, b.id
+        FROM policy_version b WHERE b.id = $2
+    )
+ORDER BY pv.major_version DESC NULLS LAST, pv.minor_version DESC, pv.patch_version DESC, pv.prerelease_key DESC, pv.created_at DESC, pv.id DESC
+LIMIT $3
+`
+
+type ListPolicyVersionsAfterParams struct {
+	PolicyName string `json:"policy_name"`
+	AfterID    int32  `json:"after_id"`
+	RowLimit   int32  `json:"row_limit"`
+}
+
+// Versions that come after the version after_id in the ListPolicyVersions order
+func (q *Queries) ListPolicyVersionsAfter(ctx context.Context, arg ListPolicyVersionsAfterParams) ([]PolicyVersion, error) {
+	rows, err := q.db.Query(ctx, listPolicyVersionsAfter, arg.PolicyName, arg.AfterID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PolicyVersion{}
+	for rows.Next() {
+		var i PolicyVersion
+		if err := rows.Scan(
+			&i.ID,
+			&i.PolicyName,
+			&i.Version,
+			&i.IsLatest,
+			&i.DisplayName,
+			&i.Provider,
+			&i.Description,
+			&i.Categories,
+			&i.Tags,
+			&i.LogoPath,
+			&i.BannerPath,
+			&i.SupportedPlatforms,
+			&i.ReleaseDate,
+			&i.DefinitionYaml,
+			&i.IconPath,
+			&i.SourceType,
+			&i.DownloadUrl,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.StatusReason,
+			&i.ReplacementVersion,
+			&i.StatusUpdatedAt,
+			&i.MajorVersion,
+			&i.MinorVersion,
+			&i.PatchVersion,
+			&i.Prerelease,
+			&i.PrereleaseKey,
+			&i.DefinitionDigest,
+			&i.ArtifactDigest,
+			&i.SignatureStatus,
+			&i.SignatureKeyID,
+			&i.Signature,
+			&i.SignatureVerifiedAt,
+			&i.BundleID,
+			&i.PreviousStatus,
+			&i.PreviousStatusReason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPolicyVersionsBefore = `-- name: ListPolicyVersionsBefore :many
+SELECT pv.id, pv.policy_name, pv.version, pv.is_latest, pv.display_name, pv.provider, pv.description, pv.categories, pv.tags, pv.logo_path, pv.banner_path, pv.supported_platforms, pv.release_date, pv.definition_yaml, pv.icon_path, pv.source_type, pv.download_url, pv.created_at, pv.updated_at, pv.status, pv.status_reason, pv.replacement_version, pv.status_updated_at, pv.major_version, pv.minor_version, pv.patch_version, pv.prerelease, pv.prerelease_key, pv.definition_digest, pv.artifact_digest, pv.signature_status, pv.signature_key_id, pv.signature, pv.signature_verified_at, pv.bundle_id, pv.previous_status, pv.previous_status_reason FROM policy_version pv
+WHERE pv.policy_name = $1
+    AND (coalesce(pv.major_version, -1), coalesce(pv.minor_version, -1), coalesce(pv.patch_version, -1), pv.prerelease_key, pv.created_at, pv.id) > (
+        SELECT coalesce(b.major_version, -1), coalesce(b.minor_version, -1), coalesce(b.patch_version, -1), b.prerelease_key, b.created_at, b.id
+        FROM policy_version b WHERE b.id = $2
+    )
+ORDER BY pv.major_version ASC NULLS FIRST, pv.minor_version ASC, pv.patch_version ASC, pv.prerelease_key ASC, pv.created_at ASC, pv.id ASC
+LIMIT $3
+`
+
+type ListPolicyVersionsBeforeParams struct {
+	PolicyName string `json:"policy_name"`
+	BeforeID   int32  `json:"before_id"`
+	RowLimit   int32  `json:"row_limit"`
+}
+
+// Versions that come before the version before_id in the ListPolicyVersions
+// order, nearest first; the caller reverses them
+func (q *Queries) ListPolicyVersionsBefore(ctx context.Context, arg ListPolicyVersionsBeforeParams) ([]PolicyVersion, error) {
+	rows, err := q.db.Query(ctx, listPolicyVersionsBefore, arg.PolicyName, arg.BeforeID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PolicyVersion{}
+	for rows.Next() {
+		var i PolicyVersion
+		if err := rows.Scan(
+			&i.ID,
+			&i.PolicyName,
+			&i.Version,
+			&i.IsLatest,
+			&i.DisplayName,
+			&i.Provider,
+			&i.Description,
+			&i.Categories,
+			&i.Tags,
+			&i.LogoPath,
+			&i.BannerPath,
+			&i.SupportedPlatforms,
+			&i.ReleaseDate,
+			&i.DefinitionYaml,
+			&i.IconPath,
+			&i.SourceType,
+			&i.DownloadUrl,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.StatusReason,
+			&i.ReplacementVersion,
+			&i.StatusUpdatedAt,
 			&i.MajorVersion,
 			&i.MinorVersion,
 			&i.PatchVersion,
+			&i.Prerelease,
+			&i.PrereleaseKey,
+			&i.DefinitionDigest,
+			&i.ArtifactDigest,
+			&i.SignatureStatus,
+			&i.SignatureKeyID,
+			&i.Signature,
+			&i.SignatureVerifiedAt,
+			&i.BundleID,
+			&i.PreviousStatus,
+			&i.PreviousStatusReason,
 		); err != nil {
 			return nil, err
 		}
@@ -694,6 +1328,40 @@ func (q *Queries) ListPolicyVersions(ctx context.Context, arg ListPolicyVersions
 	return items, nil
 }
 
+const listResolvableVersions = `-- name: ListResolvableVersions :many
+SELECT version FROM policy_version
+WHERE policy_name = $1 AND status <> 'yanked'
+`
+
+func (q *Queries) ListResolvableVersions(ctx context.Context, policyName string) ([]string, error) {
+	rows, err := q.db.Query(ctx, listResolvableVersions, policyName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		items = append(items, version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockPolicy = `-- name: LockPolicy :exec
+SELECT pg_advisory_xact_lock(hashtext($1::text))
+`
+
+func (q *Queries) LockPolicy(ctx context.Context, policyName string) error {
+	_, err := q.db.Exec(ctx, lockPolicy, policyName)
+	return err
+}
+
 const updateLatestVersion = `-- name: UpdateLatestVersion :exec
 
 UPDATE policy_version
@@ -716,3 +1384,83 @@ func (q *Queries) UpdateLatestVersion(ctx context.Context, arg UpdateLatestVersi
 	_, err := q.db.Exec(ctx, updateLatestVersion, arg.PolicyName, arg.Version)
 	return err
 }
+
+const updatePolicyVersionStatus = `-- name: UpdatePolicyVersionStatus :one
+
+UPDATE policy_version
+SET status = $3,
+    status_reason = $4,
+    replacement_version = $5,
+    previous_status = $6,
+    previous_status_reason = $7,
+    status_updated_at = NOW(),
+    updated_at = NOW()
+WHERE policy_name = $1 AND version = $2
+RETURNING id, policy_name, version, is_latest, display_name, provider, description, categories, tags, logo_path, banner_path, supported_platforms, release_date, definition_yaml, icon_path, source_type, download_url, created_at, updated_at, status, status_reason, replacement_version, status_updated_at, major_version, minor_version, patch_version, prerelease, prerelease_key, definition_digest, artifact_digest, signature_status, signature_key_id, signature, signature_verified_at, bundle_id, previous_status, previous_status_reason
+`
+
+type UpdatePolicyVersionStatusParams struct {
+	PolicyName           string      `json:"policy_name"`
+	Version              string      `json:"version"`
+	Status               string      `json:"status"`
+	StatusReason         pgtype.Text `json:"status_reason"`
+	ReplacementVersion   pgtype.Text `json:"replacement_version"`
+	PreviousStatus       pgtype.Text `json:"previous_status"`
+	PreviousStatusReason pgtype.Text `json:"previous_status_reason"`
+}
+
+// =============================================================================
+// VERSION LIFECYCLE (DEPRECATE / YANK)
+// =============================================================================
+func (q *Queries) UpdatePolicyVersionStatus(ctx context.Context, arg UpdatePolicyVersionStatusParams) (PolicyVersion, error) {
+	row := q.db.QueryRow(ctx, updatePolicyVersionStatus,
+		arg.PolicyName,
+		arg.Version,
+		arg.Status,
+		arg.StatusReason,
+		arg.ReplacementVersion,
+		arg.PreviousStatus,
+		arg.PreviousStatusReason,
+	)
+	var i PolicyVersion
+	err := row.Scan(
+		&i.ID,
+		&i.PolicyName,
+		&i.Version,
+		&i.IsLatest,
+		&i.DisplayName,
+		&i.Provider,
+		&i.Description,
+		&i.Categories,
+		&i.Tags,
+		&i.LogoPath,
+		&i.BannerPath,
+		&i.SupportedPlatforms,
+		&i.ReleaseDate,
+		&i.DefinitionYaml,
+		&i.IconPath,
+		&i.SourceType,
+		&i.DownloadUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.StatusReason,
+		&i.ReplacementVersion,
+		&i.StatusUpdatedAt,
+		&i.MajorVersion,
+		&i.MinorVersion,
+		&i.PatchVersion,
+		&i.Prerelease,
+		&i.PrereleaseKey,
+		&i.DefinitionDigest,
+		&i.ArtifactDigest,
+		&i.SignatureStatus,
+		&i.SignatureKeyID,
+		&i.Signature,
+		&i.SignatureVerifiedAt,
+		&i.BundleID,
+		&i.PreviousStatus,
+		&i.PreviousStatusReason,
+	)
+	return i, err
+}
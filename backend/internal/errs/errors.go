@@ -20,12 +20,32 @@ import (
 type Code string
 
 const (
-	CodePolicyVersionNotFound Code = "POLICY_VERSION_NOT_FOUND"
-	CodeDocNotFound           Code = "DOC_NOT_FOUND"
-	CodeValidationError       Code = "VALIDATION_ERROR"
-	CodeSyncFetchFailed       Code = "SYNC_FETCH_FAILED"
-	CodeInternalServerError   Code = "INTERNAL_SERVER_ERROR"
-	CodeDatabaseError         Code = "DB_ERROR"
+	CodePolicyVersionNotFound   Code = "POLICY_VERSION_NOT_FOUND"
+	CodeDocNotFound             Code = "DOC_NOT_FOUND"
+	CodeValidationError         Code = "VALIDATION_ERROR"
+	CodeSyncFetchFailed         Code = "SYNC_FETCH_FAILED"
+	CodeSyncFetchBlocked        Code = "SYNC_FETCH_BLOCKED"
+	CodeSyncFetchTooLarge       Code = "SYNC_FETCH_TOO_LARGE"
+	CodeRequestTooLarge         Code = "REQUEST_TOO_LARGE"
+	CodeInternalServerError     Code = "INTERNAL_SERVER_ERROR"
+	CodeDatabaseError           Code = "DB_ERROR"
+	CodeVersionStatusConflict   Code = "VERSION_STATUS_CONFLICT"
+	CodeUnauthorized            Code = "UNAUTHORIZED"
+	CodeForbidden               Code = "FORBIDDEN"
+	CodeResolveFailed           Code = "RESOLVE_FAILED"
+	CodeDigestMismatch          Code = "DIGEST_MISMATCH"
+	CodeSignatureInvalid        Code = "SIGNATURE_INVALID"
+	CodeSignatureRequired       Code = "SIGNATURE_REQUIRED"
+	CodeSyncJobNotFound         Code = "SYNC_JOB_NOT_FOUND"
+	CodeSyncInProgress          Code = "SYNC_IN_PROGRESS"
+	CodeSyncSourceNotConfigured Code = "SYNC_SOURCE_NOT_CONFIGURED"
+	CodeBundleTooLarge          Code = "BUNDLE_TOO_LARGE"
+	CodeAssetNotFound           Code = "ASSET_NOT_FOUND"
+	CodeBundleNotFound          Code = "BUNDLE_NOT_FOUND"
+	CodeBlobNotFound            Code = "BLOB_NOT_FOUND"
+	CodeDefinitionInvalid       Code = "DEFINITION_INVALID"
+	CodeSchemaNotFound          Code = "SCHEMA_NOT_FOUND"
+	CodeInvalidCursor           Code = "INVALID_CURSOR"
 )
 
 // AppError represents a structured application error
@@ -71,6 +91,26 @@ func NewConflictError(code Code, msg string, details map[string]any) *AppError {
 	}
 }
 
+// NewUnauthorizedError creates an authentication error
+func NewUnauthorizedError(msg string, details map[string]any) *AppError {
+	return &AppError{
+		Code:       CodeUnauthorized,
+		HTTPStatus: http.StatusUnauthorized,
+		Message:    msg,
+		Details:    details,
+	}
+}
+
+// NewForbiddenError creates an authorization error
+func NewForbiddenError(msg string, details map[string]any) *AppError {
+	return &AppError{
+		Code:       CodeForbidden,
+		HTTPStatus: http.StatusForbidden,
+		Message:    msg,
+		Details:    details,
+	}
+}
+
 // NewInternalError creates an internal server error
 func NewInternalError(msg string, details map[string]any) *AppError {
 	return &AppError{
@@ -140,6 +180,169 @@ func SyncFetchFailed(url string, err error) *AppError {
 	}
 }
 
+// SyncFetchBlocked creates an error for a sync fetch that violates the fetch
+// policy: a disallowed host or address or an unexpected content type
+func SyncFetchBlocked(url, reason string) *AppError {
+	return &AppError{
+		Code:       CodeSyncFetchBlocked,
+		HTTPStatus: http.StatusBadRequest,
+		Message:    "Fetch blocked by the sync fetch policy",
+		Details: map[string]any{
+			"url":    url,
+			"reason": reason,
+		},
+	}
+}
+
+// SyncFetchTooLarge creates an error for a fetched file over its size limit
+func SyncFetchTooLarge(url, resource string, limit int64) *AppError {
+	return &AppError{
+		Code:       CodeSyncFetchTooLarge,
+		HTTPStatus: http.StatusUnprocessableEntity,
+		Message:    "Fetched " + resource + " exceeds its size limit",
+		Details: map[string]any{
+			"url":      url,
+			"resource": resource,
+			"limit":    limit,
+		},
+	}
+}
+
+// RequestTooLarge creates an error for a request body over the route's size limit
+func RequestTooLarge(limit int64) *AppError {
+	return &AppError{
+		Code:       CodeRequestTooLarge,
+		HTTPStatus: http.StatusRequestEntityTooLarge,
+		Message:    "Request body exceeds the size limit",
+		Details:    map[string]any{"limit": limit},
+	}
+}
+
+// ResolveFailed creates a strict-mode resolve failure carrying the per-item errors
+func ResolveFailed(failed int, itemErrors any) *AppError {
+	return &AppError{
+		Code:       CodeResolveFailed,
+		HTTPStatus: http.StatusUnprocessableEntity,
+		Message:    "One or more policies could not be resolved",
+		Details: map[string]any{
+			"failedCount": failed,
+			"errors":      itemErrors,
+		},
+	}
+}
+
+// DigestMismatch creates an error for a re-sync whose content differs from the
+// already published version
+func DigestMismatch(name, version string, details map[string]any) *AppError {
+	if details == nil {
+		details = map[string]any{}
+	}
+	details["policyName"] = name
+	details["version"] = version
+	return NewConflictError(
+		CodeDigestMismatch,
+		"Policy version already exists with different content; published versions are immutable",
+		details,
+	)
+}
+
+// SignatureInvalid creates an error for a release signature that failed verification
+func SignatureInvalid(name, version string, err error) *AppError {
+	return &AppError{
+		Code:       CodeSignatureInvalid,
+		HTTPStatus: http.StatusUnprocessableEntity,
+		Message:    "Release signature verification failed",
+		Details: map[string]any{
+			"policyName": name,
+			"version":    version,
+			"error":      err.Error(),
+		},
+	}
+}
+
+// SignatureRequired creates an error for an unsigned sync when unsigned syncs are rejected
+func SignatureRequired(name, version string) *AppError {
+	return &AppError{
+		Code:       CodeSignatureRequired,
+		HTTPStatus: http.StatusUnprocessableEntity,
+		Message:    "Policy releases must be signed",
+		Details: map[string]any{
+			"policyName": name,
+			"version":    version,
+		},
+	}
+}
+
+// SyncJobNotFound creates a sync job not found error
+func SyncJobNotFound(id int64) *AppError {
+	return NewNotFoundError(
+		CodeSyncJobNotFound,
+		"Sync job not found",
+		map[string]any{"jobId": id},
+	)
+}
+
+// SyncInProgress creates an error for a sync submitted while another sync of
+// the same version is queued or running
+func SyncInProgress(name, version string, jobID int64) *AppError {
+	return NewConflictError(
+		CodeSyncInProgress,
+		"A sync of this policy version is already queued or running",
+		map[string]any{
+			"policyName": name,
+			"version":    version,
+			"jobId":      jobID,
+		},
+	)
+}
+
+// SyncSourceNotConfigured creates an error for a scan requested without a sync source
+func SyncSourceNotConfigured() *AppError {
+	return NewNotFoundError(
+		CodeSyncSourceNotConfigured,
+		"No sync source is configured",
+		nil,
+	)
+}
+
+// BundleTooLarge creates an error for an uploaded bundle that exceeds a size or entry limit
+func BundleTooLarge(limit string, max int64) *AppError {
+	return &AppError{
+		Code:       CodeBundleTooLarge,
+		HTTPStatus: http.StatusRequestEntityTooLarge,
+		Message:    "Policy bundle exceeds the " + limit + " limit",
+		Details: map[string]any{
+			"limit": limit,
+			"max":   max,
+		},
+	}
+}
+
+// AssetNotFound creates an error for a file missing from a version's bundle
+func AssetNotFound(name, version, path string) *AppError {
+	return NewNotFoundError(
+		CodeAssetNotFound,
+		"Asset not found",
+		map[string]any{
+			"policyName": name,
+			"version":    version,
+			"path":       path,
+		},
+	)
+}
+
+// BundleNotFound creates an error for a version that was not published from an uploaded bundle
+func BundleNotFound(name, version string) *AppError {
+	return NewNotFoundError(
+		CodeBundleNotFound,
+		"Policy version was not published from a bundle",
+		map[string]any{
+			"policyName": name,
+			"version":    version,
+		},
+	)
+}
+
 // IsUniqueConstraintError checks if an error is a PostgreSQL unique constraint violation
 func IsUniqueConstraintError(err error) bool {
 	if err == nil {
@@ -157,3 +360,61 @@ func IsUniqueConstraintError(err error) bool {
 		strings.Contains(errMsg, "duplicate key") ||
 		strings.Contains(errMsg, "already exists")
 }
+
+// BlobNotFound creates an error for a digest with no mirrored blob
+func BlobNotFound(digest string) *AppError {
+	return NewNotFoundError(
+		CodeBlobNotFound,
+		"Blob not found",
+		map[string]any{"digest": digest},
+	)
+}
+
+// DefinitionInvalid creates an error for a policy definition that violates
+// its schema, listing each violation with the path of the offending value
+func DefinitionInvalid(name, version, schemaVersion string, violations any) *AppError {
+	return &AppError{
+		Code:       CodeDefinitionInvalid,
+		HTTPStatus: http.StatusUnprocessableEntity,
+		Message:    "Policy definition does not conform to its schema",
+		Details: map[string]any{
+			"policyName":    name,
+			"version":       version,
+			"schemaVersion": schemaVersion,
+			"violations":    violations,
+		},
+	}
+}
+
+// SchemaNotFound creates an error for an unknown policy definition schema version
+func SchemaNotFound(version string) *AppError {
+	return NewNotFoundError(
+		CodeSchemaNotFound,
+		"Schema version not found",
+		map[string]any{"version": version},
+	)
+}
+
+// InvalidCursor creates an error for a pagination cursor that is malformed or
+// was issued for a different listing
+func InvalidCursor(reason string) *AppError {
+	return &AppError{
+		Code:       CodeInvalidCursor,
+		HTTPStatus: http.StatusBadRequest,
+		Message:    "Invalid pagination cursor",
+		Details:    map[string]any{"reason": reason},
+	}
+}
+
+// PolicyOwnedByOtherProvider creates an error for a version published under a
+// provider other than the one that owns the policy
+func PolicyOwnedByOtherProvider(name, owner, provider string) *AppError {
+	return NewForbiddenError(
+		"Policy is owned by a different provider",
+		map[string]any{
+			"policyName": name,
+			"owner":      owner,
+			"provider":   provider,
+		},
+	)
+}
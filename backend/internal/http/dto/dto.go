@@ -27,6 +27,16 @@ type PaginatedResponse struct {
 	Meta    PaginatedMetaDTO `json:"meta"`
 }
 
+// ResolveResponse is the API response envelope for POST /policies/resolve.
+// Data holds the resolved policies in request order; Errors holds one entry per failed request.
+type ResolveResponse struct {
+	Success bool             `json:"success"`
+	Data    interface{}      `json:"data"`
+	Errors  []PolicyErrorDTO `json:"errors"`
+	Error   *ErrorDTO        `json:"error"`
+	Meta    MetaDTO          `json:"meta"`
+}
+
 // ErrorDTO represents an error in the response
 type ErrorDTO struct {
 	Code    string         `json:"code"`
@@ -47,14 +57,28 @@ type PaginatedMetaDTO struct {
 	Timestamp  time.Time     `json:"timestamp"`
 	RequestID  string        `json:"request_id"`
 	Pagination PaginationDTO `json:"pagination"`
+	Facets     FacetsDTO     `json:"facets,omitempty"`
 }
 
-// PaginationDTO contains pagination information
+// PaginationDTO contains pagination information. Page is omitted for pages
+// requested by cursor and the totals when they were not counted; Next and Prev
+// are cursors to the neighbouring pages, omitted at either end of the listing.
 type PaginationDTO struct {
-	Page       int `json:"page"`
-	PageSize   int `json:"pageSize"`
-	TotalItems int `json:"totalItems"`
-	TotalPages int `json:"totalPages"`
+	Page       int    `json:"page,omitempty"`
+	PageSize   int    `json:"pageSize"`
+	TotalItems *int   `json:"totalItems,omitempty"`
+	TotalPages *int   `json:"totalPages,omitempty"`
+	Next       string `json:"next,omitempty"`
+	Prev       string `json:"prev,omitempty"`
+}
+
+// FacetsDTO contains the value counts of the facets requested with a policy listing, by facet
+type FacetsDTO map[string][]FacetCountDTO
+
+// FacetCountDTO is the number of policies listed with only Value selected for a facet
+type FacetCountDTO struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
 }
 
 // DocsAllResponseDTO contains all documentation pages as an array
@@ -88,14 +112,100 @@ type SyncRequestDTO struct {
 	DefinitionURL string            `json:"definitionUrl" binding:"required"`
 	Metadata      PolicyMetadataDTO `json:"metadata" binding:"required"`
 	Documentation map[string]string `json:"documentation"`
+	RequireDocs   []string          `json:"requireDocs,omitempty"`
 	AssetsBaseURL string            `json:"assetsBaseUrl"`
+	Signature     *SignatureDTO     `json:"signature,omitempty"`
+}
+
+// SignatureDTO is a detached release signature over the signing payload
+type SignatureDTO struct {
+	KeyID     string `json:"keyId" binding:"required"`
+	Algorithm string `json:"algorithm,omitempty" binding:"omitempty,oneof=ed25519 ecdsa-sha256"`
+	Value     string `json:"value" binding:"required"` // base64 encoded signature
 }
 
 // SyncResponseDTO represents the sync response payload
 type SyncResponseDTO struct {
+	PolicyName       string             `json:"policyName"`
+	Version          string             `json:"version"`
+	Status           string             `json:"status"`
+	DefinitionDigest string             `json:"definitionDigest"`
+	ArtifactDigest   string             `json:"artifactDigest"`
+	SignatureStatus  string             `json:"signatureStatus"`
+	Warning          string             `json:"warning,omitempty"`
+	Docs             []SyncDocResultDTO `json:"docs,omitempty"`
+}
+
+// SyncDocResultDTO represents how one doc page of a sync was fetched
+type SyncDocResultDTO struct {
+	Page     string `json:"page"`
+	Status   string `json:"status"`
+	Cached   bool   `json:"cached,omitempty"`
+	Attempts int    `json:"attempts"`
+	Error    string `json:"error,omitempty"`
+}
+
+// SyncJobDTO represents an asynchronous sync job
+type SyncJobDTO struct {
+	ID          int64            `json:"id"`
+	PolicyName  string           `json:"policyName"`
+	Version     string           `json:"version"`
+	Status      string           `json:"status"`
+	Attempts    int              `json:"attempts"`
+	MaxAttempts int              `json:"maxAttempts"`
+	Steps       []SyncJobStepDTO `json:"steps"`
+	Result      *SyncResponseDTO `json:"result,omitempty"`
+	Error       *ErrorDTO        `json:"error,omitempty"`
+	NextRunAt   *time.Time       `json:"nextRunAt,omitempty"`
+	CreatedAt   time.Time        `json:"createdAt"`
+	UpdatedAt   time.Time        `json:"updatedAt"`
+	StartedAt   *time.Time       `json:"startedAt,omitempty"`
+	FinishedAt  *time.Time       `json:"finishedAt,omitempty"`
+}
+
+// SyncJobStepDTO represents the progress of one step of a sync job attempt
+type SyncJobStepDTO struct {
+	Name       string     `json:"name"`
+	Status     string     `json:"status"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// SourceScanDTO represents the result of a sync source scan
+type SourceScanDTO struct {
+	Revision   string               `json:"revision,omitempty"`
+	Found      int                  `json:"found"`
+	Existing   int                  `json:"existing"`
+	InProgress int                  `json:"inProgress"`
+	Failed     int                  `json:"failed"`
+	Queued     []SourceScanJobDTO   `json:"queued"`
+	Errors     []SourceScanErrorDTO `json:"errors"`
+}
+
+// SourceScanJobDTO represents a version queued by a sync source scan
+type SourceScanJobDTO struct {
+	PolicyName string `json:"policyName"`
+	Version    string `json:"version"`
+	JobID      int64  `json:"jobId"`
+}
+
+// SourceScanErrorDTO represents a version a sync source scan could not queue
+type SourceScanErrorDTO struct {
 	PolicyName string `json:"policyName"`
 	Version    string `json:"version"`
-	Status     string `json:"status"`
+	Message    string `json:"message"`
+}
+
+// DeprecateVersionRequestDTO represents the payload for deprecating a policy version
+type DeprecateVersionRequestDTO struct {
+	Reason             string `json:"reason"`
+	ReplacementVersion string `json:"replacementVersion"`
+}
+
+// YankVersionRequestDTO represents the payload for yanking a policy version
+type YankVersionRequestDTO struct {
+	Reason string `json:"reason" binding:"required"`
 }
 
 // HealthResponseDTO represents health check response
@@ -106,14 +216,54 @@ type HealthResponseDTO struct {
 
 // ResolvePolicyRequestDTO represents a resolve policy retrieval request
 type ResolvePolicyRequestDTO struct {
-	Policies []PolicyRequestItemDTO `json:"policies" binding:"required,min=1"`
+	Policies     []PolicyRequestItemDTO `json:"policies" binding:"required,min=1"`
+	Strict       bool                   `json:"strict,omitempty"`       // Fail the whole call if any item cannot be resolved
+	VerifiedOnly bool                   `json:"verifiedOnly,omitempty"` // Only resolve versions with a verified release signature
+	// DefinitionFormat is "string" (default) to embed definitions as YAML strings or "object" to embed them parsed
+	DefinitionFormat string `json:"definitionFormat,omitempty"`
 }
 
 // PolicyRequestItemDTO represents a single policy request in the batch
 type PolicyRequestItemDTO struct {
 	Name              string `json:"name" binding:"required"`
-	RetrievalStrategy string `json:"retrievalStrategy" binding:"required"` // "exact", "latest_patch", "latest_minor", "latest_major"
-	BaseVersion       string `json:"baseVersion,omitempty"`                // For "exact", "latest_patch", "latest_minor"; ignored for "latest_major"
+	RetrievalStrategy string `json:"retrievalStrategy,omitempty"` // "exact", "latest_patch", "latest_minor", "latest_major"; alias for a constraint
+	BaseVersion       string `json:"baseVersion,omitempty"`       // For "exact", "latest_patch", "latest_minor"; ignored for "latest_major"
+	Constraint        string `json:"constraint,omitempty"`        // Version range such as "^1.2.0", "~1.4", ">=1.0.0 <2.0.0", "1.x"
+	IncludePrerelease bool   `json:"includePrerelease,omitempty"` // Let pre-release versions such as "2.0.0-beta.1" match
+}
+
+// LockfileDTO pins resolved policies to exact versions and definition digests.
+// It is returned by POST /policies/lock and accepted by POST /policies/lock/verify.
+type LockfileDTO struct {
+	LockfileVersion int               `json:"lockfileVersion"`
+	GeneratedAt     *time.Time        `json:"generatedAt,omitempty"`
+	Policies        []LockedPolicyDTO `json:"policies" binding:"required,min=1,dive"`
+}
+
+// LockedPolicyDTO represents a single locked policy
+type LockedPolicyDTO struct {
+	Name        string `json:"name" binding:"required"`
+	Version     string `json:"version" binding:"required"`
+	Digest      string `json:"digest" binding:"required"` // "sha256:<hex>" of the definition YAML
+	DownloadURL string `json:"downloadUrl,omitempty"`
+}
+
+// LockVerificationDTO reports whether a lockfile still matches the hub
+type LockVerificationDTO struct {
+	InSync   bool                    `json:"inSync"`
+	Policies []LockedPolicyStatusDTO `json:"policies"`
+}
+
+// LockedPolicyStatusDTO reports drift for a single lockfile entry
+type LockedPolicyStatusDTO struct {
+	Index          int    `json:"index"`
+	Name           string `json:"name"`
+	Version        string `json:"version"`
+	Status         string `json:"status"` // OK, DEPRECATED, YANKED, BUILD_MISMATCH, DIGEST_MISMATCH, VERSION_NOT_FOUND
+	ActualVersion  string `json:"actualVersion,omitempty"`
+	ExpectedDigest string `json:"expectedDigest"`
+	ActualDigest   string `json:"actualDigest,omitempty"`
+	Message        string `json:"message,omitempty"`
 }
 
 // PolicyResolveItemDTO represents a policy item in the resolve response
@@ -128,9 +278,13 @@ type PolicyResolveItemDTO struct {
 
 // PolicyErrorDTO represents an error for a specific policy in batch response
 type PolicyErrorDTO struct {
-	Name    string `json:"name"`
-	Version string `json:"version,omitempty"`
-	Error   string `json:"error"`
+	Index             int    `json:"index"`
+	Name              string `json:"name"`
+	RetrievalStrategy string `json:"retrievalStrategy,omitempty"`
+	BaseVersion       string `json:"baseVersion,omitempty"`
+	Constraint        string `json:"constraint,omitempty"`
+	Code              string `json:"code"`
+	Message           string `json:"message"`
 }
 
 // PolicyDTO represents the standardized policy object
@@ -151,19 +305,48 @@ type PolicyDTO struct {
 	IsLatest           bool     `json:"isLatest"`
 	SourceType         string   `json:"sourceType,omitempty"`
 	SourceURL          string   `json:"downloadUrl,omitempty"`
+	Status             string   `json:"status"`
+	StatusReason       string   `json:"statusReason,omitempty"`
+	ReplacementVersion string   `json:"replacementVersion,omitempty"`
+	DefinitionDigest   string   `json:"definitionDigest"`
+	ArtifactDigest     string   `json:"artifactDigest,omitempty"`
+	SignatureStatus    string   `json:"signatureStatus"`
+	SignatureKeyID     string   `json:"signatureKeyId,omitempty"`
+	// Snippet is set on search results: HTML-escaped text with the matches in <mark> tags
+	Snippet string `json:"snippet,omitempty"`
 }
 
+// Values of the definitionFormat option of the engine and resolve endpoints
+const (
+	DefinitionFormatString = "string"
+	DefinitionFormatObject = "object"
+)
+
 // PolicyWithDefinitionDTO represents a streamlined policy object for engine/batch operations
 // Includes definition but excludes unnecessary metadata fields
 type PolicyWithDefinitionDTO struct {
-	Name        string   `json:"name"`
-	Version     string   `json:"version"`
-	DisplayName string   `json:"displayName"`
-	Provider    string   `json:"provider"`
-	Categories  []string `json:"categories"`
-	ReleaseDate *string  `json:"releaseDate,omitempty"`
-	IsLatest    bool     `json:"isLatest"`
-	SourceType  string   `json:"sourceType,omitempty"`
-	SourceURL   string   `json:"downloadUrl,omitempty"`
-	Definition  string   `json:"definition"`
+	Name               string   `json:"name"`
+	Version            string   `json:"version"`
+	DisplayName        string   `json:"displayName"`
+	Provider           string   `json:"provider"`
+	Categories         []string `json:"categories"`
+	ReleaseDate        *string  `json:"releaseDate,omitempty"`
+	IsLatest           bool     `json:"isLatest"`
+	SourceType         string   `json:"sourceType,omitempty"`
+	SourceURL          string   `json:"downloadUrl,omitempty"`
+	Status             string   `json:"status"`
+	ReplacementVersion string   `json:"replacementVersion,omitempty"`
+	Warning            string   `json:"warning,omitempty"`
+	DefinitionDigest   string   `json:"definitionDigest"`
+	ArtifactDigest     string   `json:"artifactDigest,omitempty"`
+	SignatureStatus    string   `json:"signatureStatus"`
+	SignatureKeyID     string   `json:"signatureKeyId,omitempty"`
+	Definition         any      `json:"definition"` // YAML string, or the parsed definition with definitionFormat=object
+}
+
+// ResolvedPolicyDTO is a resolved policy tagged with the index of the request it
+// answers, matching PolicyErrorDTO.Index for the items that failed
+type ResolvedPolicyDTO struct {
+	Index int `json:"index"`
+	PolicyWithDefinitionDTO
 }
@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/wso2/policyhub/internal/errs"
+	"github.com/wso2/policyhub/internal/logging"
+	"github.com/wso2/policyhub/internal/storage"
+)
+
+// BlobHandler serves artifacts and images mirrored into the blob store
+type BlobHandler struct {
+	store  storage.Store // nil when mirroring is disabled
+	logger *logging.Logger
+}
+
+// NewBlobHandler creates a new blob handler. store may be nil.
+func NewBlobHandler(store storage.Store, logger *logging.Logger) *BlobHandler {
+	return &BlobHandler{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// GetBlob handles GET /blobs/{digest}
+func (h *BlobHandler) GetBlob(c *gin.Context) {
+	digest := c.Param("digest")
+
+	key, err := storage.DigestKey(digest)
+	if err != nil {
+		_ = c.Error(errs.NewValidationError("invalid blob digest", map[string]any{"digest": digest}))
+		return
+	}
+	if h.store == nil {
+		_ = c.Error(errs.BlobNotFound(digest))
+		return
+	}
+
+	// Blobs are addressed by content, so the digest is a strong ETag that never changes
+	etag := `"` + digest + `"`
+	if match := c.GetHeader("If-None-Match"); match != "" && (match == etag || match == "*") {
+		if _, err := h.store.Stat(c.Request.Context(), key); err == nil {
+			c.Header("ETag", etag)
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+
+	body, object, err := h.store.Get(c.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			_ = c.Error(errs.BlobNotFound(digest))
+			return
+		}
+		h.logger.Error("Failed to read blob", zap.String("digest", digest), zap.Error(err))
+		_ = c.Error(errs.NewInternalError("Failed to read blob", nil))
+		return
+	}
+	defer body.Close()
+
+	c.Header("ETag", etag)
+	if header := digestHeader(digest); header != "" {
+		c.Header("Digest", header)
+	}
+	// Mirrored files are publisher content: never sniffed or run as active content on the hub's origin
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; sandbox")
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.DataFromReader(http.StatusOK, object.Size, object.ContentType, body, nil)
+}
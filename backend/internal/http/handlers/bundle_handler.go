@@ -0,0 +1,188 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wso2/policyhub/internal/errs"
+	"github.com/wso2/policyhub/internal/http/middleware"
+	"github.com/wso2/policyhub/internal/logging"
+	"github.com/wso2/policyhub/internal/sync"
+)
+
+// bundleFormField is the multipart form field that carries an uploaded bundle
+const bundleFormField = "bundle"
+
+// BundleRequestOverhead is allowed on top of the bundle size limit in upload
+// request bodies, for multipart boundaries and headers
+const BundleRequestOverhead = 64 << 10
+
+// BundleHandler handles policy bundle uploads and serves published bundles
+type BundleHandler struct {
+	syncService *sync.Service
+	logger      *logging.Logger
+}
+
+// NewBundleHandler creates a new bundle handler
+func NewBundleHandler(syncService *sync.Service, logger *logging.Logger) *BundleHandler {
+	return &BundleHandler{
+		syncService: syncService,
+		logger:      logger,
+	}
+}
+
+// UploadBundle handles POST /internal/policies/{name}/versions/{version}/bundle
+func (h *BundleHandler) UploadBundle(c *gin.Context) {
+	policyName := c.Param("name")
+	version := c.Param("version")
+
+	archive, err := h.readBundle(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	bundle, err := h.syncService.OpenBundle(policyName, version, archive)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// Callers may only publish under providers they hold a scope for
+	if err := middleware.AuthorizeProvider(c, bundle.Metadata.Provider); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// Queue the sync; workers run it outside the request
+	job, err := h.syncService.SubmitBundle(c.Request.Context(), bundle)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/api/v1/internal/sync-jobs/%d", job.ID))
+	middleware.SendAccepted(c, toSyncJobDTO(job))
+}
+
+// DownloadBundle handles GET /policies/{name}/versions/{version}/bundle
+func (h *BundleHandler) DownloadBundle(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	archive, err := h.syncService.GetBundleArchive(c.Request.Context(), name, version)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// The archive is the version's artifact, so its digest is a strong ETag
+	if archive.Digest != "" {
+		etag := `"` + archive.Digest + `"`
+		c.Header("ETag", etag)
+		if header := digestHeader(archive.Digest); header != "" {
+			c.Header("Digest", header)
+		}
+		if match := c.GetHeader("If-None-Match"); match != "" && (match == etag || match == "*") {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+
+	contentType := "application/gzip"
+	if archive.Format == sync.BundleZip {
+		contentType = "application/zip"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.%s"`, name, version, archive.Format))
+	c.Data(http.StatusOK, contentType, archive.Data)
+}
+
+// GetBundleAsset handles GET /policies/{name}/versions/{version}/assets/{path}
+func (h *BundleHandler) GetBundleAsset(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+	assetPath := strings.TrimPrefix(c.Param("path"), "/")
+
+	content, err := h.syncService.GetBundleAsset(c.Request.Context(), name, version, assetPath)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(assetPath))
+	if contentType == "" {
+		contentType = http.DetectContentType(content)
+	}
+
+	// Assets are publisher content: never sniffed or run as active content on the hub's origin
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; sandbox")
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Data(http.StatusOK, contentType, content)
+}
+
+// readBundle reads the uploaded archive from a multipart form field named
+// "bundle" or, for any other content type, from the raw request body. The
+// router caps the request body at the bundle size limit plus BundleRequestOverhead.
+func (h *BundleHandler) readBundle(c *gin.Context) ([]byte, error) {
+	maxSize := h.syncService.MaxBundleSize()
+
+	body := io.Reader(c.Request.Body)
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		reader, err := c.Request.MultipartReader()
+		if err != nil {
+			return nil, errs.NewValidationError("invalid multipart body", map[string]any{"error": err.Error()})
+		}
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				return nil, errs.NewValidationError("multipart body has no bundle field", map[string]any{"field": bundleFormField})
+			}
+			if err != nil {
+				return nil, bundleReadError(err, maxSize)
+			}
+			if part.FormName() == bundleFormField {
+				body = part
+				break
+			}
+		}
+	}
+
+	archive, err := io.ReadAll(io.LimitReader(body, maxSize+1))
+	if err != nil {
+		return nil, bundleReadError(err, maxSize)
+	}
+	if int64(len(archive)) > maxSize {
+		return nil, errs.BundleTooLarge("size", maxSize)
+	}
+	if len(archive) == 0 {
+		return nil, errs.NewValidationError("policy bundle is empty", nil)
+	}
+	return archive, nil
+}
+
+// bundleReadError converts a failed upload read into an API error
+func bundleReadError(err error, maxSize int64) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return errs.BundleTooLarge("size", maxSize)
+	}
+	return errs.NewValidationError("failed to read policy bundle", map[string]any{"error": err.Error()})
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wso2/policyhub/internal/errs"
+	"github.com/wso2/policyhub/internal/http/dto"
+	"github.com/wso2/policyhub/internal/http/middleware"
+	"github.com/wso2/policyhub/internal/logging"
+	"github.com/wso2/policyhub/internal/policy"
+	"github.com/wso2/policyhub/internal/validation"
+)
+
+// LifecycleHandler handles policy version lifecycle operations (deprecate, yank, un-yank)
+type LifecycleHandler struct {
+	service *policy.Service
+	logger  *logging.Logger
+}
+
+// NewLifecycleHandler creates a new lifecycle handler
+func NewLifecycleHandler(service *policy.Service, logger *logging.Logger) *LifecycleHandler {
+	return &LifecycleHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// DeprecateVersion handles POST /internal/policies/{name}/versions/{version}/deprecate
+func (h *LifecycleHandler) DeprecateVersion(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	var req dto.DeprecateVersionRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(errs.NewValidationError("invalid request body", map[string]any{"error": err.Error()}))
+		return
+	}
+
+	if err := validateStatusReason(req.Reason); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if req.ReplacementVersion != "" {
+		if err := validation.ValidateVersion(req.ReplacementVersion); err != nil {
+			_ = c.Error(err)
+			return
+		}
+	}
+
+	if err := h.authorize(c, name, version); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	updated, err := h.service.DeprecatePolicyVersion(c.Request.Context(), name, version, req.Reason, req.ReplacementVersion)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	middleware.SendSuccess(c, toPolicyDTO(updated))
+}
+
+// YankVersion handles POST /internal/policies/{name}/versions/{version}/yank
+func (h *LifecycleHandler) YankVersion(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	var req dto.YankVersionRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(errs.NewValidationError("invalid request body", map[string]any{"error": err.Error()}))
+		return
+	}
+
+	if err := validateStatusReason(req.Reason); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err := h.authorize(c, name, version); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	updated, err := h.service.YankPolicyVersion(c.Request.Context(), name, version, req.Reason)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	middleware.SendSuccess(c, toPolicyDTO(updated))
+}
+
+// UnyankVersion handles POST /internal/policies/{name}/versions/{version}/unyank
+func (h *LifecycleHandler) UnyankVersion(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	if err := h.authorize(c, name, version); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	updated, err := h.service.UnyankPolicyVersion(c.Request.Context(), name, version)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	middleware.SendSuccess(c, toPolicyDTO(updated))
+}
+
+// authorize checks that the caller may manage versions of the policy's provider
+func (h *LifecycleHandler) authorize(c *gin.Context, name, version string) error {
+	existing, err := h.service.GetPolicyVersion(c.Request.Context(), name, version)
+	if err != nil {
+		return err
+	}
+	return middleware.AuthorizeProvider(c, existing.Provider)
+}
+
+// validateStatusReason enforces the maximum length of a status change reason
+func validateStatusReason(reason string) *errs.AppError {
+	if len(reason) > policy.MaxStatusReasonLength {
+		return errs.NewValidationError(
+			fmt.Sprintf("reason too long (max %d characters)", policy.MaxStatusReasonLength),
+			map[string]any{"maxLength": policy.MaxStatusReasonLength},
+		)
+	}
+	return nil
+}
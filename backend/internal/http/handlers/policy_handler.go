@@ -10,13 +10,20 @@
 package handlers
 
 import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
+	"github.com/wso2/policyhub/internal/errs"
 	"github.com/wso2/policyhub/internal/http/dto"
 	"github.com/wso2/policyhub/internal/http/middleware"
 	"github.com/wso2/policyhub/internal/logging"
@@ -39,16 +46,35 @@ func NewPolicyHandler(service *policy.Service, logger *logging.Logger) *PolicyHa
 
 // ListPolicies handles GET /policies
 func (h *PolicyHandler) ListPolicies(c *gin.Context) {
+	page, pageErr := parsePageRequest(c)
+	if pageErr != nil {
+		_ = c.Error(pageErr)
+		return
+	}
 	filters := policy.PolicyFilters{
-		Search:     c.Query("search"),
-		Categories: parseCommaSeparatedValues(c, "category", "categories"),
-		Providers:  parseCommaSeparatedValues(c, "provider", "providers"),
-		Platforms:  parseCommaSeparatedValues(c, "platform", "platforms"),
-		Page:       getIntQuery(c, "page", 1),
-		PageSize:   getIntQuery(c, "pageSize", 20),
+		Search:      strings.TrimSpace(c.Query("search")),
+		Categories:  parseCommaSeparatedValues(c, "category", "categories"),
+		Providers:   parseCommaSeparatedValues(c, "provider", "providers"),
+		Platforms:   parseCommaSeparatedValues(c, "platform", "platforms"),
+		Tags:        parseCommaSeparatedValues(c, "tag", "tags"),
+		PageRequest: page,
+	}
+
+	if err := parseFilterOptions(c, &filters); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if err := parseSort(c, &filters); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	facets, facetErr := parseFacets(c.Query("facets"))
+	if facetErr != nil {
+		_ = c.Error(facetErr)
+		return
 	}
 
-	policies, pagination, err := h.service.ListPolicies(c.Request.Context(), filters)
+	policies, pagination, counts, err := h.service.ListPolicies(c.Request.Context(), filters, facets)
 	if err != nil {
 		_ = c.Error(err)
 		return
@@ -60,14 +86,152 @@ func (h *PolicyHandler) ListPolicies(c *gin.Context) {
 		items = append(items, toPolicyDTO(p))
 	}
 
-	paginationDTO := dto.PaginationDTO{
+	paginationDTO := toPaginationDTO(pagination)
+
+	if len(facets) == 0 {
+		middleware.SendSuccessWithPagination(c, items, paginationDTO)
+		return
+	}
+
+	facetsDTO := make(dto.FacetsDTO, len(counts))
+	for facet, values := range counts {
+		valueDTOs := make([]dto.FacetCountDTO, 0, len(values))
+		for _, value := range values {
+			valueDTOs = append(valueDTOs, dto.FacetCountDTO{Value: value.Value, Count: value.Count})
+		}
+		facetsDTO[string(facet)] = valueDTOs
+	}
+
+	middleware.SendSuccessWithFacets(c, items, paginationDTO, facetsDTO)
+}
+
+// parsePageRequest reads the page, pageSize, cursor and includeTotal query
+// parameters. A cursor takes precedence over page; totals are counted by
+// default only for pages requested by number.
+func parsePageRequest(c *gin.Context) (policy.PageRequest, *errs.AppError) {
+	page := policy.PageRequest{
+		Page:     getIntQuery(c, "page", 1),
+		PageSize: getIntQuery(c, "pageSize", policy.DefaultPageSize),
+		Cursor:   c.Query("cursor"),
+	}
+	page.IncludeTotal = page.Cursor == ""
+	if value := c.Query("includeTotal"); value != "" {
+		includeTotal, err := strconv.ParseBool(value)
+		if err != nil {
+			return page, errs.NewValidationError("includeTotal must be true or false", map[string]any{"includeTotal": value})
+		}
+		page.IncludeTotal = includeTotal
+	}
+	return page, nil
+}
+
+// toPaginationDTO converts pagination metadata to its DTO
+func toPaginationDTO(pagination *policy.PaginationInfo) dto.PaginationDTO {
+	return dto.PaginationDTO{
 		Page:       pagination.Page,
 		PageSize:   pagination.PageSize,
 		TotalItems: pagination.TotalItems,
 		TotalPages: pagination.TotalPages,
+		Next:       pagination.Next,
+		Prev:       pagination.Prev,
 	}
+}
 
-	middleware.SendSuccessWithPagination(c, items, paginationDTO)
+// parseFilterOptions reads the match modes of the multi-valued filters and
+// the release date bounds into filters; unset modes are left for the service
+// to default
+func parseFilterOptions(c *gin.Context, filters *policy.PolicyFilters) *errs.AppError {
+	modes := []struct {
+		param string
+		mode  *policy.FilterMatch
+	}{
+		{"categoriesMatch", &filters.CategoriesMatch},
+		{"platformsMatch", &filters.PlatformsMatch},
+		{"tagsMatch", &filters.TagsMatch},
+	}
+	for _, m := range modes {
+		value := c.Query(m.param)
+		if value == "" {
+			continue
+		}
+		*m.mode = policy.FilterMatch(value)
+		if *m.mode != policy.MatchAny && *m.mode != policy.MatchAll {
+			return errs.NewValidationError(m.param+" must be any or all", map[string]any{m.param: value})
+		}
+	}
+
+	dates := []struct {
+		param string
+		date  **time.Time
+	}{
+		{"releasedAfter", &filters.ReleasedAfter},
+		{"releasedBefore", &filters.ReleasedBefore},
+	}
+	for _, d := range dates {
+		value := c.Query(d.param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return errs.NewValidationError(d.param+" must be a date in YYYY-MM-DD format", map[string]any{d.param: value})
+		}
+		*d.date = &parsed
+	}
+	if filters.ReleasedAfter != nil && filters.ReleasedBefore != nil && filters.ReleasedAfter.After(*filters.ReleasedBefore) {
+		return errs.NewValidationError("releasedAfter must not be later than releasedBefore", map[string]any{
+			"releasedAfter":  c.Query("releasedAfter"),
+			"releasedBefore": c.Query("releasedBefore"),
+		})
+	}
+	return nil
+}
+
+// parseSort reads the sort and order query parameters into filters; unset
+// parameters are left for the service to default
+func parseSort(c *gin.Context, filters *policy.PolicyFilters) *errs.AppError {
+	if sort := c.Query("sort"); sort != "" {
+		filters.Sort = policy.PolicySort(sort)
+		if !slices.Contains(policy.PolicySorts, filters.Sort) {
+			return errs.NewValidationError("unknown sort key", map[string]any{
+				"sort":    sort,
+				"allowed": policy.PolicySorts,
+			})
+		}
+	}
+	if order := c.Query("order"); order != "" {
+		filters.Order = policy.SortOrder(order)
+		if filters.Order != policy.SortAscending && filters.Order != policy.SortDescending {
+			return errs.NewValidationError("order must be asc or desc", map[string]any{"order": order})
+		}
+	}
+	return nil
+}
+
+// parseFacets parses the comma-separated facets query parameter; "all"
+// requests every facet
+func parseFacets(value string) ([]policy.Facet, *errs.AppError) {
+	var facets []policy.Facet
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if name == "all" {
+			return policy.Facets, nil
+		}
+		facet := policy.Facet(name)
+		if !slices.Contains(policy.Facets, facet) {
+			return nil, errs.NewValidationError("unknown facet", map[string]any{
+				"facet":   name,
+				"allowed": policy.Facets,
+			})
+		}
+		if !slices.Contains(facets, facet) {
+			facets = append(facets, facet)
+		}
+	}
+	return facets, nil
 }
 
 // GetPolicySummary handles GET /policies/{name}
@@ -87,10 +251,13 @@ func (h *PolicyHandler) GetPolicySummary(c *gin.Context) {
 // ListPolicyVersions handles GET /policies/{name}/versions
 func (h *PolicyHandler) ListPolicyVersions(c *gin.Context) {
 	name := c.Param("name")
-	page := getIntQuery(c, "page", 1)
-	pageSize := getIntQuery(c, "pageSize", 20)
+	page, pageErr := parsePageRequest(c)
+	if pageErr != nil {
+		_ = c.Error(pageErr)
+		return
+	}
 
-	versions, pagination, err := h.service.ListPolicyVersions(c.Request.Context(), name, page, pageSize)
+	versions, pagination, err := h.service.ListPolicyVersions(c.Request.Context(), name, page)
 	if err != nil {
 		_ = c.Error(err)
 		return
@@ -102,12 +269,7 @@ func (h *PolicyHandler) ListPolicyVersions(c *gin.Context) {
 		items = append(items, toPolicyDTO(v))
 	}
 
-	paginationDTO := dto.PaginationDTO{
-		Page:       pagination.Page,
-		PageSize:   pagination.PageSize,
-		TotalItems: pagination.TotalItems,
-		TotalPages: pagination.TotalPages,
-	}
+	paginationDTO := toPaginationDTO(pagination)
 
 	middleware.SendSuccessWithPagination(c, items, paginationDTO)
 }
@@ -143,19 +305,51 @@ func (h *PolicyHandler) GetPolicyVersionDetail(c *gin.Context) {
 	middleware.SendSuccess(c, policyData)
 }
 
+// definitionMediaTypes are the media types a definition is served as. The
+// first, served when the Accept header allows none of them, is the published
+// form as it was served before content negotiation.
+var definitionMediaTypes = []string{"text/yaml", "application/yaml", "application/json"}
+
 // GetPolicyDefinition handles GET /policies/{name}/versions/{version}/definition
 func (h *PolicyHandler) GetPolicyDefinition(c *gin.Context) {
 	name := c.Param("name")
 	version := c.Param("version")
 
-	definition, err := h.service.GetPolicyDefinition(c.Request.Context(), name, version)
+	mediaType := c.NegotiateFormat(definitionMediaTypes...)
+	if mediaType == "" {
+		mediaType = definitionMediaTypes[0]
+	}
+	format := policy.DefinitionFormatYAML
+	if mediaType == "application/json" {
+		format = policy.DefinitionFormatJSON
+	}
+
+	definition, digest, err := h.service.GetPolicyDefinition(c.Request.Context(), name, version, format)
 	if err != nil {
 		_ = c.Error(err)
 		return
 	}
 
-	// Return raw YAML without envelope
-	c.Data(200, "text/yaml", definition)
+	// Published definitions are immutable, so the digest doubles as a strong ETag;
+	// the JSON form gets its own, and a Digest of its own body
+	etag := `"` + digest + `"`
+	bodyDigest := digest
+	if format == policy.DefinitionFormatJSON {
+		etag = `"` + digest + `+json"`
+		bodyDigest = policy.ComputeDigest(definition)
+	}
+	c.Header("Vary", "Accept")
+	c.Header("ETag", etag)
+	if header := digestHeader(bodyDigest); header != "" {
+		c.Header("Digest", header)
+	}
+	if match := c.GetHeader("If-None-Match"); match != "" && (match == etag || match == "*") {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	// Return the definition without envelope
+	c.Data(200, mediaType, definition)
 }
 
 // GetPolicyForEngine handles GET /policies/{name}/versions/{version}/engine
@@ -163,6 +357,12 @@ func (h *PolicyHandler) GetPolicyForEngine(c *gin.Context) {
 	name := c.Param("name")
 	version := c.Param("version")
 
+	definitionFormat := c.DefaultQuery("definitionFormat", dto.DefinitionFormatString)
+	if err := validateDefinitionFormat(definitionFormat); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
 	// Get policy version (contains all needed data)
 	policyVersion, err := h.service.GetPolicyVersion(c.Request.Context(), name, version)
 	if err != nil {
@@ -171,9 +371,34 @@ func (h *PolicyHandler) GetPolicyForEngine(c *gin.Context) {
 	}
 
 	response := toPolicyWithDefinitionDTO(policyVersion)
+	if definitionFormat == dto.DefinitionFormatObject {
+		if response.Definition, err = h.parsedDefinition(policyVersion); err != nil {
+			_ = c.Error(err)
+			return
+		}
+	}
+	h.service.RecordDownload(c.Request.Context(), name)
 	middleware.SendSuccess(c, response)
 }
 
+// validateDefinitionFormat checks the definitionFormat option of the engine and resolve endpoints
+func validateDefinitionFormat(format string) *errs.AppError {
+	if format != dto.DefinitionFormatString && format != dto.DefinitionFormatObject {
+		return errs.NewValidationError("definitionFormat must be string or object", map[string]any{"definitionFormat": format})
+	}
+	return nil
+}
+
+// parsedDefinition returns a version's definition for embedding as an object
+func (h *PolicyHandler) parsedDefinition(v *policy.PolicyVersion) (json.RawMessage, error) {
+	definition, err := h.service.DefinitionJSON(v)
+	if err != nil {
+		h.logger.Error("Failed to convert policy definition", zap.Error(err))
+		return nil, errs.NewInternalError("Failed to convert policy definition", nil)
+	}
+	return definition, nil
+}
+
 // GetAllDocs handles GET /policies/{name}/versions/{version}/docs
 func (h *PolicyHandler) GetAllDocs(c *gin.Context) {
 	name := c.Param("name")
@@ -245,39 +470,30 @@ func (h *PolicyHandler) GetPlatforms(c *gin.Context) {
 
 // ResolvePolicies handles POST /policies/resolve
 func (h *PolicyHandler) ResolvePolicies(c *gin.Context) {
-	var request dto.ResolvePolicyRequestDTO
-	if err := c.ShouldBindJSON(&request); err != nil {
-		_ = c.Error(err)
+	request, serviceRequests, ok := bindResolveRequest(c)
+	if !ok {
 		return
 	}
+	if request.DefinitionFormat != "" {
+		if err := validateDefinitionFormat(request.DefinitionFormat); err != nil {
+			_ = c.Error(err)
+			return
+		}
+	}
 
-	// Validate batch size
-	batchSize := len(request.Policies)
+	// Call service
+	results, resolveErrors := h.service.ResolvePolicies(c.Request.Context(), serviceRequests)
 
-	// Enforce maximum batch size limit
-	if batchSize > policy.MaxBatchSize {
-		c.JSON(400, gin.H{
-			"error":    fmt.Sprintf("Batch size %d exceeds maximum limit of %d policies", batchSize, policy.MaxBatchSize),
-			"max_size": policy.MaxBatchSize,
-		})
-		return
-	}
+	errorData := toPolicyErrorDTOs(resolveErrors)
 
-	// Convert DTOs to service types
-	serviceRequests := make([]policy.ResolvePolicyRequest, 0, len(request.Policies))
-	for _, req := range request.Policies {
-		serviceRequests = append(serviceRequests, policy.ResolvePolicyRequest{
-			Name:              req.Name,
-			RetrievalStrategy: req.RetrievalStrategy,
-			BaseVersion:       req.BaseVersion,
-		})
+	// In strict mode any failed item fails the whole call
+	if request.Strict && len(errorData) > 0 {
+		_ = c.Error(errs.ResolveFailed(len(errorData), errorData))
+		return
 	}
 
-	// Call service
-	results, errors := h.service.ResolvePolicies(c.Request.Context(), serviceRequests)
-
 	// Convert results to DTOs
-	responseData := make([]dto.PolicyWithDefinitionDTO, 0, len(results))
+	responseData := make([]dto.ResolvedPolicyDTO, 0, len(results))
 	for _, result := range results {
 		// Extract the YAML definition from the batch result
 		yamlStr, ok := result.Definition["yaml"].(string)
@@ -304,29 +520,211 @@ func (h *PolicyHandler) ResolvePolicies(c *gin.Context) {
 			sourceURL = *result.Metadata.SourceURL
 		}
 
-		responseData = append(responseData, dto.PolicyWithDefinitionDTO{
-			Name:        result.Name,
-			Version:     result.Version,
-			DisplayName: result.Metadata.DisplayName,
-			Provider:    result.Metadata.Provider,
-			Categories:  result.Metadata.Categories,
-			ReleaseDate: releaseDate,
-			IsLatest:    result.Metadata.IsLatest,
-			SourceType:  sourceType,
-			SourceURL:   sourceURL,
-			Definition:  yamlStr,
+		replacementVersion := ""
+		if result.Metadata.ReplacementVersion != nil {
+			replacementVersion = *result.Metadata.ReplacementVersion
+		}
+
+		artifactDigest := ""
+		if result.Metadata.ArtifactDigest != nil {
+			artifactDigest = *result.Metadata.ArtifactDigest
+		}
+
+		signatureKeyID := ""
+		if result.Metadata.SignatureKeyID != nil {
+			signatureKeyID = *result.Metadata.SignatureKeyID
+		}
+
+		var definition any = yamlStr
+		if request.DefinitionFormat == dto.DefinitionFormatObject {
+			parsed, err := h.parsedDefinition(result.Metadata)
+			if err != nil {
+				_ = c.Error(err)
+				return
+			}
+			definition = parsed
+		}
+
+		responseData = append(responseData, dto.ResolvedPolicyDTO{Index: result.Index, PolicyWithDefinitionDTO: dto.PolicyWithDefinitionDTO{
+			Name:               result.Name,
+			Version:            result.Version,
+			DisplayName:        result.Metadata.DisplayName,
+			Provider:           result.Metadata.Provider,
+			Categories:         result.Metadata.Categories,
+			ReleaseDate:        releaseDate,
+			IsLatest:           result.Metadata.IsLatest,
+			SourceType:         sourceType,
+			SourceURL:          sourceURL,
+			Status:             string(result.Metadata.Status),
+			ReplacementVersion: replacementVersion,
+			Warning:            result.Metadata.StatusWarning(),
+			DefinitionDigest:   result.Metadata.DefinitionDigest,
+			ArtifactDigest:     artifactDigest,
+			SignatureStatus:    string(result.Metadata.SignatureStatus),
+			SignatureKeyID:     signatureKeyID,
+			Definition:         definition,
+		}})
+	}
+
+	middleware.SendSuccessWithErrors(c, responseData, errorData)
+}
+
+// LockPolicies handles POST /policies/lock
+func (h *PolicyHandler) LockPolicies(c *gin.Context) {
+	request, serviceRequests, ok := bindResolveRequest(c)
+	if !ok {
+		return
+	}
+
+	locked, resolveErrors := h.service.LockPolicies(c.Request.Context(), serviceRequests)
+
+	errorData := toPolicyErrorDTOs(resolveErrors)
+
+	// In strict mode any failed item fails the whole call
+	if request.Strict && len(errorData) > 0 {
+		_ = c.Error(errs.ResolveFailed(len(errorData), errorData))
+		return
+	}
+
+	generatedAt := time.Now().UTC()
+	lockfile := dto.LockfileDTO{
+		LockfileVersion: policy.LockfileVersion,
+		GeneratedAt:     &generatedAt,
+		Policies:        make([]dto.LockedPolicyDTO, 0, len(locked)),
+	}
+	for _, entry := range locked {
+		lockfile.Policies = append(lockfile.Policies, dto.LockedPolicyDTO{
+			Name:        entry.Name,
+			Version:     entry.Version,
+			Digest:      entry.Digest,
+			DownloadURL: entry.DownloadURL,
+		})
+	}
+
+	middleware.SendSuccessWithErrors(c, lockfile, errorData)
+}
+
+// VerifyLockfile handles POST /policies/lock/verify
+func (h *PolicyHandler) VerifyLockfile(c *gin.Context) {
+	var lockfile dto.LockfileDTO
+	if err := c.ShouldBindJSON(&lockfile); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if lockfile.LockfileVersion != policy.LockfileVersion {
+		_ = c.Error(errs.NewValidationError("unsupported lockfile version", map[string]any{
+			"lockfileVersion":   lockfile.LockfileVersion,
+			"supportedVersions": []int{policy.LockfileVersion},
+		}))
+		return
+	}
+
+	if len(lockfile.Policies) > policy.MaxBatchSize {
+		_ = c.Error(errs.NewValidationError(
+			fmt.Sprintf("Lockfile has %d policies, exceeding the maximum of %d", len(lockfile.Policies), policy.MaxBatchSize),
+			map[string]any{"maxSize": policy.MaxBatchSize},
+		))
+		return
+	}
+
+	locked := make([]policy.LockedPolicy, 0, len(lockfile.Policies))
+	for i, entry := range lockfile.Policies {
+		locked = append(locked, policy.LockedPolicy{
+			Index:       i,
+			Name:        entry.Name,
+			Version:     entry.Version,
+			Digest:      entry.Digest,
+			DownloadURL: entry.DownloadURL,
 		})
 	}
 
-	// For now, we'll ignore errors in the batch response as per the streamlined approach
-	// In the future, we might want to include error handling if needed
-	_ = errors
+	verifications, err := h.service.VerifyLockfile(c.Request.Context(), locked)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response := dto.LockVerificationDTO{
+		InSync:   true,
+		Policies: make([]dto.LockedPolicyStatusDTO, 0, len(verifications)),
+	}
+	for _, v := range verifications {
+		if v.Drifted() {
+			response.InSync = false
+		}
+		response.Policies = append(response.Policies, dto.LockedPolicyStatusDTO{
+			Index:          v.Index,
+			Name:           v.Name,
+			Version:        v.Version,
+			Status:         string(v.Status),
+			ActualVersion:  v.ActualVersion,
+			ExpectedDigest: v.ExpectedDigest,
+			ActualDigest:   v.ActualDigest,
+			Message:        v.Message,
+		})
+	}
 
-	middleware.SendSuccess(c, responseData)
+	middleware.SendSuccess(c, response)
 }
 
 // Helper functions
 
+// bindResolveRequest binds a resolve-style request body, enforces the batch size
+// limit and converts the items to service requests. It reports false when a
+// response has already been written.
+func bindResolveRequest(c *gin.Context) (*dto.ResolvePolicyRequestDTO, []policy.ResolvePolicyRequest, bool) {
+	var request dto.ResolvePolicyRequestDTO
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err)
+		return nil, nil, false
+	}
+
+	// Validate batch size
+	batchSize := len(request.Policies)
+
+	// Enforce maximum batch size limit
+	if batchSize > policy.MaxBatchSize {
+		c.JSON(400, gin.H{
+			"error":    fmt.Sprintf("Batch size %d exceeds maximum limit of %d policies", batchSize, policy.MaxBatchSize),
+			"max_size": policy.MaxBatchSize,
+		})
+		return nil, nil, false
+	}
+
+	// Convert DTOs to service types
+	serviceRequests := make([]policy.ResolvePolicyRequest, 0, len(request.Policies))
+	for _, req := range request.Policies {
+		serviceRequests = append(serviceRequests, policy.ResolvePolicyRequest{
+			Name:              req.Name,
+			RetrievalStrategy: req.RetrievalStrategy,
+			BaseVersion:       req.BaseVersion,
+			Constraint:        req.Constraint,
+			IncludePrerelease: req.IncludePrerelease,
+			VerifiedOnly:      request.VerifiedOnly,
+		})
+	}
+
+	return &request, serviceRequests, true
+}
+
+// toPolicyErrorDTOs converts per-item resolve errors to DTOs
+func toPolicyErrorDTOs(resolveErrors []policy.PolicyResolveError) []dto.PolicyErrorDTO {
+	errorData := make([]dto.PolicyErrorDTO, 0, len(resolveErrors))
+	for _, resolveErr := range resolveErrors {
+		errorData = append(errorData, dto.PolicyErrorDTO{
+			Index:             resolveErr.Index,
+			Name:              resolveErr.Name,
+			RetrievalStrategy: resolveErr.Strategy,
+			BaseVersion:       resolveErr.BaseVersion,
+			Constraint:        resolveErr.Constraint,
+			Code:              string(resolveErr.Code),
+			Message:           resolveErr.Error,
+		})
+	}
+	return errorData
+}
+
 func getIntQuery(c *gin.Context, key string, defaultValue int) int {
 	valueStr := c.Query(key)
 	if valueStr == "" {
@@ -377,6 +775,26 @@ func toPolicyDTO(v *policy.PolicyVersion) dto.PolicyDTO {
 		sourceURL = *v.SourceURL
 	}
 
+	statusReason := ""
+	if v.StatusReason != nil {
+		statusReason = *v.StatusReason
+	}
+
+	replacementVersion := ""
+	if v.ReplacementVersion != nil {
+		replacementVersion = *v.ReplacementVersion
+	}
+
+	artifactDigest := ""
+	if v.ArtifactDigest != nil {
+		artifactDigest = *v.ArtifactDigest
+	}
+
+	signatureKeyID := ""
+	if v.SignatureKeyID != nil {
+		signatureKeyID = *v.SignatureKeyID
+	}
+
 	return dto.PolicyDTO{
 		Name:               v.PolicyName,
 		Version:            v.Version,
@@ -393,6 +811,14 @@ func toPolicyDTO(v *policy.PolicyVersion) dto.PolicyDTO {
 		IsLatest:           v.IsLatest,
 		SourceType:         sourceType,
 		SourceURL:          sourceURL,
+		Status:             string(v.Status),
+		StatusReason:       statusReason,
+		ReplacementVersion: replacementVersion,
+		DefinitionDigest:   v.DefinitionDigest,
+		ArtifactDigest:     artifactDigest,
+		SignatureStatus:    string(v.SignatureStatus),
+		SignatureKeyID:     signatureKeyID,
+		Snippet:            v.SearchSnippet,
 	}
 }
 
@@ -413,18 +839,49 @@ func toPolicyWithDefinitionDTO(v *policy.PolicyVersion) dto.PolicyWithDefinition
 		releaseDate = &dateStr
 	}
 
+	replacementVersion := ""
+	if v.ReplacementVersion != nil {
+		replacementVersion = *v.ReplacementVersion
+	}
+
+	artifactDigest := ""
+	if v.ArtifactDigest != nil {
+		artifactDigest = *v.ArtifactDigest
+	}
+
+	signatureKeyID := ""
+	if v.SignatureKeyID != nil {
+		signatureKeyID = *v.SignatureKeyID
+	}
+
 	return dto.PolicyWithDefinitionDTO{
-		Name:        v.PolicyName,
-		Version:     v.Version,
-		DisplayName: v.DisplayName,
-		Provider:    v.Provider,
-		Categories:  v.Categories,
-		ReleaseDate: releaseDate,
-		IsLatest:    v.IsLatest,
-		SourceType:  sourceType,
-		SourceURL:   sourceURL,
-		Definition:  v.DefinitionYAML,
+		Name:               v.PolicyName,
+		Version:            v.Version,
+		DisplayName:        v.DisplayName,
+		Provider:           v.Provider,
+		Categories:         v.Categories,
+		ReleaseDate:        releaseDate,
+		IsLatest:           v.IsLatest,
+		SourceType:         sourceType,
+		SourceURL:          sourceURL,
+		Status:             string(v.Status),
+		ReplacementVersion: replacementVersion,
+		Warning:            v.StatusWarning(),
+		DefinitionDigest:   v.DefinitionDigest,
+		ArtifactDigest:     artifactDigest,
+		SignatureStatus:    string(v.SignatureStatus),
+		SignatureKeyID:     signatureKeyID,
+		Definition:         v.DefinitionYAML,
+	}
+}
+
+// digestHeader converts a "sha256:<hex>" digest into an RFC 3230 Digest header value
+func digestHeader(digest string) string {
+	sum, err := hex.DecodeString(strings.TrimPrefix(digest, policy.DigestPrefix))
+	if err != nil || !strings.HasPrefix(digest, policy.DigestPrefix) {
+		return ""
 	}
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(sum)
 }
 
 func toDocsAllResponseDTO(docs map[string]string) dto.DocsAllResponseDTO {
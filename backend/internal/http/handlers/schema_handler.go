@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wso2/policyhub/internal/errs"
+	"github.com/wso2/policyhub/internal/validation"
+)
+
+// SchemaHandler serves the schemas published policies are validated against
+type SchemaHandler struct{}
+
+// NewSchemaHandler creates a new schema handler
+func NewSchemaHandler() *SchemaHandler {
+	return &SchemaHandler{}
+}
+
+// GetDefinitionSchema handles GET /schemas/policy-definition and
+// GET /schemas/policy-definition/{version}. Without a version the current
+// schema is served.
+func (h *SchemaHandler) GetDefinitionSchema(c *gin.Context) {
+	version := c.Param("version")
+	if version == "" {
+		version = validation.CurrentDefinitionSchemaVersion
+	}
+
+	schema, ok := validation.DefinitionSchema(version)
+	if !ok {
+		_ = c.Error(errs.SchemaNotFound(version))
+		return
+	}
+
+	// A published schema version never changes; the current version may move on
+	if c.Param("version") != "" {
+		c.Header("Cache-Control", "public, max-age=86400")
+	} else {
+		c.Header("Cache-Control", "public, max-age=300")
+	}
+	c.Header("X-Schema-Version", version)
+
+	// Return the raw schema without envelope
+	c.Data(http.StatusOK, "application/schema+json", schema)
+}
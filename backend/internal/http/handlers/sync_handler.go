@@ -10,6 +10,9 @@
 package handlers
 
 import (
+	"fmt"
+	"strconv"
+
 	"github.com/gin-gonic/gin"
 
 	"github.com/wso2/policyhub/internal/errs"
@@ -17,6 +20,7 @@ import (
 	"github.com/wso2/policyhub/internal/http/middleware"
 	"github.com/wso2/policyhub/internal/logging"
 	"github.com/wso2/policyhub/internal/policy"
+	"github.com/wso2/policyhub/internal/signing"
 	"github.com/wso2/policyhub/internal/sync"
 	"github.com/wso2/policyhub/internal/validation"
 )
@@ -67,7 +71,7 @@ func (h *SyncHandler) CreatePolicyVersion(c *gin.Context) {
 	req.PolicyName = policyName
 	req.Version = version
 
-	// Validate version format (must be d.d.d)
+	// Validate version format (must be a SemVer 2.0 version)
 	if err := validation.ValidateVersion(req.Version); err != nil {
 		_ = c.Error(err)
 		return
@@ -79,6 +83,12 @@ func (h *SyncHandler) CreatePolicyVersion(c *gin.Context) {
 		return
 	}
 
+	// Callers may only publish under providers they hold a scope for
+	if err := middleware.AuthorizeProvider(c, req.Metadata.Provider); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
 	// Validate documentation types
 	if req.Documentation != nil {
 		validDocTypes := policy.ValidDocTypes()
@@ -109,21 +119,153 @@ func (h *SyncHandler) CreatePolicyVersion(c *gin.Context) {
 			BannerURL:          req.Metadata.BannerURL,
 		},
 		Documentation: req.Documentation,
+		RequireDocs:   req.RequireDocs,
 		AssetsBaseURL: req.AssetsBaseURL,
 	}
 
-	// Execute sync
-	result, err := h.syncService.SyncPolicy(c.Request.Context(), syncReq)
+	if req.Signature != nil {
+		syncReq.Signature = &signing.Signature{
+			KeyID:     req.Signature.KeyID,
+			Algorithm: req.Signature.Algorithm,
+			Value:     req.Signature.Value,
+		}
+	}
+
+	// Queue the sync; workers run it outside the request
+	job, err := h.syncService.SubmitSync(c.Request.Context(), syncReq)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/api/v1/internal/sync-jobs/%d", job.ID))
+	middleware.SendAccepted(c, toSyncJobDTO(job))
+}
+
+// GetSyncJob handles GET /internal/sync-jobs/{id}
+func (h *SyncHandler) GetSyncJob(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id < 1 {
+		_ = c.Error(errs.NewValidationError("invalid sync job id", map[string]any{"id": c.Param("id")}))
+		return
+	}
+
+	job, err := h.syncService.GetJob(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// Jobs are visible to callers that may publish for the job's provider
+	if err := middleware.AuthorizeProvider(c, job.Provider); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	middleware.SendSuccess(c, toSyncJobDTO(job))
+}
+
+// ScanSource handles POST /internal/sync-source/scan
+func (h *SyncHandler) ScanSource(c *gin.Context) {
+	// A scan publishes for every provider in the source
+	if err := middleware.AuthorizeAdmin(c); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	result, err := h.syncService.ScanSource(c.Request.Context())
 	if err != nil {
 		_ = c.Error(err)
 		return
 	}
 
-	response := dto.SyncResponseDTO{
-		PolicyName: result.PolicyName,
-		Version:    result.Version,
-		Status:     result.Status,
+	scanDTO := dto.SourceScanDTO{
+		Revision:   result.Revision,
+		Found:      result.Found,
+		Existing:   result.Existing,
+		InProgress: result.InProgress,
+		Failed:     result.Failed,
+		Queued:     make([]dto.SourceScanJobDTO, 0, len(result.Queued)),
+		Errors:     make([]dto.SourceScanErrorDTO, 0, len(result.Errors)),
+	}
+	for _, queued := range result.Queued {
+		scanDTO.Queued = append(scanDTO.Queued, dto.SourceScanJobDTO{
+			PolicyName: queued.PolicyName,
+			Version:    queued.Version,
+			JobID:      queued.JobID,
+		})
+	}
+	for _, scanErr := range result.Errors {
+		scanDTO.Errors = append(scanDTO.Errors, dto.SourceScanErrorDTO{
+			PolicyName: scanErr.PolicyName,
+			Version:    scanErr.Version,
+			Message:    scanErr.Message,
+		})
+	}
+
+	middleware.SendSuccess(c, scanDTO)
+}
+
+// toSyncJobDTO converts a sync job to its response DTO
+func toSyncJobDTO(job *sync.Job) dto.SyncJobDTO {
+	jobDTO := dto.SyncJobDTO{
+		ID:          job.ID,
+		PolicyName:  job.PolicyName,
+		Version:     job.Version,
+		Status:      string(job.Status),
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		Steps:       make([]dto.SyncJobStepDTO, 0, len(job.Steps)),
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+		StartedAt:   job.StartedAt,
+		FinishedAt:  job.FinishedAt,
+	}
+
+	for _, step := range job.Steps {
+		jobDTO.Steps = append(jobDTO.Steps, dto.SyncJobStepDTO{
+			Name:       step.Name,
+			Status:     string(step.Status),
+			StartedAt:  step.StartedAt,
+			FinishedAt: step.FinishedAt,
+			Error:      step.Error,
+		})
+	}
+
+	if job.Status == sync.JobQueued {
+		nextRunAt := job.NextRunAt
+		jobDTO.NextRunAt = &nextRunAt
+	}
+
+	if job.Result != nil {
+		jobDTO.Result = &dto.SyncResponseDTO{
+			PolicyName:       job.Result.PolicyName,
+			Version:          job.Result.Version,
+			Status:           job.Result.Status,
+			DefinitionDigest: job.Result.DefinitionDigest,
+			ArtifactDigest:   job.Result.ArtifactDigest,
+			SignatureStatus:  string(job.Result.SignatureStatus),
+			Warning:          job.Result.Warning,
+		}
+		for _, doc := range job.Result.Docs {
+			jobDTO.Result.Docs = append(jobDTO.Result.Docs, dto.SyncDocResultDTO{
+				Page:     doc.Page,
+				Status:   string(doc.Status),
+				Cached:   doc.Cached,
+				Attempts: doc.Attempts,
+				Error:    doc.Error,
+			})
+		}
+	}
+
+	// A queued job's error is from its last failed attempt, a failed job's is final
+	if job.LastError != nil {
+		jobDTO.Error = &dto.ErrorDTO{
+			Code:    job.LastError.Code,
+			Message: job.LastError.Message,
+			Details: job.LastError.Details,
+		}
 	}
 
-	middleware.SendSuccess(c, response)
+	return jobDTO
 }
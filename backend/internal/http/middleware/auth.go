@@ -0,0 +1,195 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/wso2/policyhub/internal/config"
+	"github.com/wso2/policyhub/internal/errs"
+	"github.com/wso2/policyhub/internal/logging"
+)
+
+const (
+	// ScopeAdmin grants every internal operation for every provider
+	ScopeAdmin = "policies:admin"
+	// ScopePublishPrefix is followed by a provider name, or "*" for all providers
+	ScopePublishPrefix = "policies:publish:"
+
+	principalContextKey = "principal"
+)
+
+// ErrNoCredentials is returned by an Authenticator when the request carries no
+// credentials it understands, so the next authenticator in the chain is tried
+var ErrNoCredentials = errors.New("no credentials")
+
+// Principal is the authenticated caller of an internal API request
+type Principal struct {
+	Subject string
+	Method  string // api_key, jwt
+	Scopes  []string
+}
+
+// HasScope reports whether the principal holds the given scope
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// CanPublish reports whether the principal may publish or manage policies of a provider
+func (p *Principal) CanPublish(provider string) bool {
+	if p.HasScope(ScopeAdmin) || p.HasScope(ScopePublishPrefix+"*") {
+		return true
+	}
+	return provider != "" && p.HasScope(ScopePublishPrefix+provider)
+}
+
+// Authenticator verifies one kind of credential on a request
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// AuthMiddleware authenticates internal API requests against a chain of authenticators
+type AuthMiddleware struct {
+	enabled        bool
+	authenticators []Authenticator
+	logger         *logging.Logger
+}
+
+// NewAuthMiddleware creates the auth middleware from configuration, loading
+// the API key and JWKS files that are configured
+func NewAuthMiddleware(cfg *config.AuthConfig, logger *logging.Logger) (*AuthMiddleware, error) {
+	m := &AuthMiddleware{
+		enabled: cfg.Enabled,
+		logger:  logger,
+	}
+
+	if !cfg.Enabled {
+		logger.Warn("Authentication is disabled for the internal API")
+		return m, nil
+	}
+	// Checked here rather than in config.Validate so commands that do not
+	// serve the API, such as migrate, run without credentials configured
+	if cfg.APIKeysFile == "" && cfg.JWKSFile == "" {
+		return nil, errors.New("auth is enabled but neither AUTH_API_KEYS_FILE nor AUTH_JWKS_FILE is set")
+	}
+
+	if cfg.APIKeysFile != "" {
+		apiKeys, err := NewAPIKeyAuthenticator(cfg.APIKeysFile)
+		if err != nil {
+			return nil, err
+		}
+		m.authenticators = append(m.authenticators, apiKeys)
+	}
+
+	if cfg.JWKSFile != "" {
+		jwtAuth, err := NewJWTAuthenticator(cfg.JWKSFile, cfg.JWTIssuer, cfg.JWTAudience)
+		if err != nil {
+			return nil, err
+		}
+		m.authenticators = append(m.authenticators, jwtAuth)
+	}
+
+	return m, nil
+}
+
+// Authenticate rejects requests that do not carry valid credentials and
+// stores the resolved principal in the request context
+func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !m.enabled {
+			c.Set(principalContextKey, &Principal{
+				Subject: "anonymous",
+				Method:  "none",
+				Scopes:  []string{ScopeAdmin},
+			})
+			c.Next()
+			return
+		}
+
+		for _, authenticator := range m.authenticators {
+			principal, err := authenticator.Authenticate(c.Request)
+			if errors.Is(err, ErrNoCredentials) {
+				continue
+			}
+			if err != nil {
+				m.logger.Warn("Authentication failed",
+					zap.String("path", c.Request.URL.Path),
+					zap.Error(err),
+				)
+				m.unauthorized(c, "Invalid credentials")
+				return
+			}
+
+			c.Set(principalContextKey, principal)
+			c.Next()
+			return
+		}
+
+		m.unauthorized(c, "Missing credentials")
+	}
+}
+
+// unauthorized aborts the request with a 401 response
+func (m *AuthMiddleware) unauthorized(c *gin.Context, msg string) {
+	c.Header("WWW-Authenticate", `Bearer realm="policyhub"`)
+	_ = c.Error(errs.NewUnauthorizedError(msg, nil))
+	c.Abort()
+}
+
+// GetPrincipal returns the authenticated principal, or nil for unauthenticated routes
+func GetPrincipal(c *gin.Context) *Principal {
+	if principal, exists := c.Get(principalContextKey); exists {
+		return principal.(*Principal)
+	}
+	return nil
+}
+
+// AuthorizeProvider checks that the caller may publish or manage policies of a provider
+func AuthorizeProvider(c *gin.Context, provider string) error {
+	principal := GetPrincipal(c)
+	if principal == nil || !principal.CanPublish(provider) {
+		return errs.NewForbiddenError("Not authorized for provider", map[string]any{
+			"provider":      provider,
+			"requiredScope": ScopePublishPrefix + provider,
+		})
+	}
+	return nil
+}
+
+// AuthorizeAdmin checks that the caller holds the admin scope
+func AuthorizeAdmin(c *gin.Context) error {
+	principal := GetPrincipal(c)
+	if principal == nil || !principal.HasScope(ScopeAdmin) {
+		return errs.NewForbiddenError("Not authorized", map[string]any{
+			"requiredScope": ScopeAdmin,
+		})
+	}
+	return nil
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer <token>" header
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
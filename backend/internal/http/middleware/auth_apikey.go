@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const (
+	apiKeyHeader     = "X-API-Key"
+	apiKeyHashPrefix = "sha256:"
+)
+
+// apiKeyEntry is a single entry of the API keys file
+type apiKeyEntry struct {
+	ID     string   `json:"id"`
+	Hash   string   `json:"hash"` // sha256:<hex digest of the raw key>
+	Scopes []string `json:"scopes"`
+}
+
+// storedAPIKey is a loaded API key with its decoded digest
+type storedAPIKey struct {
+	id     string
+	digest []byte
+	scopes []string
+}
+
+// APIKeyAuthenticator authenticates requests using static API keys sent in the
+// X-API-Key header. Only SHA-256 digests of the keys are kept in configuration.
+type APIKeyAuthenticator struct {
+	keys []storedAPIKey
+}
+
+// NewAPIKeyAuthenticator loads hashed API keys from a JSON file of the form
+// [{"id": "...", "hash": "sha256:<hex>", "scopes": ["policies:publish:WSO2"]}]
+func NewAPIKeyAuthenticator(path string) (*APIKeyAuthenticator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API keys file: %w", err)
+	}
+
+	var entries []apiKeyEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse API keys file: %w", err)
+	}
+
+	authenticator := &APIKeyAuthenticator{}
+	seen := make(map[string]bool)
+	for i, entry := range entries {
+		if entry.ID == "" {
+			return nil, fmt.Errorf("API key entry %d has no id", i)
+		}
+		if seen[entry.ID] {
+			return nil, fmt.Errorf("duplicate API key id %q", entry.ID)
+		}
+		seen[entry.ID] = true
+
+		if !strings.HasPrefix(entry.Hash, apiKeyHashPrefix) {
+			return nil, fmt.Errorf("API key %q: hash must start with %q", entry.ID, apiKeyHashPrefix)
+		}
+		digest, err := hex.DecodeString(strings.TrimPrefix(entry.Hash, apiKeyHashPrefix))
+		if err != nil || len(digest) != sha256.Size {
+			return nil, fmt.Errorf("API key %q: invalid SHA-256 digest", entry.ID)
+		}
+
+		authenticator.keys = append(authenticator.keys, storedAPIKey{
+			id:     entry.ID,
+			digest: digest,
+			scopes: entry.Scopes,
+		})
+	}
+
+	return authenticator, nil
+}
+
+// Authenticate implements Authenticator
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	key := r.Header.Get(apiKeyHeader)
+	if key == "" {
+		return nil, ErrNoCredentials
+	}
+
+	digest := sha256.Sum256([]byte(key))
+	for _, stored := range a.keys {
+		if subtle.ConstantTimeCompare(digest[:], stored.digest) == 1 {
+			return &Principal{
+				Subject: stored.id,
+				Method:  "api_key",
+				Scopes:  stored.scopes,
+			}, nil
+		}
+	}
+
+	return nil, errors.New("unknown API key")
+}
@@ -0,0 +1,367 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package middleware
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+
+	"github.com/wso2/policyhub/internal/config"
+	"github.com/wso2/policyhub/internal/logging"
+)
+
+const (
+	testIssuer   = "https://idp.example.com"
+	testAudience = "policyhub"
+)
+
+func writeTestFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func apiKeyHash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return apiKeyHashPrefix + hex.EncodeToString(sum[:])
+}
+
+func apiKeysFile(t *testing.T) string {
+	t.Helper()
+	return writeTestFile(t, "api-keys.json", fmt.Sprintf(`[
+		{"id": "ci-wso2", "hash": %q, "scopes": ["policies:publish:WSO2"]},
+		{"id": "ops", "hash": %q, "scopes": ["policies:admin"]}
+	]`, apiKeyHash("wso2-secret"), apiKeyHash("ops-secret")))
+}
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	authenticator, err := NewAPIKeyAuthenticator(apiKeysFile(t))
+	if err != nil {
+		t.Fatalf("NewAPIKeyAuthenticator() error = %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set(apiKeyHeader, "wso2-secret")
+	principal, err := authenticator.Authenticate(req)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if principal.Subject != "ci-wso2" || principal.Method != "api_key" || !principal.HasScope("policies:publish:WSO2") {
+		t.Errorf("Authenticate() = %+v, want the ci-wso2 key", principal)
+	}
+
+	req.Header.Set(apiKeyHeader, "wso2-secret ")
+	if principal, err := authenticator.Authenticate(req); err == nil || errors.Is(err, ErrNoCredentials) {
+		t.Errorf("Authenticate() with an unknown key = %+v, %v; want an error", principal, err)
+	}
+
+	req.Header.Del(apiKeyHeader)
+	if _, err := authenticator.Authenticate(req); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("Authenticate() without a key error = %v, want ErrNoCredentials", err)
+	}
+}
+
+func TestNewAPIKeyAuthenticatorInvalid(t *testing.T) {
+	valid := apiKeyHash("secret")
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "not JSON", content: `{"id": "a"}`, want: "failed to parse"},
+		{name: "missing id", content: fmt.Sprintf(`[{"hash": %q}]`, valid), want: "has no id"},
+		{name: "duplicate id", content: fmt.Sprintf(`[{"id": "a", "hash": %q}, {"id": "a", "hash": %q}]`, valid, valid), want: "duplicate API key id"},
+		{name: "plaintext key", content: `[{"id": "a", "hash": "secret"}]`, want: "must start with"},
+		{name: "not hex", content: `[{"id": "a", "hash": "sha256:` + strings.Repeat("zz", 32) + `"}]`, want: "invalid SHA-256 digest"},
+		{name: "short digest", content: fmt.Sprintf(`[{"id": "a", "hash": %q}]`, valid[:len(valid)-2]), want: "invalid SHA-256 digest"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAPIKeyAuthenticator(writeTestFile(t, "api-keys.json", tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("NewAPIKeyAuthenticator() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewAPIKeyAuthenticator(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("NewAPIKeyAuthenticator() accepted a missing file")
+	}
+}
+
+// jwksFile writes a JWKS holding the Ed25519 public keys under their kids
+func jwksFile(t *testing.T, keys map[string]ed25519.PublicKey) string {
+	t.Helper()
+	entries := make([]string, 0, len(keys))
+	for kid, key := range keys {
+		entries = append(entries, fmt.Sprintf(`{"kty": "OKP", "crv": "Ed25519", "use": "sig", "kid": %q, "x": %q}`,
+			kid, base64.RawURLEncoding.EncodeToString(key)))
+	}
+	return writeTestFile(t, "jwks.json", `{"keys": [`+strings.Join(entries, ",")+`]}`)
+}
+
+func newKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return public, private
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, key any, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func bearerRequest(token string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	public1, private1 := newKey(t)
+	public2, private2 := newKey(t)
+	authenticator, err := NewJWTAuthenticator(jwksFile(t, map[string]ed25519.PublicKey{"k1": public1, "k2": public2}), testIssuer, testAudience)
+	if err != nil {
+		t.Fatalf("NewJWTAuthenticator() error = %v", err)
+	}
+
+	claims := func(change func(jwt.MapClaims)) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"sub":   "ci",
+			"iss":   testIssuer,
+			"aud":   testAudience,
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"scope": "policies:publish:WSO2 openid",
+		}
+		if change != nil {
+			change(c)
+		}
+		return c
+	}
+	eddsa := jwt.SigningMethodEdDSA
+
+	valid := map[string]string{
+		"first key":  signToken(t, eddsa, private1, "k1", claims(nil)),
+		"second key": signToken(t, eddsa, private2, "k2", claims(nil)),
+	}
+	for name, token := range valid {
+		principal, err := authenticator.Authenticate(bearerRequest(token))
+		if err != nil {
+			t.Errorf("%s: Authenticate() error = %v", name, err)
+			continue
+		}
+		if principal.Subject != "ci" || principal.Method != "jwt" || !principal.HasScope("policies:publish:WSO2") {
+			t.Errorf("%s: Authenticate() = %+v", name, principal)
+		}
+	}
+
+	rejected := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"key of another kid", signToken(t, eddsa, private2, "k1", claims(nil)), jwt.ErrTokenSignatureInvalid},
+		{"unknown kid", signToken(t, eddsa, private1, "k3", claims(nil)), jwt.ErrTokenUnverifiable},
+		{"no kid", signToken(t, eddsa, private1, "", claims(nil)), jwt.ErrTokenUnverifiable},
+		{"HMAC algorithm", signToken(t, jwt.SigningMethodHS256, []byte(public1), "k1", claims(nil)), jwt.ErrTokenSignatureInvalid},
+		{"none algorithm", signToken(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "k1", claims(nil)), jwt.ErrTokenSignatureInvalid},
+		{"missing exp", signToken(t, eddsa, private1, "k1", claims(func(c jwt.MapClaims) { delete(c, "exp") })), jwt.ErrTokenRequiredClaimMissing},
+		{"expired", signToken(t, eddsa, private1, "k1", claims(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() })), jwt.ErrTokenExpired},
+		{"wrong issuer", signToken(t, eddsa, private1, "k1", claims(func(c jwt.MapClaims) { c["iss"] = "https://other.example.com" })), jwt.ErrTokenInvalidIssuer},
+		{"missing issuer", signToken(t, eddsa, private1, "k1", claims(func(c jwt.MapClaims) { delete(c, "iss") })), jwt.ErrTokenRequiredClaimMissing},
+		{"wrong audience", signToken(t, eddsa, private1, "k1", claims(func(c jwt.MapClaims) { c["aud"] = []string{"other"} })), jwt.ErrTokenInvalidAudience},
+		{"malformed", "not.a.token", jwt.ErrTokenMalformed},
+	}
+	for _, tt := range rejected {
+		principal, err := authenticator.Authenticate(bearerRequest(tt.token))
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: Authenticate() = %+v, %v; want %v", tt.name, principal, err, tt.want)
+		}
+	}
+
+	if _, err := authenticator.Authenticate(httptest.NewRequest(http.MethodPost, "/", nil)); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("Authenticate() without a token error = %v, want ErrNoCredentials", err)
+	}
+	basic := httptest.NewRequest(http.MethodPost, "/", nil)
+	basic.SetBasicAuth("user", "password")
+	if _, err := authenticator.Authenticate(basic); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("Authenticate() with basic auth error = %v, want ErrNoCredentials", err)
+	}
+}
+
+func TestJWTAuthenticatorSingleKey(t *testing.T) {
+	public, private := newKey(t)
+	authenticator, err := NewJWTAuthenticator(jwksFile(t, map[string]ed25519.PublicKey{"only": public}), "", "")
+	if err != nil {
+		t.Fatalf("NewJWTAuthenticator() error = %v", err)
+	}
+
+	// Without a kid the only key is used; issuer and audience are not checked when unset
+	token := signToken(t, jwt.SigningMethodEdDSA, private, "", jwt.MapClaims{
+		"sub": "ci",
+		"exp": time.Now().Add(time.Hour).Unix(),
+		"scp": []string{"policies:admin", "policies:publish:WSO2"},
+	})
+	principal, err := authenticator.Authenticate(bearerRequest(token))
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if !principal.HasScope(ScopeAdmin) || !principal.HasScope("policies:publish:WSO2") {
+		t.Errorf("scopes = %v, want the scp claim", principal.Scopes)
+	}
+}
+
+func TestPrincipalCanPublish(t *testing.T) {
+	tests := []struct {
+		name     string
+		scopes   []string
+		provider string
+		want     bool
+	}{
+		{name: "admin", scopes: []string{ScopeAdmin}, provider: "WSO2", want: true},
+		{name: "admin without provider", scopes: []string{ScopeAdmin}, provider: "", want: true},
+		{name: "all providers", scopes: []string{"policies:publish:*"}, provider: "Acme", want: true},
+		{name: "exact provider", scopes: []string{"policies:publish:WSO2"}, provider: "WSO2", want: true},
+		{name: "other provider", scopes: []string{"policies:publish:WSO2"}, provider: "Acme", want: false},
+		{name: "provider case differs", scopes: []string{"policies:publish:WSO2"}, provider: "wso2", want: false},
+		{name: "provider prefix", scopes: []string{"policies:publish:WSO"}, provider: "WSO2", want: false},
+		{name: "empty provider", scopes: []string{"policies:publish:WSO2"}, provider: "", want: false},
+		{name: "empty provider scope", scopes: []string{"policies:publish:"}, provider: "", want: false},
+		{name: "no scopes", provider: "WSO2", want: false},
+	}
+	for _, tt := range tests {
+		principal := &Principal{Scopes: tt.scopes}
+		if got := principal.CanPublish(tt.provider); got != tt.want {
+			t.Errorf("%s: CanPublish(%q) = %v, want %v", tt.name, tt.provider, got, tt.want)
+		}
+	}
+}
+
+// serveAuthenticated runs a request through the auth middleware and returns
+// the response and the principal the handler saw
+func serveAuthenticated(t *testing.T, m *AuthMiddleware, req *http.Request) (*httptest.ResponseRecorder, *Principal) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorHandler())
+
+	var principal *Principal
+	router.POST("/internal", m.Authenticate(), func(c *gin.Context) {
+		principal = GetPrincipal(c)
+		c.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w, principal
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	logger := &logging.Logger{Logger: zap.NewNop()}
+	m, err := NewAuthMiddleware(&config.AuthConfig{Enabled: true, APIKeysFile: apiKeysFile(t)}, logger)
+	if err != nil {
+		t.Fatalf("NewAuthMiddleware() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		apiKey  string
+		bearer  string
+		status  int
+		subject string
+	}{
+		{name: "no credentials", status: http.StatusUnauthorized},
+		{name: "unknown key", apiKey: "guess", status: http.StatusUnauthorized},
+		{name: "valid key", apiKey: "ops-secret", status: http.StatusNoContent, subject: "ops"},
+		// No JWKS is configured, so bearer tokens are not credentials this chain understands
+		{name: "unsupported credentials", bearer: "token", status: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/internal", nil)
+			if tt.apiKey != "" {
+				req.Header.Set(apiKeyHeader, tt.apiKey)
+			}
+			if tt.bearer != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.bearer)
+			}
+
+			w, principal := serveAuthenticated(t, m, req)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.status, w.Body)
+			}
+			if tt.status == http.StatusUnauthorized {
+				if principal != nil {
+					t.Error("the handler ran for an unauthenticated request")
+				}
+				if w.Header().Get("WWW-Authenticate") == "" {
+					t.Error("401 response has no WWW-Authenticate header")
+				}
+				return
+			}
+			if principal == nil || principal.Subject != tt.subject {
+				t.Errorf("principal = %+v, want subject %s", principal, tt.subject)
+			}
+		})
+	}
+}
+
+func TestAuthMiddlewareDisabled(t *testing.T) {
+	logger := &logging.Logger{Logger: zap.NewNop()}
+	m, err := NewAuthMiddleware(&config.AuthConfig{Enabled: false}, logger)
+	if err != nil {
+		t.Fatalf("NewAuthMiddleware() error = %v", err)
+	}
+
+	w, principal := serveAuthenticated(t, m, httptest.NewRequest(http.MethodPost, "/internal", nil))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if principal == nil || principal.Subject != "anonymous" || !principal.HasScope(ScopeAdmin) || !principal.CanPublish("WSO2") {
+		t.Errorf("principal = %+v, want the anonymous admin", principal)
+	}
+}
+
+func TestNewAuthMiddlewareRequiresCredentials(t *testing.T) {
+	logger := &logging.Logger{Logger: zap.NewNop()}
+	if _, err := NewAuthMiddleware(&config.AuthConfig{Enabled: true}, logger); err == nil {
+		t.Error("NewAuthMiddleware() accepted auth without an API keys or JWKS file")
+	}
+}
@@ -10,6 +10,8 @@
 package middleware
 
 import (
+	"errors"
+	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -33,6 +35,12 @@ func ErrorHandler() gin.HandlerFunc {
 		traceID := GetTraceID(c)
 		requestID := GetRequestID(c)
 
+		// Bodies cut off by BodyLimit fail wherever they are read
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			err = errs.RequestTooLarge(maxBytesErr.Limit)
+		}
+
 		// Check if it's an AppError
 		appErr, ok := err.(*errs.AppError)
 		if !ok {
@@ -92,6 +100,21 @@ func SendSuccess(c *gin.Context, data interface{}) {
 	c.JSON(200, response)
 }
 
+// SendAccepted sends a 202 response for work that continues in the background
+func SendAccepted(c *gin.Context, data interface{}) {
+	response := dto.BaseResponse{
+		Success: true,
+		Data:    data,
+		Error:   nil,
+		Meta: dto.MetaDTO{
+			TraceID:   GetTraceID(c),
+			Timestamp: time.Now().UTC(),
+			RequestID: GetRequestID(c),
+		},
+	}
+	c.JSON(202, response)
+}
+
 // SendSuccessWithPagination sends a successful response with pagination
 func SendSuccessWithPagination(c *gin.Context, data interface{}, pagination dto.PaginationDTO) {
 	response := dto.PaginatedResponse{
@@ -107,3 +130,36 @@ func SendSuccessWithPagination(c *gin.Context, data interface{}, pagination dto.
 	}
 	c.JSON(200, response)
 }
+
+// SendSuccessWithFacets sends a successful response with pagination and facet counts
+func SendSuccessWithFacets(c *gin.Context, data interface{}, pagination dto.PaginationDTO, facets dto.FacetsDTO) {
+	response := dto.PaginatedResponse{
+		Success: true,
+		Data:    data,
+		Error:   nil,
+		Meta: dto.PaginatedMetaDTO{
+			TraceID:    GetTraceID(c),
+			Timestamp:  time.Now().UTC(),
+			RequestID:  GetRequestID(c),
+			Pagination: pagination,
+			Facets:     facets,
+		},
+	}
+	c.JSON(200, response)
+}
+
+// SendSuccessWithErrors sends a successful response that also reports per-item errors
+func SendSuccessWithErrors(c *gin.Context, data interface{}, itemErrors []dto.PolicyErrorDTO) {
+	response := dto.ResolveResponse{
+		Success: true,
+		Data:    data,
+		Errors:  itemErrors,
+		Error:   nil,
+		Meta: dto.MetaDTO{
+			TraceID:   GetTraceID(c),
+			Timestamp: time.Now().UTC(),
+			RequestID: GetRequestID(c),
+		},
+	}
+	c.JSON(200, response)
+}
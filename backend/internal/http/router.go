@@ -17,6 +17,7 @@ import (
 	"github.com/wso2/policyhub/internal/http/middleware"
 	"github.com/wso2/policyhub/internal/logging"
 	"github.com/wso2/policyhub/internal/policy"
+	"github.com/wso2/policyhub/internal/storage"
 	"github.com/wso2/policyhub/internal/sync"
 )
 
@@ -25,6 +26,8 @@ func SetupRouter(
 	cfg *config.Config,
 	policyService *policy.Service,
 	syncService *sync.Service,
+	store storage.Store,
+	authMW *middleware.AuthMiddleware,
 	logger *logging.Logger,
 ) *gin.Engine {
 	// Set Gin mode
@@ -41,10 +44,18 @@ func SetupRouter(
 	// Validation middleware
 	validationMW := middleware.NewValidationMiddleware(logger)
 
+	// Request body limits; bundle uploads carry archives rather than JSON
+	jsonBody := middleware.BodyLimit(cfg.Server.MaxBodySize)
+	bundleBody := middleware.BodyLimit(cfg.Sync.Bundle.MaxSize + handlers.BundleRequestOverhead)
+
 	// Handlers
 	healthHandler := handlers.NewHealthHandler()
 	policyHandler := handlers.NewPolicyHandler(policyService, logger)
 	syncHandler := handlers.NewSyncHandler(syncService, logger)
+	bundleHandler := handlers.NewBundleHandler(syncService, logger)
+	blobHandler := handlers.NewBlobHandler(store, logger)
+	lifecycleHandler := handlers.NewLifecycleHandler(policyService, logger)
+	schemaHandler := handlers.NewSchemaHandler()
 
 	// API Version group
 	apiV1 := router.Group("/api/v1")
@@ -52,7 +63,9 @@ func SetupRouter(
 	// Public routes under /api/v1
 	// Policy routes
 	apiV1.GET("/policies", validationMW.ValidatePagination(), policyHandler.ListPolicies)
-	apiV1.POST("/policies/resolve", policyHandler.ResolvePolicies)
+	apiV1.POST("/policies/resolve", jsonBody, policyHandler.ResolvePolicies)
+	apiV1.POST("/policies/lock", jsonBody, policyHandler.LockPolicies)
+	apiV1.POST("/policies/lock/verify", jsonBody, policyHandler.VerifyLockfile)
 
 	// Metadata routes (must come before parameterized routes)
 	apiV1.GET("/policies/categories", policyHandler.GetCategories)
@@ -69,10 +82,32 @@ func SetupRouter(
 	apiV1.GET("/policies/:name/versions/:version/docs", validationMW.ValidatePolicyName(), validationMW.ValidateVersion(), policyHandler.GetAllDocs)
 	apiV1.GET("/policies/:name/versions/:version/docs/:page", validationMW.ValidatePolicyName(), validationMW.ValidateVersion(), validationMW.ValidateDocType(), policyHandler.GetSingleDoc)
 
+	// Files of versions published from uploaded bundles; sync.Service builds their URLs
+	apiV1.GET("/policies/:name/versions/:version/bundle", validationMW.ValidatePolicyName(), validationMW.ValidateVersion(), bundleHandler.DownloadBundle)
+	apiV1.GET("/policies/:name/versions/:version/assets/*path", validationMW.ValidatePolicyName(), validationMW.ValidateVersion(), bundleHandler.GetBundleAsset)
+
+	// Artifacts and images mirrored by syncs, by content digest
+	apiV1.GET("/blobs/:digest", blobHandler.GetBlob)
+
+	// Policy definition schema, for authors to validate definitions locally
+	apiV1.GET("/schemas/policy-definition", schemaHandler.GetDefinitionSchema)
+	apiV1.GET("/schemas/policy-definition/:version", schemaHandler.GetDefinitionSchema)
+
 	// Internal routes under /api/v1/internal
 	internal := apiV1.Group("/internal")
 	internal.GET("/health", healthHandler.HealthCheck)
-	internal.POST("/policies/:name/versions/:version", validationMW.ValidatePolicyName(), validationMW.ValidateVersion(), syncHandler.CreatePolicyVersion)
+
+	// Authenticated internal routes; provider scopes are checked by the handlers
+	internal.Use(authMW.Authenticate())
+	internal.POST("/policies/:name/versions/:version", jsonBody, validationMW.ValidatePolicyName(), validationMW.ValidateVersion(), syncHandler.CreatePolicyVersion)
+	internal.POST("/policies/:name/versions/:version/bundle", bundleBody, validationMW.ValidatePolicyName(), validationMW.ValidateVersion(), bundleHandler.UploadBundle)
+	internal.GET("/sync-jobs/:id", syncHandler.GetSyncJob)
+	internal.POST("/sync-source/scan", jsonBody, syncHandler.ScanSource)
+
+	// Version lifecycle routes
+	internal.POST("/policies/:name/versions/:version/deprecate", jsonBody, validationMW.ValidatePolicyName(), validationMW.ValidateVersion(), lifecycleHandler.DeprecateVersion)
+	internal.POST("/policies/:name/versions/:version/yank", jsonBody, validationMW.ValidatePolicyName(), validationMW.ValidateVersion(), lifecycleHandler.YankVersion)
+	internal.POST("/policies/:name/versions/:version/unyank", jsonBody, validationMW.ValidatePolicyName(), validationMW.ValidateVersion(), lifecycleHandler.UnyankVersion)
 
 	return router
 }
@@ -21,6 +21,113 @@ const (
 	DocTypeFAQ           DocType = "faq"
 )
 
+// VersionStatus represents the lifecycle status of a published policy version
+type VersionStatus string
+
+const (
+	VersionStatusActive     VersionStatus = "active"
+	VersionStatusDeprecated VersionStatus = "deprecated"
+	VersionStatusYanked     VersionStatus = "yanked"
+)
+
+// SignatureStatus records whether a version's release signature was verified at sync time
+type SignatureStatus string
+
+const (
+	SignatureStatusUnsigned SignatureStatus = "unsigned"
+	SignatureStatusVerified SignatureStatus = "verified"
+)
+
+// ResolveErrorCode is a machine-readable reason why a resolve request item failed
+type ResolveErrorCode string
+
+const (
+	ResolveErrorPolicyNotFound     ResolveErrorCode = "POLICY_NOT_FOUND"
+	ResolveErrorNoMatchingVersion  ResolveErrorCode = "NO_MATCHING_VERSION"
+	ResolveErrorInvalidBaseVersion ResolveErrorCode = "INVALID_BASE_VERSION"
+	ResolveErrorInvalidStrategy    ResolveErrorCode = "INVALID_STRATEGY"
+	ResolveErrorInvalidConstraint  ResolveErrorCode = "INVALID_CONSTRAINT"
+	ResolveErrorInternal           ResolveErrorCode = "INTERNAL_ERROR"
+)
+
+// Facet is a policy attribute the policy listing can count the values of
+type Facet string
+
+const (
+	FacetCategory Facet = "category"
+	FacetProvider Facet = "provider"
+	FacetPlatform Facet = "platform"
+	FacetTag      Facet = "tag"
+)
+
+// Facets lists every facet, in the order they are reported
+var Facets = []Facet{FacetCategory, FacetProvider, FacetPlatform, FacetTag}
+
+// PolicySort is a key the policy listing can be sorted by
+type PolicySort string
+
+const (
+	SortName        PolicySort = "name"
+	SortDisplayName PolicySort = "displayName"
+	SortProvider    PolicySort = "provider"
+	SortReleaseDate PolicySort = "releaseDate"
+	SortUpdated     PolicySort = "updated"
+	SortPopularity  PolicySort = "popularity" // downloads and resolves
+	SortRelevance   PolicySort = "relevance"  // search rank; listings without a search sort by SortCreated
+	SortCreated     PolicySort = "created"
+)
+
+// PolicySorts lists every sort key
+var PolicySorts = []PolicySort{SortName, SortDisplayName, SortProvider, SortReleaseDate, SortUpdated, SortPopularity, SortRelevance, SortCreated}
+
+// DefaultOrder is the order a key sorts in when none is given: names
+// alphabetically, everything else highest or newest first
+func (s PolicySort) DefaultOrder() SortOrder {
+	switch s {
+	case SortName, SortDisplayName, SortProvider:
+		return SortAscending
+	default:
+		return SortDescending
+	}
+}
+
+// SortOrder is the direction of a sort
+type SortOrder string
+
+const (
+	SortAscending  SortOrder = "asc"
+	SortDescending SortOrder = "desc"
+)
+
+// FilterMatch is how a multi-valued filter matches a policy's values
+type FilterMatch string
+
+const (
+	MatchAny FilterMatch = "any" // any of the filter's values
+	MatchAll FilterMatch = "all" // every one of the filter's values
+)
+
+// LockStatus is the outcome of verifying a lockfile entry
+type LockStatus string
+
+const (
+	LockStatusOK              LockStatus = "OK"
+	LockStatusDeprecated      LockStatus = "DEPRECATED" // in sync, but the version is deprecated
+	LockStatusYanked          LockStatus = "YANKED"
+	LockStatusBuildMismatch   LockStatus = "BUILD_MISMATCH" // the version is published with other build metadata
+	LockStatusDigestMismatch  LockStatus = "DIGEST_MISMATCH"
+	LockStatusVersionNotFound LockStatus = "VERSION_NOT_FOUND"
+)
+
+// Lockfile constants
+const (
+	LockfileVersion = 1         // format version of generated lockfiles
+	DigestPrefix    = "sha256:" // algorithm prefix of content digests
+)
+
+// DefinitionCacheSize bounds the definitions kept converted to JSON, in bytes
+const DefinitionCacheSize = 32 << 20
+
 // Pagination constants
 const (
 	DefaultPageSize = 20
@@ -28,6 +135,9 @@ const (
 	MinPageSize     = 1
 )
 
+// MaxFacetValues bounds the values reported per facet; the most common are kept
+const MaxFacetValues = 50
+
 // Batch processing constants
 const (
 	MaxBatchSize = 100 // Maximum batch size limit
@@ -35,9 +145,10 @@ const (
 
 // Validation constants
 const (
-	MaxPolicyNameLength  = 100
-	MaxVersionLength     = 50
-	MaxDescriptionLength = 1000
+	MaxPolicyNameLength   = 100
+	MaxVersionLength      = 50
+	MaxDescriptionLength  = 1000
+	MaxStatusReasonLength = 500
 )
 
 // HTTP timeouts
@@ -48,7 +159,11 @@ const (
 // Regular expressions for validation
 const (
 	PolicyNameRegex = `^[a-zA-Z0-9_-]+$`
-	VersionRegex    = `^\d+\.\d+\.\d+$`
+	// VersionRegex is the SemVer 2.0 grammar: major.minor.patch, optional
+	// "-prerelease" identifiers and optional "+build" metadata
+	VersionRegex = `^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+		`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+		`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`
 )
 
 // ValidDocTypes returns a map of valid documentation types
@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefinitionFormat is a representation a policy definition is served in
+type DefinitionFormat string
+
+const (
+	DefinitionFormatYAML DefinitionFormat = "yaml" // the definition as published
+	DefinitionFormatJSON DefinitionFormat = "json" // the definition converted to JSON
+)
+
+// definitionCache holds definitions converted to JSON by definition digest.
+// Published definitions never change, so entries never go stale; when the
+// cache is full, arbitrary entries are dropped to make room.
+type definitionCache struct {
+	mu       sync.RWMutex
+	entries  map[string]json.RawMessage
+	size     int
+	maxBytes int
+}
+
+func newDefinitionCache(maxBytes int) *definitionCache {
+	return &definitionCache{
+		entries:  make(map[string]json.RawMessage),
+		maxBytes: maxBytes,
+	}
+}
+
+func (c *definitionCache) get(digest string) (json.RawMessage, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	definition, ok := c.entries[digest]
+	return definition, ok
+}
+
+func (c *definitionCache) put(digest string, definition json.RawMessage) {
+	if len(definition) > c.maxBytes {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[digest]; ok {
+		return
+	}
+	for key, entry := range c.entries {
+		if c.size+len(definition) <= c.maxBytes {
+			break
+		}
+		delete(c.entries, key)
+		c.size -= len(entry)
+	}
+	c.entries[digest] = definition
+	c.size += len(definition)
+}
+
+// DefinitionJSON returns the definition of a version converted to JSON. The
+// conversion is cached by definition digest.
+func (s *Service) DefinitionJSON(version *PolicyVersion) (json.RawMessage, error) {
+	if definition, ok := s.definitions.get(version.DefinitionDigest); ok {
+		return definition, nil
+	}
+
+	definition, err := definitionToJSON([]byte(version.DefinitionYAML))
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert definition of policy %s version %s to JSON: %w", version.PolicyName, version.Version, err)
+	}
+	s.definitions.put(version.DefinitionDigest, definition)
+	return definition, nil
+}
+
+// definitionToJSON converts a YAML definition to JSON
+func definitionToJSON(definition []byte) (json.RawMessage, error) {
+	var doc any
+	if err := yaml.Unmarshal(definition, &doc); err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonCompatible(doc))
+}
+
+// jsonCompatible converts the maps yaml.v3 decodes with non-string keys into
+// the string-keyed maps JSON encoding requires
+func jsonCompatible(value any) any {
+	switch val := value.(type) {
+	case map[string]any:
+		for key, item := range val {
+			val[key] = jsonCompatible(item)
+		}
+		return val
+	case map[any]any:
+		object := make(map[string]any, len(val))
+		for key, item := range val {
+			object[fmt.Sprint(key)] = jsonCompatible(item)
+		}
+		return object
+	case []any:
+		for i, item := range val {
+			val[i] = jsonCompatible(item)
+		}
+		return val
+	}
+	return value
+}
@@ -10,9 +10,14 @@
 package policy
 
 import (
+	"crypto/sha256"
 	"database/sql/driver"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"time"
+
+	"github.com/wso2/policyhub/internal/semver"
 )
 
 type PolicyVersion struct {
@@ -39,6 +44,81 @@ type PolicyVersion struct {
 	SourceURL      *string
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
+
+	// Content digests ("sha256:<hex>"); the artifact digest is unknown for versions synced before digests were recorded
+	DefinitionDigest string
+	ArtifactDigest   *string
+
+	// Lifecycle status (deprecated versions stay resolvable, yanked ones only by exact version)
+	Status             VersionStatus
+	StatusReason       *string
+	ReplacementVersion *string
+	StatusUpdatedAt    *time.Time
+
+	// Status and reason of a yanked version before it was yanked, restored by un-yanking
+	PreviousStatus       *VersionStatus
+	PreviousStatusReason *string
+
+	// Release signature, set only when it was verified against a provider key at sync time
+	SignatureStatus     SignatureStatus
+	SignatureKeyID      *string
+	Signature           *string
+	SignatureVerifiedAt *time.Time
+
+	// BundleID is the uploaded bundle the version was published from, if any
+	BundleID *int64
+
+	// SearchSnippet is set on policies listed by a search: HTML-escaped text
+	// around the matches, which are wrapped in <mark> tags
+	SearchSnippet string
+
+	// sortKey is the value a listed policy is sorted by, which cursors to its
+	// neighbours are keyed on
+	sortKey string
+}
+
+// StatusWarning returns a consumer-facing warning for deprecated or yanked versions
+func (v *PolicyVersion) StatusWarning() string {
+	var warning string
+	switch v.Status {
+	case VersionStatusDeprecated:
+		warning = fmt.Sprintf("Policy %s version %s is deprecated", v.PolicyName, v.Version)
+	case VersionStatusYanked:
+		warning = fmt.Sprintf("Policy %s version %s has been yanked", v.PolicyName, v.Version)
+	default:
+		return ""
+	}
+
+	if v.StatusReason != nil && *v.StatusReason != "" {
+		warning += ": " + *v.StatusReason
+	}
+	if v.ReplacementVersion != nil && *v.ReplacementVersion != "" {
+		warning += fmt.Sprintf(" (use version %s instead)", *v.ReplacementVersion)
+	}
+
+	return warning
+}
+
+// ComputeDigest returns the SHA-256 digest of data as "sha256:<hex>"
+func ComputeDigest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return FormatDigest(sum[:])
+}
+
+// FormatDigest formats a raw SHA-256 sum as "sha256:<hex>"
+func FormatDigest(sum []byte) string {
+	return DigestPrefix + hex.EncodeToString(sum)
+}
+
+// VersionStatusUpdate describes a lifecycle status change for a policy version
+type VersionStatusUpdate struct {
+	Status             VersionStatus
+	Reason             *string
+	ReplacementVersion *string
+
+	// PreviousStatus and PreviousReason are set when yanking, to be restored by un-yanking
+	PreviousStatus *VersionStatus
+	PreviousReason *string
 }
 
 // PolicyDoc represents a documentation page
@@ -83,17 +163,62 @@ type PolicyFilters struct {
 	Search     string
 	Categories []string
 	Providers  []string
-	Platforms  []string
-	Page       int
-	PageSize   int
+	// Platforms are matched by compatibility: "apim-4.4" matches policies
+	// declaring "apim-4.4+" (see platform_supports)
+	Platforms []string
+	Tags      []string
+	// CategoriesMatch, PlatformsMatch and TagsMatch default to MatchAny
+	CategoriesMatch FilterMatch
+	PlatformsMatch  FilterMatch
+	TagsMatch       FilterMatch
+	// ReleasedAfter and ReleasedBefore bound the release date, inclusively;
+	// versions without one are left out when either is set
+	ReleasedAfter  *time.Time
+	ReleasedBefore *time.Time
+	// Sort defaults to SortRelevance with a search and SortCreated without
+	// one; Order defaults to the key's DefaultOrder
+	Sort  PolicySort
+	Order SortOrder
+	PageRequest
+}
+
+// PageRequest selects a page of a listing, either by number or, when Cursor
+// is set, as the page a cursor of a previous page points to
+type PageRequest struct {
+	Page     int
+	PageSize int
+	Cursor   string
+	// IncludeTotal counts the whole listing for TotalItems and TotalPages
+	IncludeTotal bool
 }
 
-// PaginationInfo holds pagination metadata
+// FacetCount is the number of policies a listing would show with only Value
+// selected for its facet, under the listing's other filters
+type FacetCount struct {
+	Value string
+	Count int
+}
+
+// FacetCounts holds the counted values of each requested facet, most common first
+type FacetCounts map[Facet][]FacetCount
+
+// PolicyPage is a page of the policy listing with the counts read along with it
+type PolicyPage struct {
+	Policies []*PolicyVersion
+	Total    int         // set when the filters include the total
+	Facets   FacetCounts // nil when no facets are counted
+}
+
+// PaginationInfo holds pagination metadata. Page is 0 for pages read by
+// cursor; the totals are only set when requested. Next and Prev are cursors
+// to the neighbouring pages, empty at either end of the listing.
 type PaginationInfo struct {
 	Page       int
 	PageSize   int
-	TotalItems int
-	TotalPages int
+	TotalItems *int
+	TotalPages *int
+	Next       string
+	Prev       string
 }
 
 // CalculateTotalPages calculates total pages from total items and page size
@@ -111,12 +236,16 @@ func CalculateTotalPages(totalItems, pageSize int) int {
 // ResolvePolicyRequest represents a single policy request in the resolve operation
 type ResolvePolicyRequest struct {
 	Name              string
-	RetrievalStrategy string
+	RetrievalStrategy string // alias for a constraint, see strategyConstraint
 	BaseVersion       string
+	Constraint        string // version range expression such as "^1.2.0"
+	IncludePrerelease bool   // let pre-release versions match the constraint
+	VerifiedOnly      bool   // only match versions with a verified release signature
 }
 
 // PolicyResolveItem represents a policy item in resolve response
 type PolicyResolveItem struct {
+	Index      int // position of the request this item answers
 	Name       string
 	Version    string
 	SourceType string
@@ -127,26 +256,51 @@ type PolicyResolveItem struct {
 
 // PolicyResolveError represents an error for a specific policy in resolve response
 type PolicyResolveError struct {
-	Name    string
-	Version string
-	Error   string
+	Index       int // position of the request that failed
+	Name        string
+	Strategy    string
+	BaseVersion string
+	Constraint  string
+	Code        ResolveErrorCode
+	Error       string
+}
+
+// VersionRangeRequest asks for the highest version of a policy within any of the
+// given ranges. Yanked versions are only considered when AllowYanked is set;
+// pre-releases when IncludePrerelease is set or a range bound admits them.
+// VerifiedOnly skips versions without a verified release signature.
+type VersionRangeRequest struct {
+	Name              string
+	Ranges            []semver.Range
+	AllowYanked       bool
+	IncludePrerelease bool
+	VerifiedOnly      bool
 }
 
-// Bulk request types for optimization
-type ExactVersionRequest struct {
-	Name    string
-	Version string
+// LockedPolicy pins a resolved policy to an exact version and definition digest
+type LockedPolicy struct {
+	Index       int // position of the request or lockfile entry
+	Name        string
+	Version     string
+	Digest      string
+	DownloadURL string
 }
 
-type PatchVersionRequest struct {
-	Name         string
-	MajorVersion int32
-	MinorVersion int32
+// LockVerification is the result of checking one lockfile entry against the hub
+type LockVerification struct {
+	Index          int
+	Name           string
+	Version        string
+	Status         LockStatus
+	ActualVersion  string // set when the hub serves another build of the locked version
+	ExpectedDigest string
+	ActualDigest   string
+	Message        string
 }
 
-type MinorVersionRequest struct {
-	Name         string
-	MajorVersion int32
+// Drifted reports whether the hub no longer serves what the entry locked
+func (v LockVerification) Drifted() bool {
+	return v.Status != LockStatusOK && v.Status != LockStatusDeprecated
 }
 
 // PolicyMetadata represents the metadata.json structure
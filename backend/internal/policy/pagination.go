@@ -0,0 +1,163 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package policy
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/wso2/policyhub/internal/errs"
+)
+
+// Keyset is the position of an item in a listing. The page a cursor points
+// to starts right after the item, or ends right before it when Backward is set.
+type Keyset struct {
+	SortKey   string    `json:"k,omitempty"`
+	CreatedAt time.Time `json:"c,omitzero"`
+	Name      string    `json:"n,omitempty"`
+	VersionID int32     `json:"v,omitempty"`
+	Backward  bool      `json:"b,omitempty"`
+}
+
+// cursor is the content of a cursor token. Scope fingerprints the listing the
+// cursor was issued for, so that it is not replayed against another one.
+type cursor struct {
+	Keyset
+	Scope string `json:"s"`
+}
+
+// encodeCursor encodes a keyset as an opaque, URL-safe cursor token
+func encodeCursor(keyset Keyset, scope string) string {
+	raw, _ := json.Marshal(cursor{Keyset: keyset, Scope: scope})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeCursor decodes a cursor token issued for the listing scope; an empty
+// token decodes to nil
+func decodeCursor(token, scope string) (*Keyset, *errs.AppError) {
+	if token == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errs.InvalidCursor("cursor is not a token issued by this API")
+	}
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, errs.InvalidCursor("cursor is not a token issued by this API")
+	}
+	if c.Scope != scope {
+		return nil, errs.InvalidCursor("cursor was issued for a listing with other filters or sorting")
+	}
+	return &c.Keyset, nil
+}
+
+// listingScope fingerprints the parameters that select and order a listing
+func listingScope(parts ...string) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return base64.RawURLEncoding.EncodeToString(hash.Sum(nil)[:12])
+}
+
+// scope fingerprints the filters and sort of a policy listing
+func (f *PolicyFilters) scope() string {
+	return listingScope("policies", f.Search,
+		strings.Join(f.Categories, ","), strings.Join(f.Providers, ","),
+		strings.Join(f.Platforms, ","), strings.Join(f.Tags, ","),
+		string(f.CategoriesMatch), string(f.PlatformsMatch), string(f.TagsMatch),
+		formatDate(f.ReleasedAfter), formatDate(f.ReleasedBefore),
+		string(f.Sort), string(f.Order))
+}
+
+// formatDate formats an optional date for a listing scope
+func formatDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.Format(time.DateOnly)
+}
+
+// versionsScope fingerprints the version listing of a policy
+func versionsScope(name string) string {
+	return listingScope("versions", name)
+}
+
+// policyKeyset is the position of a policy in a policy listing
+func policyKeyset(pv *PolicyVersion, backward bool) Keyset {
+	return Keyset{SortKey: pv.sortKey, CreatedAt: pv.CreatedAt, Name: pv.PolicyName, Backward: backward}
+}
+
+// versionKeyset is the position of a version in a version listing
+func versionKeyset(pv *PolicyVersion, backward bool) Keyset {
+	return Keyset{VersionID: pv.ID, Backward: backward}
+}
+
+// normalize applies the default page and page size
+func (r *PageRequest) normalize() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+	if r.PageSize < MinPageSize || r.PageSize > MaxPageSize {
+		r.PageSize = DefaultPageSize
+	}
+}
+
+// offset is the number of items before the requested page; pages read by
+// cursor start at the cursor instead
+func (r *PageRequest) offset(keyset *Keyset) int {
+	if keyset != nil {
+		return 0
+	}
+	return (r.Page - 1) * r.PageSize
+}
+
+// paginate trims a page read with one item more than the page size, the
+// extra item telling whether the listing continues in the direction it was
+// read, and sets the cursors to the neighbouring pages. Pages read backward
+// from a cursor always have a next page: the one the cursor came from.
+func (r *PageRequest) paginate(items []*PolicyVersion, keyset *Keyset, scope string, keysetOf func(*PolicyVersion, bool) Keyset) ([]*PolicyVersion, *PaginationInfo) {
+	backward := keyset != nil && keyset.Backward
+	more := len(items) > r.PageSize
+	if more && backward {
+		items = items[len(items)-r.PageSize:]
+	} else if more {
+		items = items[:r.PageSize]
+	}
+
+	hasNext, hasPrev := more, keyset != nil || r.offset(keyset) > 0
+	if backward {
+		hasNext, hasPrev = true, more
+	}
+
+	pagination := &PaginationInfo{PageSize: r.PageSize}
+	if keyset == nil {
+		pagination.Page = r.Page
+	}
+	if len(items) > 0 && hasNext {
+		pagination.Next = encodeCursor(keysetOf(items[len(items)-1], false), scope)
+	}
+	if len(items) > 0 && hasPrev {
+		pagination.Prev = encodeCursor(keysetOf(items[0], true), scope)
+	}
+	return items, pagination
+}
+
+// setTotal sets the totals of a listing of total items
+func (p *PaginationInfo) setTotal(total int) {
+	pages := CalculateTotalPages(total, p.PageSize)
+	p.TotalItems = &total
+	p.TotalPages = &pages
+}
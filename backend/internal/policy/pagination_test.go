@@ -0,0 +1,213 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package policy
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wso2/policyhub/internal/errs"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	created := time.Date(2025, 3, 14, 15, 9, 26, 535897000, time.UTC)
+	keysets := []Keyset{
+		{SortKey: "rate-limit", CreatedAt: created, Name: "rate-limit"},
+		{SortKey: "", CreatedAt: created, Name: "cors", Backward: true},
+		{SortKey: "ünïcødé / \"quoted\"", CreatedAt: created, Name: "a"},
+		{VersionID: 42},
+		{VersionID: 7, Backward: true},
+	}
+	scope := versionsScope("rate-limit")
+
+	for _, keyset := range keysets {
+		token := encodeCursor(keyset, scope)
+		if strings.ContainsAny(token, "+/=") {
+			t.Errorf("cursor %q is not URL-safe", token)
+		}
+		decoded, err := decodeCursor(token, scope)
+		if err != nil {
+			t.Fatalf("decodeCursor(%q) error = %v", token, err)
+		}
+		if !decoded.CreatedAt.Equal(keyset.CreatedAt) {
+			t.Errorf("CreatedAt = %v, want %v", decoded.CreatedAt, keyset.CreatedAt)
+		}
+		decoded.CreatedAt = keyset.CreatedAt
+		if *decoded != keyset {
+			t.Errorf("decodeCursor(encodeCursor(%+v)) = %+v", keyset, *decoded)
+		}
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	scope := versionsScope("rate-limit")
+	valid := encodeCursor(Keyset{VersionID: 42}, scope)
+
+	if keyset, err := decodeCursor("", scope); keyset != nil || err != nil {
+		t.Errorf("decodeCursor(\"\") = %v, %v; want nil, nil", keyset, err)
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		reason string
+	}{
+		{"not base64", "not a cursor!", "not a token issued"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"v":42}`)), "not a token issued"},
+		{"not JSON", base64.RawURLEncoding.EncodeToString([]byte("rate-limit")), "not a token issued"},
+		{"wrong field types", base64.RawURLEncoding.EncodeToString([]byte(`{"v":"42","s":"x"}`)), "not a token issued"},
+		{"other policy", encodeCursor(Keyset{VersionID: 42}, versionsScope("cors")), "other filters"},
+		{"no scope", base64.RawURLEncoding.EncodeToString([]byte(`{"v":42}`)), "other filters"},
+		{"truncated", valid[:len(valid)-4], ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyset, err := decodeCursor(tt.token, scope)
+			if err == nil {
+				t.Fatalf("decodeCursor(%q) = %+v, want an error", tt.token, keyset)
+			}
+			if err.Code != errs.CodeInvalidCursor {
+				t.Errorf("error code = %s, want %s", err.Code, errs.CodeInvalidCursor)
+			}
+			if reason, _ := err.Details["reason"].(string); !strings.Contains(reason, tt.reason) {
+				t.Errorf("reason = %q, want it to contain %q", reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestPolicyFiltersScope(t *testing.T) {
+	after := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	base := PolicyFilters{Search: "rate", Categories: []string{"security"}, Sort: SortName, Order: SortAscending}
+	same := base
+	same.PageRequest = PageRequest{Page: 3, PageSize: 50, Cursor: "x", IncludeTotal: true}
+	if base.scope() != same.scope() {
+		t.Error("paging parameters changed the listing scope")
+	}
+
+	variants := map[string]func(*PolicyFilters){
+		"search":       func(f *PolicyFilters) { f.Search = "limit" },
+		"categories":   func(f *PolicyFilters) { f.Categories = []string{"security", "traffic"} },
+		"providers":    func(f *PolicyFilters) { f.Providers = []string{"WSO2"} },
+		"platforms":    func(f *PolicyFilters) { f.Platforms = []string{"apim-4.4"} },
+		"tags":         func(f *PolicyFilters) { f.Tags = []string{"ai"} },
+		"match mode":   func(f *PolicyFilters) { f.CategoriesMatch = MatchAll },
+		"release date": func(f *PolicyFilters) { f.ReleasedAfter = &after },
+		"sort":         func(f *PolicyFilters) { f.Sort = SortCreated },
+		"order":        func(f *PolicyFilters) { f.Order = SortDescending },
+		// Values are joined with separators, so moving one between fields changes the scope
+		"shifted value": func(f *PolicyFilters) { f.Categories, f.Providers = nil, []string{"security"} },
+	}
+	for name, change := range variants {
+		filters := base
+		change(&filters)
+		if filters.scope() == base.scope() {
+			t.Errorf("changing the %s kept the listing scope", name)
+		}
+	}
+	if versionsScope("rate-limit") == versionsScope("cors") {
+		t.Error("version listings of different policies share a scope")
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	scope := versionsScope("rate-limit")
+	versions := func(ids ...int32) []*PolicyVersion {
+		items := make([]*PolicyVersion, len(ids))
+		for i, id := range ids {
+			items[i] = &PolicyVersion{ID: id, PolicyName: "rate-limit", Version: fmt.Sprintf("1.0.%d", id)}
+		}
+		return items
+	}
+	cursorID := func(t *testing.T, token string) (int32, bool) {
+		t.Helper()
+		if token == "" {
+			return 0, false
+		}
+		keyset, err := decodeCursor(token, scope)
+		if err != nil {
+			t.Fatalf("decodeCursor: %v", err)
+		}
+		return keyset.VersionID, keyset.Backward
+	}
+
+	tests := []struct {
+		name     string
+		page     int
+		keyset   *Keyset
+		items    []*PolicyVersion // read with one item more than the page size of 3
+		wantIDs  []int32
+		wantPage int
+		wantNext int32 // ID the next cursor points after, 0 for none
+		wantPrev int32 // ID the prev cursor points before, 0 for none
+	}{
+		{name: "first page", page: 1, items: versions(1, 2, 3, 4), wantIDs: []int32{1, 2, 3}, wantPage: 1, wantNext: 3},
+		{name: "only page", page: 1, items: versions(1, 2), wantIDs: []int32{1, 2}, wantPage: 1},
+		{name: "numbered middle page", page: 2, items: versions(4, 5, 6, 7), wantIDs: []int32{4, 5, 6}, wantPage: 2, wantNext: 6, wantPrev: 4},
+		{name: "numbered page past the end", page: 9, items: nil, wantIDs: []int32{}, wantPage: 9},
+		{name: "forward from cursor", keyset: &Keyset{VersionID: 3}, items: versions(4, 5, 6, 7), wantIDs: []int32{4, 5, 6}, wantNext: 6, wantPrev: 4},
+		{name: "forward to the end", keyset: &Keyset{VersionID: 3}, items: versions(4, 5), wantIDs: []int32{4, 5}, wantPrev: 4},
+		{name: "backward from cursor", keyset: &Keyset{VersionID: 8, Backward: true}, items: versions(4, 5, 6, 7), wantIDs: []int32{5, 6, 7}, wantNext: 7, wantPrev: 5},
+		{name: "backward to the start", keyset: &Keyset{VersionID: 3, Backward: true}, items: versions(1, 2), wantIDs: []int32{1, 2}, wantNext: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := PageRequest{Page: tt.page, PageSize: 3}
+			items, pagination := page.paginate(tt.items, tt.keyset, scope, versionKeyset)
+
+			ids := make([]int32, len(items))
+			for i, item := range items {
+				ids[i] = item.ID
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(tt.wantIDs) {
+				t.Errorf("items = %v, want %v", ids, tt.wantIDs)
+			}
+			if pagination.Page != tt.wantPage {
+				t.Errorf("page = %d, want %d", pagination.Page, tt.wantPage)
+			}
+			if pagination.TotalItems != nil || pagination.TotalPages != nil {
+				t.Error("paginate set totals")
+			}
+			if id, backward := cursorID(t, pagination.Next); id != tt.wantNext || backward {
+				t.Errorf("next cursor points after %d (backward %v), want %d", id, backward, tt.wantNext)
+			}
+			if id, backward := cursorID(t, pagination.Prev); id != tt.wantPrev || (id != 0 && !backward) {
+				t.Errorf("prev cursor points before %d (backward %v), want %d", id, backward, tt.wantPrev)
+			}
+		})
+	}
+}
+
+func TestPageRequestOffset(t *testing.T) {
+	page := PageRequest{Page: 3, PageSize: 20}
+	if got := page.offset(nil); got != 40 {
+		t.Errorf("offset = %d, want 40", got)
+	}
+	if got := page.offset(&Keyset{VersionID: 1}); got != 0 {
+		t.Errorf("offset with a cursor = %d, want 0", got)
+	}
+
+	page = PageRequest{Page: 0, PageSize: MaxPageSize + 1}
+	page.normalize()
+	if page.Page != 1 || page.PageSize != DefaultPageSize {
+		t.Errorf("normalize() = %+v, want page 1 of size %d", page, DefaultPageSize)
+	}
+}
+
+func TestSetTotal(t *testing.T) {
+	pagination := &PaginationInfo{PageSize: 20}
+	pagination.setTotal(41)
+	if *pagination.TotalItems != 41 || *pagination.TotalPages != 3 {
+		t.Errorf("totals = %d items, %d pages; want 41 items, 3 pages", *pagination.TotalItems, *pagination.TotalPages)
+	}
+}
@@ -15,8 +15,12 @@ import (
 
 // Repository defines the interface for policy data access
 type Repository interface {
-	ListPolicies(ctx context.Context, filters PolicyFilters) ([]*PolicyVersion, error)
-	CountPolicies(ctx context.Context, filters PolicyFilters) (int, error)
+	// ListPolicies lists limit policies from offset, or from the position
+	// keyset when it is set; the listing order is kept when reading backward.
+	// In the same query it counts the matching policies when filters include
+	// the total, and counts them per value of each of facets, leaving a
+	// facet's own filter out of its counts.
+	ListPolicies(ctx context.Context, filters PolicyFilters, facets []Facet, keyset *Keyset, offset, limit int) (*PolicyPage, error)
 
 	// Metadata operations
 	GetDistinctCategories(ctx context.Context) ([]string, error)
@@ -24,10 +28,16 @@ type Repository interface {
 	GetDistinctPlatforms(ctx context.Context) ([]string, error)
 
 	GetPolicyVersion(ctx context.Context, name string, version string) (*PolicyVersion, error)
-	ListPolicyVersions(ctx context.Context, name string, page, pageSize int) ([]*PolicyVersion, error)
+	// ListPolicyVersions lists versions like ListPolicies lists policies
+	ListPolicyVersions(ctx context.Context, name string, keyset *Keyset, offset, limit int) ([]*PolicyVersion, error)
 	CountPolicyVersions(ctx context.Context, name string) (int, error)
 	GetLatestPolicyVersion(ctx context.Context, name string) (*PolicyVersion, error)
-	CreatePolicyVersion(ctx context.Context, version *PolicyVersion) (*PolicyVersion, error)
+	// GetPolicyProvider returns the provider that owns the policy, or "" for a new policy
+	GetPolicyProvider(ctx context.Context, name string) (string, error)
+	// CreatePolicyVersion stores a version and its documentation pages in one
+	// transaction, so the version only becomes visible with all of its docs
+	CreatePolicyVersion(ctx context.Context, version *PolicyVersion, docs []*PolicyDoc) (*PolicyVersion, error)
+	UpdatePolicyVersionStatus(ctx context.Context, name, version string, update VersionStatusUpdate) (*PolicyVersion, error)
 
 	// Strategy-based policy retrieval
 	GetPolicyVersionByExact(ctx context.Context, name, version string) (*PolicyVersion, error)
@@ -35,14 +45,19 @@ type Repository interface {
 	GetPolicyVersionByLatestMinor(ctx context.Context, name string, majorVersion int32) (*PolicyVersion, error)
 	GetPolicyVersionByLatestMajor(ctx context.Context, name string) (*PolicyVersion, error)
 
-	// Bulk strategy-based policy retrieval
-	BulkGetPolicyVersionsByExact(ctx context.Context, requests []ExactVersionRequest) ([]*PolicyVersion, error)
-	BulkGetPolicyVersionsByLatestPatch(ctx context.Context, requests []PatchVersionRequest) ([]*PolicyVersion, error)
-	BulkGetPolicyVersionsByLatestMinor(ctx context.Context, requests []MinorVersionRequest) ([]*PolicyVersion, error)
-	BulkGetPolicyVersionsByLatestMajor(ctx context.Context, policyNames []string) ([]*PolicyVersion, error)
+	// Bulk range-based policy retrieval. Results are positional: result[i]
+	// answers requests[i] and is nil when no version matches.
+	BulkGetPolicyVersionsByRanges(ctx context.Context, requests []VersionRangeRequest) ([]*PolicyVersion, error)
+	GetExistingPolicyNames(ctx context.Context, policyNames []string) ([]string, error)
+	// ListExistingVersions returns the stored versions of each given policy
+	ListExistingVersions(ctx context.Context, policyNames []string) (map[string][]string, error)
+
+	// Usage counts, for sorting by popularity
+	IncrementDownloads(ctx context.Context, name string) error
+	// IncrementResolves counts a resolve for every occurrence of a name
+	IncrementResolves(ctx context.Context, names []string) error
 
 	// Documentation operations
 	GetPolicyDoc(ctx context.Context, versionID int32, page string) (*PolicyDoc, error)
 	ListPolicyDocs(ctx context.Context, versionID int32) ([]*PolicyDoc, error)
-	UpsertPolicyDoc(ctx context.Context, doc *PolicyDoc) (*PolicyDoc, error)
 }
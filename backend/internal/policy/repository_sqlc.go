@@ -13,8 +13,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strconv"
-	"strings"
+	"math"
+	"slices"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -22,7 +22,7 @@ import (
 	"github.com/wso2/policyhub/internal/db"
 	"github.com/wso2/policyhub/internal/db/sqlc"
 	"github.com/wso2/policyhub/internal/errs"
-	"golang.org/x/mod/semver"
+	"github.com/wso2/policyhub/internal/semver"
 )
 
 // SQLCRepository implements Repository using sqlc-generated code
@@ -63,6 +63,30 @@ func ptrToPgtypeDate(t *time.Time) pgtype.Date {
 	return pgtype.Date{}
 }
 
+// Helper to convert *time.Time to pgtype.Timestamptz
+func ptrToPgtypeTimestamptz(t *time.Time) pgtype.Timestamptz {
+	if t != nil {
+		return pgtype.Timestamptz{Time: *t, Valid: true}
+	}
+	return pgtype.Timestamptz{}
+}
+
+// Helper to convert pgtype.Int8 to *int64
+func pgtypeInt8ToPtr(pi pgtype.Int8) *int64 {
+	if pi.Valid {
+		return &pi.Int64
+	}
+	return nil
+}
+
+// Helper to convert *int64 to pgtype.Int8
+func ptrToPgtypeInt8(i *int64) pgtype.Int8 {
+	if i != nil {
+		return pgtype.Int8{Int64: *i, Valid: true}
+	}
+	return pgtype.Int8{}
+}
+
 // Helper to convert *time.Time to sql.NullTime
 // Mapper functions to convert between sqlc and domain models
 
@@ -112,6 +136,30 @@ func sqlcToPolicyVersion(spv sqlc.PolicyVersion) (*PolicyVersion, error) {
 		releaseDate = &spv.ReleaseDate.Time
 	}
 
+	// Rows inserted outside the sync pipeline may lack a definition digest
+	definitionDigest := spv.DefinitionDigest.String
+	if !spv.DefinitionDigest.Valid {
+		definitionDigest = ComputeDigest([]byte(spv.DefinitionYaml))
+	}
+
+	var statusReason, replacementVersion *string
+	if spv.StatusReason.Valid {
+		statusReason = &spv.StatusReason.String
+	}
+	if spv.ReplacementVersion.Valid {
+		replacementVersion = &spv.ReplacementVersion.String
+	}
+
+	var statusUpdatedAt *time.Time
+	if spv.StatusUpdatedAt.Valid {
+		statusUpdatedAt = &spv.StatusUpdatedAt.Time
+	}
+
+	var signatureVerifiedAt *time.Time
+	if spv.SignatureVerifiedAt.Valid {
+		signatureVerifiedAt = &spv.SignatureVerifiedAt.Time
+	}
+
 	return &PolicyVersion{
 		ID:         spv.ID,
 		PolicyName: spv.PolicyName,
@@ -136,6 +184,27 @@ func sqlcToPolicyVersion(spv sqlc.PolicyVersion) (*PolicyVersion, error) {
 		SourceURL:      downloadUrl,
 		CreatedAt:      spv.CreatedAt.Time,
 		UpdatedAt:      spv.UpdatedAt.Time,
+
+		// Content digests
+		DefinitionDigest: definitionDigest,
+		ArtifactDigest:   pgtypeTextToPtr(spv.ArtifactDigest),
+
+		// Release signature
+		SignatureStatus:     SignatureStatus(spv.SignatureStatus),
+		SignatureKeyID:      pgtypeTextToPtr(spv.SignatureKeyID),
+		Signature:           pgtypeTextToPtr(spv.Signature),
+		SignatureVerifiedAt: signatureVerifiedAt,
+
+		// Lifecycle status
+		Status:             VersionStatus(spv.Status),
+		StatusReason:       statusReason,
+		ReplacementVersion: replacementVersion,
+		StatusUpdatedAt:    statusUpdatedAt,
+
+		PreviousStatus:       (*VersionStatus)(pgtypeTextToPtr(spv.PreviousStatus)),
+		PreviousStatusReason: pgtypeTextToPtr(spv.PreviousStatusReason),
+
+		BundleID: pgtypeInt8ToPtr(spv.BundleID),
 	}, nil
 }
 
@@ -197,15 +266,29 @@ func filterRowToPolicyVersion(row sqlc.FilterPoliciesByMultipleRow) (*PolicyVers
 		releaseDate = &row.ReleaseDate.Time
 	}
 
+	// Rows inserted outside the sync pipeline may lack a definition digest
+	definitionDigest := row.DefinitionDigest.String
+	if !row.DefinitionDigest.Valid {
+		definitionDigest = ComputeDigest([]byte(row.DefinitionYaml.String))
+	}
+
+	var statusReason, replacementVersion *string
+	if row.StatusReason.Valid {
+		statusReason = &row.StatusReason.String
+	}
+	if row.ReplacementVersion.Valid {
+		replacementVersion = &row.ReplacementVersion.String
+	}
+
 	return &PolicyVersion{
-		ID:         row.ID,
-		PolicyName: row.PolicyName,
-		Version:    row.Version,
+		ID:         row.ID.Int32,
+		PolicyName: row.PolicyName.String,
+		Version:    row.Version.String,
 		IsLatest:   row.IsLatest.Bool,
 
 		// All metadata fields
-		DisplayName:        row.DisplayName,
-		Provider:           row.Provider,
+		DisplayName:        row.DisplayName.String,
+		Provider:           row.Provider.String,
 		Description:        description,
 		Categories:         categories,
 		Tags:               tags,
@@ -215,79 +298,132 @@ func filterRowToPolicyVersion(row sqlc.FilterPoliciesByMultipleRow) (*PolicyVers
 
 		// Version-specific fields
 		ReleaseDate:    releaseDate,
-		DefinitionYAML: row.DefinitionYaml,
+		DefinitionYAML: row.DefinitionYaml.String,
 		IconPath:       iconPath,
 		SourceType:     sourceType,
 		SourceURL:      downloadUrl,
 		CreatedAt:      row.CreatedAt.Time,
 		UpdatedAt:      row.UpdatedAt.Time,
+
+		// Content digests
+		DefinitionDigest: definitionDigest,
+		ArtifactDigest:   pgtypeTextToPtr(row.ArtifactDigest),
+
+		// Release signature
+		SignatureStatus: SignatureStatus(row.SignatureStatus.String),
+		SignatureKeyID:  pgtypeTextToPtr(row.SignatureKeyID),
+
+		// Lifecycle status
+		Status:             VersionStatus(row.Status.String),
+		StatusReason:       statusReason,
+		ReplacementVersion: replacementVersion,
+
+		SearchSnippet: highlightSnippet(row.SearchSnippet),
+		sortKey:       row.SortKey.String,
 	}, nil
 }
 
 // Policy operations
 
-func (r *SQLCRepository) ListPolicies(ctx context.Context, filters PolicyFilters) ([]*PolicyVersion, error) {
+func (r *SQLCRepository) ListPolicies(ctx context.Context, filters PolicyFilters, facets []Facet, keyset *Keyset, offset, limit int) (*PolicyPage, error) {
 	q := r.queries
-	var sqlcPolicies []sqlc.FilterPoliciesByMultipleRow
-	var err error
-
-	offset := int32((filters.Page - 1) * filters.PageSize)
-	limit := int32(filters.PageSize)
-
-	// Use the new combined filtering query
-	search := ""
-	if filters.Search != "" {
-		search = filters.Search
-	}
-
-	sqlcPolicies, err = q.FilterPoliciesByMultiple(ctx, sqlc.FilterPoliciesByMultipleParams{
-		Column1: search,
-		Column2: filters.Categories,
-		Column3: filters.Providers,
-		Column4: filters.Platforms,
-		Limit:   limit,
-		Offset:  offset,
-	})
 
+	names := make([]string, len(facets))
+	for i, facet := range facets {
+		names[i] = string(facet)
+	}
+
+	params := sqlc.FilterPoliciesByMultipleParams{
+		Search:             filters.Search,
+		Categories:         filters.Categories,
+		MatchAllCategories: filters.CategoriesMatch == MatchAll,
+		Providers:          filters.Providers,
+		Platforms:          filters.Platforms,
+		MatchAllPlatforms:  filters.PlatformsMatch == MatchAll,
+		Tags:               filters.Tags,
+		MatchAllTags:       filters.TagsMatch == MatchAll,
+		ReleasedAfter:      ptrToPgtypeDate(filters.ReleasedAfter),
+		ReleasedBefore:     ptrToPgtypeDate(filters.ReleasedBefore),
+		SortBy:             string(filters.Sort),
+		SortOrder:          string(filters.Order),
+		KeyAscending:       filters.Order == SortAscending,
+		RowLimit:           int32(limit),
+		RowOffset:          int32(offset),
+		Facets:             names,
+		IncludeTotal:       filters.IncludeTotal,
+	}
+	if keyset != nil {
+		// Reading backward walks the listing in reverse from the cursor
+		params.KeyAscending = params.KeyAscending != keyset.Backward
+		params.WalkBackward = keyset.Backward
+		params.CursorKey = pgtype.Text{String: keyset.SortKey, Valid: true}
+		params.CursorCreated = pgtype.Timestamptz{Time: keyset.CreatedAt, Valid: true}
+		params.CursorName = pgtype.Text{String: keyset.Name, Valid: true}
+	}
+
+	rows, err := q.FilterPoliciesByMultiple(ctx, params)
 	if err != nil {
 		return nil, errs.NewDatabaseError("failed to list policies", map[string]any{"error": err.Error()})
 	}
 
-	policies := make([]*PolicyVersion, 0, len(sqlcPolicies))
-	for _, sp := range sqlcPolicies {
-		p, err := filterRowToPolicyVersion(sp)
+	page := &PolicyPage{Policies: make([]*PolicyVersion, 0, len(rows))}
+	for _, row := range rows {
+		// An empty page is a single row carrying only the counts
+		if !row.ID.Valid {
+			continue
+		}
+		p, err := filterRowToPolicyVersion(row)
 		if err != nil {
 			return nil, err
 		}
-		policies = append(policies, p)
+		page.Policies = append(page.Policies, p)
+	}
+	if params.WalkBackward {
+		slices.Reverse(page.Policies)
 	}
 
-	return policies, nil
+	// Every row carries the same counts
+	if len(rows) > 0 {
+		page.Total = int(rows[0].TotalCount.Int64)
+	}
+	if len(facets) > 0 {
+		page.Facets = make(FacetCounts, len(facets))
+		for _, facet := range facets {
+			page.Facets[facet] = []FacetCount{}
+		}
+		var values []struct {
+			Facet string `json:"facet"`
+			Value string `json:"value"`
+			Count int    `json:"count"`
+		}
+		if len(rows) > 0 {
+			if err := json.Unmarshal(rows[0].FacetCounts, &values); err != nil {
+				return nil, errs.NewDatabaseError("failed to decode policy facet counts", map[string]any{"error": err.Error()})
+			}
+		}
+		for _, v := range values {
+			facet := Facet(v.Facet)
+			page.Facets[facet] = append(page.Facets[facet], FacetCount{Value: v.Value, Count: v.Count})
+		}
+	}
+
+	return page, nil
 }
 
-func (r *SQLCRepository) CountPolicies(ctx context.Context, filters PolicyFilters) (int, error) {
-	q := r.queries
-	var count int64
-	var err error
+// Usage operations
 
-	// Use the new combined filtering count
-	search := ""
-	if filters.Search != "" {
-		search = filters.Search
+func (r *SQLCRepository) IncrementDownloads(ctx context.Context, name string) error {
+	if err := r.queries.IncrementPolicyDownloads(ctx, name); err != nil {
+		return errs.NewDatabaseError("failed to count policy download", map[string]any{"error": err.Error()})
 	}
+	return nil
+}
 
-	count, err = q.CountPoliciesByMultiple(ctx, sqlc.CountPoliciesByMultipleParams{
-		Column1: search,
-		Column2: filters.Categories,
-		Column3: filters.Providers,
-		Column4: filters.Platforms,
-	})
-
-	if err != nil {
-		return 0, errs.NewDatabaseError("failed to count policies", map[string]any{"error": err.Error()})
+func (r *SQLCRepository) IncrementResolves(ctx context.Context, names []string) error {
+	if err := r.queries.IncrementPolicyResolves(ctx, names); err != nil {
+		return errs.NewDatabaseError("failed to count policy resolves", map[string]any{"error": err.Error()})
 	}
-
-	return int(count), nil
+	return nil
 }
 
 // Metadata operations
@@ -339,16 +475,32 @@ func (r *SQLCRepository) GetPolicyVersion(ctx context.Context, name string, vers
 	return sqlcToPolicyVersion(spv)
 }
 
-func (r *SQLCRepository) ListPolicyVersions(ctx context.Context, name string, page, pageSize int) ([]*PolicyVersion, error) {
+func (r *SQLCRepository) ListPolicyVersions(ctx context.Context, name string, keyset *Keyset, offset, limit int) ([]*PolicyVersion, error) {
 	q := r.queries
-	offset := int32((page - 1) * pageSize)
-	limit := int32(pageSize)
 
-	spvs, err := q.ListPolicyVersions(ctx, sqlc.ListPolicyVersionsParams{
-		PolicyName: name,
-		Limit:      limit,
-		Offset:     offset,
-	})
+	var spvs []sqlc.PolicyVersion
+	var err error
+	switch {
+	case keyset == nil:
+		spvs, err = q.ListPolicyVersions(ctx, sqlc.ListPolicyVersionsParams{
+			PolicyName: name,
+			Limit:      int32(limit),
+			Offset:     int32(offset),
+		})
+	case keyset.Backward:
+		spvs, err = q.ListPolicyVersionsBefore(ctx, sqlc.ListPolicyVersionsBeforeParams{
+			PolicyName: name,
+			BeforeID:   keyset.VersionID,
+			RowLimit:   int32(limit),
+		})
+		slices.Reverse(spvs)
+	default:
+		spvs, err = q.ListPolicyVersionsAfter(ctx, sqlc.ListPolicyVersionsAfterParams{
+			PolicyName: name,
+			AfterID:    keyset.VersionID,
+			RowLimit:   int32(limit),
+		})
+	}
 
 	if err != nil {
 		return nil, errs.NewDatabaseError("failed to list policy versions", map[string]any{"error": err.Error()})
@@ -387,6 +539,22 @@ func (r *SQLCRepository) GetLatestPolicyVersion(ctx context.Context, name string
 	return sqlcToPolicyVersion(spv)
 }
 
+func (r *SQLCRepository) GetPolicyProvider(ctx context.Context, name string) (string, error) {
+	return policyProvider(ctx, r.queries, name)
+}
+
+// policyProvider returns the provider that owns a policy, or "" when it has no versions
+func policyProvider(ctx context.Context, q *sqlc.Queries, name string) (string, error) {
+	provider, err := q.GetPolicyProvider(ctx, name)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", nil
+		}
+		return "", errs.NewDatabaseError("failed to get policy provider", map[string]any{"error": err.Error()})
+	}
+	return provider, nil
+}
+
 // determineIsLatestInTransaction determines if a version should be latest within a transaction
 func (r *SQLCRepository) determineIsLatestInTransaction(ctx context.Context, q *sqlc.Queries, policyName, newVersion string) (bool, error) {
 	// Get the current latest version within this transaction
@@ -399,25 +567,56 @@ func (r *SQLCRepository) determineIsLatestInTransaction(ctx context.Context, q *
 		return false, errs.NewDatabaseError("failed to get current latest version", map[string]any{"error": err.Error()})
 	}
 
-	// Compare versions semantically using semver
-	result := semver.Compare(r.normalizeVersion(newVersion), r.normalizeVersion(currentLatest.Version))
-
 	// Version should be latest if it's greater than current latest
-	return result > 0, nil
+	return r.isNewerVersion(newVersion, currentLatest.Version), nil
 }
 
-// normalizeVersion ensures version strings are in semver format (adds 'v' prefix if missing)
-func (r *SQLCRepository) normalizeVersion(version string) string {
-	if version == "" {
-		return "v0.0.0"
+// recomputeLatestInTransaction marks the highest non-yanked release (or pre-release,
+// when the policy has no release) as latest within a transaction
+func (r *SQLCRepository) recomputeLatestInTransaction(ctx context.Context, q *sqlc.Queries, policyName string) error {
+	versions, err := q.ListResolvableVersions(ctx, policyName)
+	if err != nil {
+		return errs.NewDatabaseError("failed to list resolvable versions", map[string]any{"error": err.Error()})
+	}
+
+	latest := ""
+	for _, v := range versions {
+		if latest == "" || r.isNewerVersion(v, latest) {
+			latest = v
+		}
+	}
+
+	// Clear the current flag first so the unique latest index is never violated mid-update
+	if err := q.ClearLatestVersion(ctx, policyName); err != nil {
+		return errs.NewDatabaseError("failed to clear latest version flag", map[string]any{"error": err.Error()})
+	}
+
+	if latest == "" {
+		return nil
+	}
+
+	err = q.UpdateLatestVersion(ctx, sqlc.UpdateLatestVersionParams{
+		PolicyName: policyName,
+		Version:    latest,
+	})
+	if err != nil {
+		return errs.NewDatabaseError("failed to update latest version flags", map[string]any{"error": err.Error()})
 	}
-	if version[0] != 'v' {
-		return "v" + version
+
+	return nil
+}
+
+// isNewerVersion reports whether candidate should replace current as the latest version.
+// Releases always win over pre-releases, so a pre-release is only latest while a
+// policy has no release; otherwise versions are compared by semver precedence.
+func (r *SQLCRepository) isNewerVersion(candidate, current string) bool {
+	if candidatePre, currentPre := semver.IsPrerelease(candidate), semver.IsPrerelease(current); candidatePre != currentPre {
+		return currentPre
 	}
-	return version
+	return semver.Compare(candidate, current) > 0
 }
 
-func (r *SQLCRepository) CreatePolicyVersion(ctx context.Context, version *PolicyVersion) (*PolicyVersion, error) {
+func (r *SQLCRepository) CreatePolicyVersion(ctx context.Context, version *PolicyVersion, docs []*PolicyDoc) (*PolicyVersion, error) {
 	// Use transaction to ensure atomicity
 	tx, err := r.db.Pool.Begin(ctx)
 	if err != nil {
@@ -427,6 +626,20 @@ func (r *SQLCRepository) CreatePolicyVersion(ctx context.Context, version *Polic
 
 	q := sqlc.New(tx)
 
+	// Serialize with other writers (syncs, status changes) for this policy
+	if err := q.LockPolicy(ctx, version.PolicyName); err != nil {
+		return nil, errs.NewDatabaseError("failed to lock policy", map[string]any{"error": err.Error()})
+	}
+
+	// A policy belongs to the provider that first published it
+	owner, err := policyProvider(ctx, q, version.PolicyName)
+	if err != nil {
+		return nil, err
+	}
+	if owner != "" && owner != version.Provider {
+		return nil, errs.PolicyOwnedByOtherProvider(version.PolicyName, owner, version.Provider)
+	}
+
 	// Determine if this version should be latest by comparing with current latest
 	isLatest, err := r.determineIsLatestInTransaction(ctx, q, version.PolicyName, version.Version)
 	if err != nil {
@@ -445,34 +658,62 @@ func (r *SQLCRepository) CreatePolicyVersion(ctx context.Context, version *Polic
 		}
 	}
 
+	signatureStatus := version.SignatureStatus
+	if signatureStatus == "" {
+		signatureStatus = SignatureStatusUnsigned
+	}
+
 	platformsJSON, _ := json.Marshal(version.SupportedPlatforms)
 
 	categoriesJSON, _ := json.Marshal(version.Categories)
 	tagsJSON, _ := json.Marshal(version.Tags)
 
 	spv, err := q.InsertPolicyVersion(ctx, sqlc.InsertPolicyVersionParams{
-		PolicyName:         version.PolicyName,
-		Version:            version.Version,
-		IsLatest:           pgtype.Bool{Bool: version.IsLatest, Valid: true},
-		DisplayName:        version.DisplayName,
-		Provider:           version.Provider,
-		Description:        ptrToPgtypeText(version.Description),
-		Categories:         categoriesJSON,
-		Tags:               tagsJSON,
-		LogoPath:           ptrToPgtypeText(version.LogoPath),
-		BannerPath:         ptrToPgtypeText(version.BannerPath),
-		SupportedPlatforms: platformsJSON,
-		ReleaseDate:        ptrToPgtypeDate(version.ReleaseDate),
-		DefinitionYaml:     version.DefinitionYAML,
-		IconPath:           ptrToPgtypeText(version.IconPath),
-		SourceType:         ptrToPgtypeText(version.SourceType),
-		DownloadUrl:        ptrToPgtypeText(version.SourceURL),
+		PolicyName:          version.PolicyName,
+		Version:             version.Version,
+		IsLatest:            pgtype.Bool{Bool: version.IsLatest, Valid: true},
+		DisplayName:         version.DisplayName,
+		Provider:            version.Provider,
+		Description:         ptrToPgtypeText(version.Description),
+		Categories:          categoriesJSON,
+		Tags:                tagsJSON,
+		LogoPath:            ptrToPgtypeText(version.LogoPath),
+		BannerPath:          ptrToPgtypeText(version.BannerPath),
+		SupportedPlatforms:  platformsJSON,
+		ReleaseDate:         ptrToPgtypeDate(version.ReleaseDate),
+		DefinitionYaml:      version.DefinitionYAML,
+		IconPath:            ptrToPgtypeText(version.IconPath),
+		SourceType:          ptrToPgtypeText(version.SourceType),
+		DownloadUrl:         ptrToPgtypeText(version.SourceURL),
+		DefinitionDigest:    pgtype.Text{String: version.DefinitionDigest, Valid: true},
+		ArtifactDigest:      ptrToPgtypeText(version.ArtifactDigest),
+		SignatureStatus:     string(signatureStatus),
+		SignatureKeyID:      ptrToPgtypeText(version.SignatureKeyID),
+		Signature:           ptrToPgtypeText(version.Signature),
+		SignatureVerifiedAt: ptrToPgtypeTimestamptz(version.SignatureVerifiedAt),
+		BundleID:            ptrToPgtypeInt8(version.BundleID),
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
+	for _, doc := range docs {
+		_, err := q.UpsertPolicyDoc(ctx, sqlc.UpsertPolicyDocParams{
+			PolicyVersionID: spv.ID,
+			Page:            doc.Page,
+			ContentMd:       doc.ContentMd,
+		})
+		if err != nil {
+			return nil, errs.NewDatabaseError("failed to store policy doc", map[string]any{"page": doc.Page, "error": err.Error()})
+		}
+	}
+
+	// Index the version for search once its docs are stored
+	if err := q.RefreshPolicyVersionSearch(ctx, spv.ID); err != nil {
+		return nil, errs.NewDatabaseError("failed to index policy version for search", map[string]any{"error": err.Error()})
+	}
+
 	// Commit transaction
 	if err = tx.Commit(ctx); err != nil {
 		return nil, errs.NewDatabaseError("failed to commit transaction", map[string]any{"error": err.Error()})
@@ -481,6 +722,57 @@ func (r *SQLCRepository) CreatePolicyVersion(ctx context.Context, version *Polic
 	return sqlcToPolicyVersion(spv)
 }
 
+// UpdatePolicyVersionStatus changes the lifecycle status of a version and recomputes is_latest atomically
+func (r *SQLCRepository) UpdatePolicyVersionStatus(ctx context.Context, name, version string, update VersionStatusUpdate) (*PolicyVersion, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, errs.NewDatabaseError("failed to start transaction", map[string]any{"error": err.Error()})
+	}
+	defer tx.Rollback(ctx)
+
+	q := sqlc.New(tx)
+
+	if err := q.LockPolicy(ctx, name); err != nil {
+		return nil, errs.NewDatabaseError("failed to lock policy", map[string]any{"error": err.Error()})
+	}
+
+	_, err = q.UpdatePolicyVersionStatus(ctx, sqlc.UpdatePolicyVersionStatusParams{
+		PolicyName:           name,
+		Version:              version,
+		Status:               string(update.Status),
+		StatusReason:         ptrToPgtypeText(update.Reason),
+		ReplacementVersion:   ptrToPgtypeText(update.ReplacementVersion),
+		PreviousStatus:       ptrToPgtypeText((*string)(update.PreviousStatus)),
+		PreviousStatusReason: ptrToPgtypeText(update.PreviousReason),
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.NewNotFoundError(errs.CodePolicyVersionNotFound, "Policy version not found", map[string]any{"policyName": name, "version": version})
+		}
+		return nil, errs.NewDatabaseError("failed to update policy version status", map[string]any{"error": err.Error()})
+	}
+
+	// Yanking or restoring a version changes which versions are eligible to be latest
+	if err := r.recomputeLatestInTransaction(ctx, q, name); err != nil {
+		return nil, err
+	}
+
+	// Re-read the row so the returned is_latest flag reflects the recomputation
+	spv, err := q.GetPolicyVersion(ctx, sqlc.GetPolicyVersionParams{
+		PolicyName: name,
+		Version:    version,
+	})
+	if err != nil {
+		return nil, errs.NewDatabaseError("failed to get policy version", map[string]any{"error": err.Error()})
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, errs.NewDatabaseError("failed to commit transaction", map[string]any{"error": err.Error()})
+	}
+
+	return sqlcToPolicyVersion(spv)
+}
+
 // PolicyDoc operations
 
 func (r *SQLCRepository) GetPolicyDoc(ctx context.Context, versionID int32, page string) (*PolicyDoc, error) {
@@ -513,19 +805,6 @@ func (r *SQLCRepository) ListPolicyDocs(ctx context.Context, versionID int32) ([
 	return docs, nil
 }
 
-func (r *SQLCRepository) UpsertPolicyDoc(ctx context.Context, doc *PolicyDoc) (*PolicyDoc, error) {
-	q := r.queries
-	spd, err := q.UpsertPolicyDoc(ctx, sqlc.UpsertPolicyDocParams{
-		PolicyVersionID: doc.PolicyVersionID,
-		Page:            doc.Page,
-		ContentMd:       doc.ContentMd,
-	})
-	if err != nil {
-		return nil, errs.NewDatabaseError("failed to upsert policy doc", map[string]any{"error": err.Error()})
-	}
-	return sqlcToPolicyDoc(spd), nil
-}
-
 // Strategy-based policy retrieval methods
 
 func (r *SQLCRepository) GetPolicyVersionByExact(ctx context.Context, name, version string) (*PolicyVersion, error) {
@@ -586,138 +865,115 @@ func (r *SQLCRepository) GetPolicyVersionByLatestMajor(ctx context.Context, name
 	return sqlcToPolicyVersion(spv)
 }
 
-// Bulk strategy-based policy retrieval methods
+// Bulk range-based policy retrieval methods
 
-func (r *SQLCRepository) BulkGetPolicyVersionsByExact(ctx context.Context, requests []ExactVersionRequest) ([]*PolicyVersion, error) {
+// BulkGetPolicyVersionsByRanges flattens every request into one row per range and
+// resolves them all in a single query
+func (r *SQLCRepository) BulkGetPolicyVersionsByRanges(ctx context.Context, requests []VersionRangeRequest) ([]*PolicyVersion, error) {
 	if len(requests) == 0 {
 		return []*PolicyVersion{}, nil
 	}
 
-	// For now, fall back to individual queries for exact matches
-	// This is still efficient since we use individual optimal queries
-	results := make([]*PolicyVersion, 0, len(requests))
-	for _, req := range requests {
-		pv, err := r.GetPolicyVersionByExact(ctx, req.Name, req.Version)
-		if err == nil {
-			results = append(results, pv)
+	var params sqlc.BulkGetPolicyVersionsByRangesParams
+	for i, req := range requests {
+		for _, rng := range req.Ranges {
+			lower := semver.Bound{Version: minRangeVersion, Inclusive: true}
+			if rng.Lower != nil {
+				lower = *rng.Lower
+			}
+			upper := semver.Bound{Version: maxRangeVersion, Inclusive: true}
+			if rng.Upper != nil {
+				upper = *rng.Upper
+			}
+
+			params.RequestIndexes = append(params.RequestIndexes, int32(i+1))
+			params.PolicyNames = append(params.PolicyNames, req.Name)
+			params.LowerMajors = append(params.LowerMajors, clampInt32(lower.Version.Major))
+			params.LowerMinors = append(params.LowerMinors, clampInt32(lower.Version.Minor))
+			params.LowerPatches = append(params.LowerPatches, clampInt32(lower.Version.Patch))
+			params.LowerPrereleases = append(params.LowerPrereleases, lower.Version.Prerelease)
+			params.LowerInclusive = append(params.LowerInclusive, lower.Inclusive)
+			params.UpperMajors = append(params.UpperMajors, clampInt32(upper.Version.Major))
+			params.UpperMinors = append(params.UpperMinors, clampInt32(upper.Version.Minor))
+			params.UpperPatches = append(params.UpperPatches, clampInt32(upper.Version.Patch))
+			params.UpperPrereleases = append(params.UpperPrereleases, upper.Version.Prerelease)
+			params.UpperInclusive = append(params.UpperInclusive, upper.Inclusive)
+			params.AllowYanked = append(params.AllowYanked, req.AllowYanked)
+			params.IncludePrerelease = append(params.IncludePrerelease, req.IncludePrerelease)
+			params.VerifiedOnly = append(params.VerifiedOnly, req.VerifiedOnly)
 		}
-		// Skip errors - they will be handled as missing policies
 	}
 
-	return results, nil
-}
+	results := make([]*PolicyVersion, len(requests))
+	if len(params.RequestIndexes) == 0 {
+		// No request has a satisfiable range
+		return results, nil
+	}
 
-func (r *SQLCRepository) BulkGetPolicyVersionsByLatestPatch(ctx context.Context, requests []PatchVersionRequest) ([]*PolicyVersion, error) {
-	if len(requests) == 0 {
-		return []*PolicyVersion{}, nil
+	rows, err := r.queries.BulkGetPolicyVersionsByRanges(ctx, params)
+	if err != nil {
+		return nil, errs.NewDatabaseError("failed to bulk get policy versions by ranges", map[string]any{"error": err.Error()})
 	}
 
-	// For now, fall back to individual queries
-	results := make([]*PolicyVersion, 0, len(requests))
-	for _, req := range requests {
-		pv, err := r.GetPolicyVersionByLatestPatch(ctx, req.Name, req.MajorVersion, req.MinorVersion)
-		if err == nil {
-			results = append(results, pv)
+	for _, row := range rows {
+		if err := placeBulkResult(results, row.RequestIndex, row.PolicyVersion); err != nil {
+			return nil, err
 		}
 	}
 
 	return results, nil
 }
 
-func (r *SQLCRepository) BulkGetPolicyVersionsByLatestMinor(ctx context.Context, requests []MinorVersionRequest) ([]*PolicyVersion, error) {
-	if len(requests) == 0 {
-		return []*PolicyVersion{}, nil
-	}
+// minRangeVersion and maxRangeVersion stand in for the unbounded ends of a range.
+// minRangeVersion sorts below every version, pre-releases of 0.0.0 included.
+var (
+	minRangeVersion = semver.Version{Major: -1}
+	maxRangeVersion = semver.Version{Major: math.MaxInt32, Minor: math.MaxInt32, Patch: math.MaxInt32}
+)
 
-	// For now, fall back to individual queries
-	results := make([]*PolicyVersion, 0, len(requests))
-	for _, req := range requests {
-		pv, err := r.GetPolicyVersionByLatestMinor(ctx, req.Name, req.MajorVersion)
-		if err == nil {
-			results = append(results, pv)
-		}
+// clampInt32 fits a version number into the int columns of policy_version
+func clampInt32(n int) int32 {
+	if n > math.MaxInt32 {
+		return math.MaxInt32
 	}
-
-	return results, nil
+	return int32(n)
 }
 
-func (r *SQLCRepository) BulkGetPolicyVersionsByLatestMajor(ctx context.Context, policyNames []string) ([]*PolicyVersion, error) {
-	if len(policyNames) == 0 {
-		return []*PolicyVersion{}, nil
+// placeBulkResult stores a bulk query row at the position of the request it answers.
+// Request indexes are 1-based.
+func placeBulkResult(results []*PolicyVersion, requestIndex int32, spv sqlc.PolicyVersion) error {
+	i := int(requestIndex) - 1
+	if i < 0 || i >= len(results) {
+		return errs.NewDatabaseError("bulk query returned an out of range request index", map[string]any{"requestIndex": requestIndex})
 	}
 
-	// Use the bulk query for this case since it's simpler
-	q := r.queries
-	spvs, err := q.BulkGetPolicyVersionsByNames(ctx, policyNames)
+	pv, err := sqlcToPolicyVersion(spv)
 	if err != nil {
-		return nil, errs.NewDatabaseError("failed to bulk get policy versions by names", map[string]any{"error": err.Error()})
+		return err
 	}
-
-	// Group by policy name and find latest major version for each
-	policyMap := make(map[string][]*PolicyVersion)
-	for _, spv := range spvs {
-		pv, err := sqlcToPolicyVersion(spv)
-		if err != nil {
-			continue
-		}
-		policyMap[pv.PolicyName] = append(policyMap[pv.PolicyName], pv)
-	}
-
-	// Find latest major version for each policy
-	results := make([]*PolicyVersion, 0, len(policyNames))
-	for _, policyName := range policyNames {
-		if versions, exists := policyMap[policyName]; exists {
-			latestVersion := r.findLatestMajorVersion(versions)
-			if latestVersion != nil {
-				results = append(results, latestVersion)
-			}
-		}
-	}
-
-	return results, nil
+	results[i] = pv
+	return nil
 }
 
-// Helper function to find latest major version from a slice of PolicyVersion
-func (r *SQLCRepository) findLatestMajorVersion(versions []*PolicyVersion) *PolicyVersion {
-	if len(versions) == 0 {
-		return nil
-	}
-
-	latest := versions[0]
-	for _, pv := range versions[1:] {
-		if r.compareVersions(pv.Version, latest.Version) > 0 {
-			latest = pv
-		}
+// GetExistingPolicyNames returns which of the given policy names have at least one version
+func (r *SQLCRepository) GetExistingPolicyNames(ctx context.Context, policyNames []string) ([]string, error) {
+	names, err := r.queries.GetExistingPolicyNames(ctx, policyNames)
+	if err != nil {
+		return nil, errs.NewDatabaseError("failed to get existing policy names", map[string]any{"error": err.Error()})
 	}
-
-	return latest
+	return names, nil
 }
 
-// Helper function to compare semantic versions
-func (r *SQLCRepository) compareVersions(v1, v2 string) int {
-	// Parse versions
-	v1Parts := strings.Split(v1, ".")
-	v2Parts := strings.Split(v2, ".")
-
-	// Ensure both have 3 parts
-	for len(v1Parts) < 3 {
-		v1Parts = append(v1Parts, "0")
-	}
-	for len(v2Parts) < 3 {
-		v2Parts = append(v2Parts, "0")
+// ListExistingVersions returns the stored versions of each given policy, keyed by policy name
+func (r *SQLCRepository) ListExistingVersions(ctx context.Context, policyNames []string) (map[string][]string, error) {
+	rows, err := r.queries.ListExistingVersions(ctx, policyNames)
+	if err != nil {
+		return nil, errs.NewDatabaseError("failed to list existing versions", map[string]any{"error": err.Error()})
 	}
 
-	// Compare each part
-	for i := 0; i < 3; i++ {
-		v1Int, _ := strconv.Atoi(v1Parts[i])
-		v2Int, _ := strconv.Atoi(v2Parts[i])
-
-		if v1Int > v2Int {
-			return 1
-		} else if v1Int < v2Int {
-			return -1
-		}
+	versions := make(map[string][]string)
+	for _, row := range rows {
+		versions[row.PolicyName] = append(versions[row.PolicyName], row.Version)
 	}
-
-	return 0
+	return versions, nil
 }
@@ -0,0 +1,29 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package policy
+
+import (
+	"html"
+	"strings"
+)
+
+// snippetMarks restores the <mark> tags ts_headline wraps search matches in
+// (see FilterPoliciesByMultiple) once the snippet is escaped
+var snippetMarks = strings.NewReplacer(
+	html.EscapeString("<mark>"), "<mark>",
+	html.EscapeString("</mark>"), "</mark>",
+)
+
+// highlightSnippet makes a search headline safe to render as HTML: everything
+// but the <mark> tags is escaped and whitespace, including the line breaks of
+// markdown docs, is collapsed
+func highlightSnippet(headline string) string {
+	return snippetMarks.Replace(html.EscapeString(strings.Join(strings.Fields(headline), " ")))
+}
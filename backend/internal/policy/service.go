@@ -11,62 +11,93 @@ package policy
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"strconv"
+	"regexp"
+	"sort"
 	"strings"
-	"sync"
 
 	"go.uber.org/zap"
 
 	"github.com/wso2/policyhub/internal/errs"
 	"github.com/wso2/policyhub/internal/logging"
+	"github.com/wso2/policyhub/internal/semver"
+)
+
+// Base version formats accepted by the retrieval strategy aliases
+var (
+	exactBaseVersion      = regexp.MustCompile(VersionRegex)
+	majorMinorBaseVersion = regexp.MustCompile(`^\d+\.\d+$`)
+	majorBaseVersion      = regexp.MustCompile(`^\d+$`)
 )
 
 // Service implements business logic for policies
 type Service struct {
-	repo   Repository
-	logger *logging.Logger
+	repo        Repository
+	logger      *logging.Logger
+	definitions *definitionCache
 }
 
 // NewService creates a new policy service
 func NewService(repo Repository, logger *logging.Logger) *Service {
 	return &Service{
-		repo:   repo,
-		logger: logger,
+		repo:        repo,
+		logger:      logger,
+		definitions: newDefinitionCache(DefinitionCacheSize),
 	}
 }
 
-// ListPolicies retrieves a paginated list of policies with smart fallback to older versions
-func (s *Service) ListPolicies(ctx context.Context, filters PolicyFilters) ([]*PolicyVersion, *PaginationInfo, error) {
+// ListPolicies retrieves a page of policies with smart fallback to older
+// versions. Pages are read by number or by a cursor from a previous page;
+// cursors stay stable while policies are published between requests.
+//
+// The total is counted when requested, and each of facets is counted per
+// value. A facet's own filter is left out of its counts, so each count is what
+// the listing would total with only that value selected; only the
+// MaxFacetValues most common values are kept. The page, total and counts are
+// read by one query, so they always agree.
+func (s *Service) ListPolicies(ctx context.Context, filters PolicyFilters, facets []Facet) ([]*PolicyVersion, *PaginationInfo, FacetCounts, error) {
 	// Validate and set defaults
-	if filters.Page < 1 {
-		filters.Page = 1
+	filters.normalize()
+	if filters.Sort == "" {
+		filters.Sort = SortRelevance
 	}
-	if filters.PageSize < MinPageSize || filters.PageSize > MaxPageSize {
-		filters.PageSize = DefaultPageSize
+	if filters.Sort == SortRelevance && filters.Search == "" {
+		filters.Sort = SortCreated
+	}
+	if filters.Order == "" {
+		filters.Order = filters.Sort.DefaultOrder()
+	}
+	for _, match := range []*FilterMatch{&filters.CategoriesMatch, &filters.PlatformsMatch, &filters.TagsMatch} {
+		if *match == "" {
+			*match = MatchAny
+		}
 	}
 
-	// Database handles smart version selection AND pagination efficiently
-	policies, err := s.repo.ListPolicies(ctx, filters)
-	if err != nil {
-		return nil, nil, errs.SanitizeDatabaseError("listing policies")
+	scope := filters.scope()
+	keyset, cursorErr := decodeCursor(filters.Cursor, scope)
+	if cursorErr != nil {
+		return nil, nil, nil, cursorErr
 	}
 
-	// Count unique policies for pagination
-	total, err := s.repo.CountPolicies(ctx, filters)
+	// Database handles smart version selection, pagination and the counts in one query
+	page, err := s.repo.ListPolicies(ctx, filters, facets, keyset, filters.offset(keyset), filters.PageSize+1)
 	if err != nil {
-		return nil, nil, errs.SanitizeDatabaseError("counting policies")
+		s.logger.Error("Failed to list policies", zap.Error(err))
+		return nil, nil, nil, errs.SanitizeDatabaseError("listing policies")
 	}
 
-	pagination := &PaginationInfo{
-		Page:       filters.Page,
-		PageSize:   filters.PageSize,
-		TotalItems: total,
-		TotalPages: CalculateTotalPages(total, filters.PageSize),
+	policies, pagination := filters.paginate(page.Policies, keyset, scope, policyKeyset)
+	if filters.IncludeTotal {
+		pagination.setTotal(page.Total)
+	}
+	counts := page.Facets
+	for facet, values := range counts {
+		if len(values) > MaxFacetValues {
+			counts[facet] = values[:MaxFacetValues]
+		}
 	}
 
-	return policies, pagination, nil
+	return policies, pagination, counts, nil
 }
 
 // GetPolicyWithLatestVersion retrieves the latest policy version (contains all policy data)
@@ -79,30 +110,28 @@ func (s *Service) GetPolicyWithLatestVersion(ctx context.Context, name string) (
 	return latestVersion, nil
 }
 
-// ListPolicyVersions retrieves versions for a policy
-func (s *Service) ListPolicyVersions(ctx context.Context, name string, page, pageSize int) ([]*PolicyVersion, *PaginationInfo, error) {
-	if page < 1 {
-		page = 1
-	}
-	if pageSize < MinPageSize || pageSize > MaxPageSize {
-		pageSize = DefaultPageSize
-	}
+// ListPolicyVersions retrieves a page of the versions of a policy, newest first
+func (s *Service) ListPolicyVersions(ctx context.Context, name string, page PageRequest) ([]*PolicyVersion, *PaginationInfo, error) {
+	page.normalize()
 
-	versions, err := s.repo.ListPolicyVersions(ctx, name, page, pageSize)
-	if err != nil {
-		return nil, nil, errs.NewDatabaseError("Failed to list versions", map[string]any{"error": err.Error()})
+	scope := versionsScope(name)
+	keyset, cursorErr := decodeCursor(page.Cursor, scope)
+	if cursorErr != nil {
+		return nil, nil, cursorErr
 	}
 
-	total, err := s.repo.CountPolicyVersions(ctx, name)
+	versions, err := s.repo.ListPolicyVersions(ctx, name, keyset, page.offset(keyset), page.PageSize+1)
 	if err != nil {
-		return nil, nil, errs.NewDatabaseError("Failed to count versions", map[string]any{"error": err.Error()})
+		return nil, nil, errs.NewDatabaseError("Failed to list versions", map[string]any{"error": err.Error()})
 	}
+	versions, pagination := page.paginate(versions, keyset, scope, versionKeyset)
 
-	pagination := &PaginationInfo{
-		Page:       page,
-		PageSize:   pageSize,
-		TotalItems: total,
-		TotalPages: CalculateTotalPages(total, pageSize),
+	if page.IncludeTotal {
+		total, err := s.repo.CountPolicyVersions(ctx, name)
+		if err != nil {
+			return nil, nil, errs.NewDatabaseError("Failed to count versions", map[string]any{"error": err.Error()})
+		}
+		pagination.setTotal(total)
 	}
 
 	return versions, pagination, nil
@@ -128,14 +157,46 @@ func (s *Service) GetLatestPolicyVersion(ctx context.Context, name string) (*Pol
 	return latestVersion, nil
 }
 
-// GetPolicyDefinition retrieves the raw policy definition JSON
-func (s *Service) GetPolicyDefinition(ctx context.Context, name, version string) (json.RawMessage, error) {
+// GetPolicyDefinition retrieves a policy definition in the given format and
+// the digest of the definition as published
+func (s *Service) GetPolicyDefinition(ctx context.Context, name, version string, format DefinitionFormat) ([]byte, string, error) {
 	policyVersion, err := s.GetPolicyVersion(ctx, name, version)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	definition := []byte(policyVersion.DefinitionYAML)
+	if format == DefinitionFormatJSON {
+		if definition, err = s.DefinitionJSON(policyVersion); err != nil {
+			s.logger.Error("Failed to convert policy definition", zap.Error(err))
+			return nil, "", errs.NewInternalError("Failed to convert policy definition", nil)
+		}
+	}
+
+	s.RecordDownload(ctx, name)
+	return definition, policyVersion.DefinitionDigest, nil
+}
+
+// RecordDownload counts a download of a policy for sorting by popularity.
+// Failures are logged rather than failing the download.
+func (s *Service) RecordDownload(ctx context.Context, name string) {
+	if err := s.repo.IncrementDownloads(ctx, name); err != nil {
+		s.logger.Warn("Failed to record policy download", zap.String("policy", name), zap.Error(err))
 	}
+}
 
-	return []byte(policyVersion.DefinitionYAML), nil
+// recordResolves counts the policies a resolve request resolved, like RecordDownload
+func (s *Service) recordResolves(ctx context.Context, items []PolicyResolveItem) {
+	if len(items) == 0 {
+		return
+	}
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = item.Name
+	}
+	if err := s.repo.IncrementResolves(ctx, names); err != nil {
+		s.logger.Warn("Failed to record policy resolves", zap.Int("count", len(names)), zap.Error(err))
+	}
 }
 
 // GetAllDocs retrieves all documentation pages for a version
@@ -173,8 +234,18 @@ func (s *Service) GetSingleDoc(ctx context.Context, name, version, page string)
 	return doc.ContentMd, nil
 }
 
-// CreatePolicyVersion creates a new policy version
-func (s *Service) CreatePolicyVersion(ctx context.Context, version *PolicyVersion) (*PolicyVersion, error) {
+// ListExistingVersions returns the stored versions of each given policy, yanked ones included
+func (s *Service) ListExistingVersions(ctx context.Context, names []string) (map[string][]string, error) {
+	versions, err := s.repo.ListExistingVersions(ctx, names)
+	if err != nil {
+		s.logger.Error("Failed to list existing versions", zap.Error(err))
+		return nil, errs.SanitizeDatabaseError("listing existing versions")
+	}
+	return versions, nil
+}
+
+// CreatePolicyVersion creates a new policy version together with its documentation pages
+func (s *Service) CreatePolicyVersion(ctx context.Context, version *PolicyVersion, docs []*PolicyDoc) (*PolicyVersion, error) {
 	s.logger.Info("Creating policy version",
 		zap.String("policyName", version.PolicyName),
 		zap.String("version", version.Version))
@@ -188,13 +259,20 @@ func (s *Service) CreatePolicyVersion(ctx context.Context, version *PolicyVersio
 		return nil, err
 	}
 
+	// The stored digest always describes the stored definition
+	version.DefinitionDigest = ComputeDigest([]byte(version.DefinitionYAML))
+
 	// IsLatest will be determined atomically in the repository based on semantic versioning
 
 	// Attempt to create the version - database unique constraint will prevent duplicates
-	created, err := s.repo.CreatePolicyVersion(ctx, version)
+	created, err := s.repo.CreatePolicyVersion(ctx, version, docs)
 	if err != nil {
 		// Check if this is a unique constraint violation
 		if errs.IsUniqueConstraintError(err) {
+			// Published versions are immutable: different content under the same version is tampering
+			if mismatch := s.checkExistingDigests(ctx, version); mismatch != nil {
+				return nil, mismatch
+			}
 			s.logger.Info("Policy version creation skipped - version already exists",
 				zap.String("policyName", version.PolicyName),
 				zap.String("version", version.Version))
@@ -203,6 +281,13 @@ func (s *Service) CreatePolicyVersion(ctx context.Context, version *PolicyVersio
 				"version":    version.Version,
 			})
 		}
+		if appErr, ok := err.(*errs.AppError); ok && appErr.Code == errs.CodeForbidden {
+			s.logger.Warn("Policy version creation rejected - policy owned by another provider",
+				zap.String("policyName", version.PolicyName),
+				zap.String("version", version.Version),
+				zap.String("provider", version.Provider))
+			return nil, appErr
+		}
 		s.logger.Error("Policy version creation failed - database error",
 			zap.String("policyName", version.PolicyName),
 			zap.String("version", version.Version),
@@ -213,19 +298,184 @@ func (s *Service) CreatePolicyVersion(ctx context.Context, version *PolicyVersio
 	s.logger.Info("Policy version created successfully",
 		zap.String("policyName", version.PolicyName),
 		zap.String("version", version.Version),
-		zap.Int32("id", created.ID))
+		zap.Int32("id", created.ID),
+		zap.Int("docs", len(docs)))
 
 	return created, nil
 }
 
-// UpsertPolicyDoc creates or updates a documentation page
-func (s *Service) UpsertPolicyDoc(ctx context.Context, doc *PolicyDoc) (*PolicyDoc, error) {
-	upserted, err := s.repo.UpsertPolicyDoc(ctx, doc)
+// CheckPolicyOwner returns a forbidden error when the policy already exists
+// under a provider other than the given one
+func (s *Service) CheckPolicyOwner(ctx context.Context, name, provider string) error {
+	owner, err := s.repo.GetPolicyProvider(ctx, name)
+	if err != nil {
+		s.logger.Error("Failed to get policy provider",
+			zap.String("policyName", name),
+			zap.Error(err))
+		return errs.SanitizeDatabaseError("get policy provider")
+	}
+	if owner != "" && owner != provider {
+		return errs.PolicyOwnedByOtherProvider(name, owner, provider)
+	}
+	return nil
+}
+
+// checkExistingDigests compares a rejected duplicate against the published version
+// and returns a digest mismatch error if their content differs
+func (s *Service) checkExistingDigests(ctx context.Context, version *PolicyVersion) *errs.AppError {
+	existing, err := s.repo.GetPolicyVersion(ctx, version.PolicyName, version.Version)
 	if err != nil {
-		return nil, errs.NewDatabaseError("Failed to upsert doc", map[string]any{"error": err.Error()})
+		return nil
 	}
 
-	return upserted, nil
+	details := map[string]any{}
+	if existing.DefinitionDigest != version.DefinitionDigest {
+		details["expectedDefinitionDigest"] = existing.DefinitionDigest
+		details["actualDefinitionDigest"] = version.DefinitionDigest
+	}
+	if existing.ArtifactDigest != nil && version.ArtifactDigest != nil && *existing.ArtifactDigest != *version.ArtifactDigest {
+		details["expectedArtifactDigest"] = *existing.ArtifactDigest
+		details["actualArtifactDigest"] = *version.ArtifactDigest
+	}
+	if len(details) == 0 {
+		return nil
+	}
+
+	s.logger.Warn("Policy version re-sync rejected - content digest mismatch",
+		zap.String("policyName", version.PolicyName),
+		zap.String("version", version.Version),
+		zap.Any("digests", details))
+	return errs.DigestMismatch(version.PolicyName, version.Version, details)
+}
+
+// DeprecatePolicyVersion marks a version as deprecated; it remains resolvable but consumers receive a warning
+func (s *Service) DeprecatePolicyVersion(ctx context.Context, name, version, reason, replacementVersion string) (*PolicyVersion, error) {
+	current, err := s.GetPolicyVersion(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if current.Status == VersionStatusYanked {
+		return nil, errs.NewConflictError(errs.CodeVersionStatusConflict, "Yanked policy versions cannot be deprecated", map[string]any{
+			"policyName": name,
+			"version":    version,
+		})
+	}
+
+	update := VersionStatusUpdate{Status: VersionStatusDeprecated}
+	if reason != "" {
+		update.Reason = &reason
+	}
+
+	if replacementVersion != "" {
+		if replacementVersion == version {
+			return nil, errs.NewValidationError("replacement version must differ from the deprecated version", map[string]any{
+				"policyName": name,
+				"version":    version,
+			})
+		}
+
+		replacement, err := s.repo.GetPolicyVersion(ctx, name, replacementVersion)
+		if err != nil {
+			return nil, errs.NewValidationError("replacement version does not exist", map[string]any{
+				"policyName":         name,
+				"replacementVersion": replacementVersion,
+			})
+		}
+		if replacement.Status == VersionStatusYanked {
+			return nil, errs.NewValidationError("replacement version has been yanked", map[string]any{
+				"policyName":         name,
+				"replacementVersion": replacementVersion,
+			})
+		}
+		update.ReplacementVersion = &replacementVersion
+	}
+
+	return s.updateVersionStatus(ctx, name, version, update)
+}
+
+// YankPolicyVersion retracts a version so it is excluded from listings and latest_* resolution
+func (s *Service) YankPolicyVersion(ctx context.Context, name, version, reason string) (*PolicyVersion, error) {
+	current, err := s.GetPolicyVersion(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if current.Status == VersionStatusYanked {
+		return nil, errs.NewConflictError(errs.CodeVersionStatusConflict, "Policy version is already yanked", map[string]any{
+			"policyName": name,
+			"version":    version,
+		})
+	}
+
+	update := VersionStatusUpdate{
+		Status:             VersionStatusYanked,
+		ReplacementVersion: current.ReplacementVersion,
+		PreviousStatus:     &current.Status,
+		PreviousReason:     current.StatusReason,
+	}
+	if reason != "" {
+		update.Reason = &reason
+	}
+
+	return s.updateVersionStatus(ctx, name, version, update)
+}
+
+// UnyankPolicyVersion restores a yanked version to the status, reason and
+// replacement it had before it was yanked
+func (s *Service) UnyankPolicyVersion(ctx context.Context, name, version string) (*PolicyVersion, error) {
+	current, err := s.GetPolicyVersion(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if current.Status != VersionStatusYanked {
+		return nil, errs.NewConflictError(errs.CodeVersionStatusConflict, "Policy version is not yanked", map[string]any{
+			"policyName": name,
+			"version":    version,
+			"status":     current.Status,
+		})
+	}
+
+	return s.updateVersionStatus(ctx, name, version, unyankUpdate(current))
+}
+
+// unyankUpdate restores the status a version had before it was yanked. Only
+// deprecations keep their reason and replacement; versions without a recorded
+// previous status become active.
+func unyankUpdate(current *PolicyVersion) VersionStatusUpdate {
+	if current.PreviousStatus == nil || *current.PreviousStatus != VersionStatusDeprecated {
+		return VersionStatusUpdate{Status: VersionStatusActive}
+	}
+	return VersionStatusUpdate{
+		Status:             VersionStatusDeprecated,
+		Reason:             current.PreviousStatusReason,
+		ReplacementVersion: current.ReplacementVersion,
+	}
+}
+
+// updateVersionStatus persists a lifecycle status change
+func (s *Service) updateVersionStatus(ctx context.Context, name, version string, update VersionStatusUpdate) (*PolicyVersion, error) {
+	updated, err := s.repo.UpdatePolicyVersionStatus(ctx, name, version, update)
+	if err != nil {
+		if appErr, ok := err.(*errs.AppError); ok && appErr.Code == errs.CodePolicyVersionNotFound {
+			return nil, errs.PolicyVersionNotFound(name, version)
+		}
+		s.logger.Error("Policy version status update failed - database error",
+			zap.String("policyName", name),
+			zap.String("version", version),
+			zap.String("status", string(update.Status)),
+			zap.Error(err))
+		return nil, errs.SanitizeDatabaseError("updating policy version status")
+	}
+
+	s.logger.Info("Policy version status updated",
+		zap.String("policyName", name),
+		zap.String("version", version),
+		zap.String("status", string(updated.Status)),
+		zap.Bool("isLatest", updated.IsLatest))
+
+	return updated, nil
 }
 
 // GetDistinctCategories retrieves all unique categories from policies
@@ -258,168 +508,227 @@ func (s *Service) GetDistinctPlatforms(ctx context.Context) ([]string, error) {
 	return platforms, nil
 }
 
-// ResolvePolicies retrieves multiple policies in a single request using bulk optimization
+// ResolvePolicies retrieves multiple policies in a single request using bulk optimization.
+// Every request is compiled to a version constraint and all of them are resolved in one query.
+// Items and errors carry the index of the request they answer and are returned in request order.
 func (s *Service) ResolvePolicies(ctx context.Context, requests []ResolvePolicyRequest) ([]PolicyResolveItem, []PolicyResolveError) {
-	// Group requests by strategy for bulk processing
-	strategyGroups := s.groupRequestsByStrategy(requests)
-
-	// Process each strategy group in parallel
-	resultsChan := make(chan []PolicyResolveItem, 4)
-	errorsChan := make(chan []PolicyResolveError, 4)
-
-	var wg sync.WaitGroup
-
-	// Process exact versions
-	if len(strategyGroups["exact"]) > 0 {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			results, errs := s.bulkGetExact(ctx, strategyGroups["exact"])
-			resultsChan <- results
-			errorsChan <- errs
-		}()
-	}
-
-	// Process latest patch versions
-	if len(strategyGroups["latest_patch"]) > 0 {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			results, errs := s.bulkGetLatestPatch(ctx, strategyGroups["latest_patch"])
-			resultsChan <- results
-			errorsChan <- errs
-		}()
-	}
-
-	// Process latest minor versions
-	if len(strategyGroups["latest_minor"]) > 0 {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			results, errs := s.bulkGetLatestMinor(ctx, strategyGroups["latest_minor"])
-			resultsChan <- results
-			errorsChan <- errs
-		}()
-	}
-
-	// Process latest major versions
-	if len(strategyGroups["latest_major"]) > 0 {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			results, errs := s.bulkGetLatestMajor(ctx, strategyGroups["latest_major"])
-			resultsChan <- results
-			errorsChan <- errs
-		}()
-	}
-
-	// Close channels after all goroutines complete
-	go func() {
-		wg.Wait()
-		close(resultsChan)
-		close(errorsChan)
-	}()
-
-	// Collect results
-	var allResults []PolicyResolveItem
+	var validRequests []indexedResolveRequest
+	var repoRequests []VersionRangeRequest
 	var allErrors []PolicyResolveError
 
-	for results := range resultsChan {
-		allResults = append(allResults, results...)
+	for i, req := range requests {
+		indexed := indexedResolveRequest{Index: i, ResolvePolicyRequest: req}
+
+		constraint, resolveErr := s.compileConstraint(indexed)
+		if resolveErr != nil {
+			allErrors = append(allErrors, *resolveErr)
+			continue
+		}
+
+		// Yanked versions stay reachable only by pinning them exactly
+		_, pinned := constraint.Exact()
+
+		validRequests = append(validRequests, indexed)
+		repoRequests = append(repoRequests, VersionRangeRequest{
+			Name:              req.Name,
+			Ranges:            constraint.Ranges(),
+			AllowYanked:       pinned,
+			IncludePrerelease: req.IncludePrerelease,
+			VerifiedOnly:      req.VerifiedOnly,
+		})
 	}
 
-	for errors := range errorsChan {
-		allErrors = append(allErrors, errors...)
+	if len(validRequests) == 0 {
+		return []PolicyResolveItem{}, allErrors
 	}
 
-	return allResults, allErrors
+	policyVersions, err := s.repo.BulkGetPolicyVersionsByRanges(ctx, repoRequests)
+	if err != nil {
+		allErrors = append(allErrors, s.bulkFetchFailed(validRequests, err)...)
+		sort.Slice(allErrors, func(i, j int) bool { return allErrors[i].Index < allErrors[j].Index })
+		return []PolicyResolveItem{}, allErrors
+	}
+
+	results, unmatched := s.matchResolveResults(validRequests, policyVersions)
+
+	// Tell "policy does not exist" apart from "no version matches"
+	allErrors = append(allErrors, s.classifyUnmatched(ctx, unmatched)...)
+	sort.Slice(allErrors, func(i, j int) bool { return allErrors[i].Index < allErrors[j].Index })
+
+	s.recordResolves(ctx, results)
+	return results, allErrors
 }
 
-// getPolicyByStrategy retrieves a policy version based on the specified strategy
-func (s *Service) getPolicyByStrategy(ctx context.Context, name, strategy, baseVersion string) (*PolicyVersion, error) {
-	switch strategy {
-	case "exact":
-		if baseVersion == "" {
-			return nil, errs.NewValidationError("baseVersion is required for exact strategy", map[string]any{
-				"policyName": name,
-				"strategy":   strategy,
-			})
-		}
-		return s.repo.GetPolicyVersionByExact(ctx, name, baseVersion)
+// LockPolicies resolves the requests like ResolvePolicies and pins every resolved
+// policy to its exact version and definition digest
+func (s *Service) LockPolicies(ctx context.Context, requests []ResolvePolicyRequest) ([]LockedPolicy, []PolicyResolveError) {
+	items, resolveErrors := s.ResolvePolicies(ctx, requests)
+
+	locked := make([]LockedPolicy, 0, len(items))
+	for _, item := range items {
+		locked = append(locked, LockedPolicy{
+			Index:       item.Index,
+			Name:        item.Name,
+			Version:     item.Version,
+			Digest:      item.Metadata.DefinitionDigest,
+			DownloadURL: item.SourceURL,
+		})
+	}
 
-	case "latest_patch":
-		if baseVersion == "" {
-			return nil, errs.NewValidationError("baseVersion is required for latest_patch strategy", map[string]any{
-				"policyName": name,
-				"strategy":   strategy,
-			})
+	return locked, resolveErrors
+}
+
+// VerifyLockfile checks every locked policy against the hub and reports whether
+// its version still exists, is still resolvable and still has the locked digest
+func (s *Service) VerifyLockfile(ctx context.Context, locked []LockedPolicy) ([]LockVerification, error) {
+	results := make([]LockVerification, len(locked))
+	requests := make([]VersionRangeRequest, len(locked))
+	lockedVersions := make([]semver.Version, len(locked))
+
+	for i, entry := range locked {
+		results[i] = LockVerification{
+			Index:          i,
+			Name:           entry.Name,
+			Version:        entry.Version,
+			ExpectedDigest: entry.Digest,
 		}
-		major, minor, err := s.parseMajorMinor(baseVersion)
+
+		version, err := semver.Parse(entry.Version)
 		if err != nil {
-			return nil, errs.NewValidationError("invalid baseVersion format for latest_patch", map[string]any{
-				"policyName":  name,
-				"baseVersion": baseVersion,
-				"error":       err.Error(),
-			})
+			// A request without ranges matches nothing and is reported as not found below
+			requests[i] = VersionRangeRequest{Name: entry.Name}
+			continue
 		}
-		return s.repo.GetPolicyVersionByLatestPatch(ctx, name, major, minor)
+		lockedVersions[i] = version
+		pin := semver.Bound{Version: version, Inclusive: true}
+		requests[i] = VersionRangeRequest{
+			Name:              entry.Name,
+			Ranges:            []semver.Range{{Lower: &pin, Upper: &pin}},
+			AllowYanked:       true,
+			IncludePrerelease: true,
+		}
+	}
 
-	case "latest_minor":
-		if baseVersion == "" {
-			return nil, errs.NewValidationError("baseVersion is required for latest_minor strategy", map[string]any{
-				"policyName": name,
-				"strategy":   strategy,
-			})
+	policyVersions, err := s.repo.BulkGetPolicyVersionsByRanges(ctx, requests)
+	if err != nil {
+		s.logger.Error("Lockfile verification failed - database error",
+			zap.Int("count", len(locked)),
+			zap.Error(err))
+		return nil, errs.SanitizeDatabaseError("verifying lockfile")
+	}
+
+	for i, pv := range policyVersions {
+		result := &results[i]
+
+		var actual semver.Version
+		var parseErr error
+		if pv != nil {
+			actual, parseErr = semver.Parse(pv.Version)
 		}
-		major, err := s.parseMajor(baseVersion)
-		if err != nil {
-			return nil, errs.NewValidationError("invalid baseVersion format for latest_minor", map[string]any{
-				"policyName":  name,
-				"baseVersion": baseVersion,
-				"error":       err.Error(),
-			})
+		if pv == nil || parseErr != nil || actual.Compare(lockedVersions[i]) != 0 {
+			result.Status = LockStatusVersionNotFound
+			result.Message = fmt.Sprintf("Policy %s version %s does not exist", result.Name, result.Version)
+			continue
 		}
-		return s.repo.GetPolicyVersionByLatestMinor(ctx, name, major)
-
-	case "latest_major":
-		return s.repo.GetPolicyVersionByLatestMajor(ctx, name)
 
-	default:
-		return nil, errs.NewValidationError("invalid retrieval strategy", map[string]any{
-			"policyName": name,
-			"strategy":   strategy,
-		})
+		// Ranges ignore build metadata, so the hub may serve another build of the locked version
+		result.ActualDigest = pv.DefinitionDigest
+		switch {
+		case actual.Build != lockedVersions[i].Build:
+			result.Status = LockStatusBuildMismatch
+			result.ActualVersion = pv.Version
+			result.Message = fmt.Sprintf("Policy %s version %s is published as %s", result.Name, result.Version, pv.Version)
+		case result.ActualDigest != result.ExpectedDigest:
+			result.Status = LockStatusDigestMismatch
+			result.Message = fmt.Sprintf("Definition of policy %s version %s does not match the locked digest", result.Name, result.Version)
+		case pv.Status == VersionStatusYanked:
+			result.Status = LockStatusYanked
+			result.Message = pv.StatusWarning()
+		case pv.Status == VersionStatusDeprecated:
+			result.Status = LockStatusDeprecated
+			result.Message = pv.StatusWarning()
+		default:
+			result.Status = LockStatusOK
+		}
 	}
-}
 
-// parseMajorMinor parses a version string like "1.2" into major and minor integers
-func (s *Service) parseMajorMinor(version string) (int32, int32, error) {
-	parts := strings.Split(version, ".")
-	if len(parts) != 2 {
-		return 0, 0, fmt.Errorf("version must be in format 'major.minor', got: %s", version)
-	}
+	return results, nil
+}
 
-	major, err := strconv.Atoi(parts[0])
-	if err != nil {
-		return 0, 0, fmt.Errorf("invalid major version: %s", parts[0])
+// compileConstraint parses the request's constraint, or compiles its retrieval
+// strategy into the equivalent constraint. Exactly one of the two must be set.
+func (s *Service) compileConstraint(req indexedResolveRequest) (*semver.Constraint, *PolicyResolveError) {
+	// A blank constraint would otherwise parse as "*" and match any version
+	expression := strings.TrimSpace(req.Constraint)
+	switch {
+	case req.Constraint != "" && expression == "":
+		resolveErr := resolveError(req, ResolveErrorInvalidConstraint, "Constraint must not be blank")
+		return nil, &resolveErr
+	case req.Constraint != "" && req.RetrievalStrategy != "":
+		resolveErr := resolveError(req, ResolveErrorInvalidConstraint, "Specify either constraint or retrievalStrategy, not both")
+		return nil, &resolveErr
+	case req.Constraint == "" && req.RetrievalStrategy == "":
+		resolveErr := resolveError(req, ResolveErrorInvalidConstraint, "Either constraint or retrievalStrategy is required")
+		return nil, &resolveErr
+	}
+
+	if req.RetrievalStrategy != "" {
+		var resolveErr *PolicyResolveError
+		expression, resolveErr = strategyConstraint(req)
+		if resolveErr != nil {
+			return nil, resolveErr
+		}
 	}
 
-	minor, err := strconv.Atoi(parts[1])
+	constraint, err := semver.ParseConstraint(expression)
 	if err != nil {
-		return 0, 0, fmt.Errorf("invalid minor version: %s", parts[1])
+		resolveErr := resolveError(req, ResolveErrorInvalidConstraint, fmt.Sprintf("Invalid constraint: %s", err.Error()))
+		return nil, &resolveErr
 	}
 
-	return int32(major), int32(minor), nil
+	return constraint, nil
 }
 
-// parseMajor parses a version string like "1" into major integer
-func (s *Service) parseMajor(version string) (int32, error) {
-	major, err := strconv.Atoi(version)
-	if err != nil {
-		return 0, fmt.Errorf("invalid major version: %s", version)
+// strategyConstraint compiles a retrieval strategy and its base version into a constraint:
+//
+//	exact        1.2.3  ->  =1.2.3
+//	latest_patch 1.2    ->  ~1.2
+//	latest_minor 1      ->  1.x
+//	latest_major        ->  *
+func strategyConstraint(req indexedResolveRequest) (string, *PolicyResolveError) {
+	invalidBase := func(format string) (string, *PolicyResolveError) {
+		resolveErr := resolveError(req, ResolveErrorInvalidBaseVersion,
+			fmt.Sprintf("baseVersion must be in format '%s' for %s strategy, got: %q", format, req.RetrievalStrategy, req.BaseVersion))
+		return "", &resolveErr
 	}
 
-	return int32(major), nil
+	switch req.RetrievalStrategy {
+	case "exact":
+		if !exactBaseVersion.MatchString(req.BaseVersion) {
+			return invalidBase("major.minor.patch")
+		}
+		return "=" + req.BaseVersion, nil
+
+	case "latest_patch":
+		if !majorMinorBaseVersion.MatchString(req.BaseVersion) {
+			return invalidBase("major.minor")
+		}
+		return "~" + req.BaseVersion, nil
+
+	case "latest_minor":
+		if !majorBaseVersion.MatchString(req.BaseVersion) {
+			return invalidBase("major")
+		}
+		return req.BaseVersion + ".x", nil
+
+	case "latest_major":
+		return "*", nil
+
+	default:
+		resolveErr := resolveError(req, ResolveErrorInvalidStrategy,
+			fmt.Sprintf("Unknown retrieval strategy: %s", req.RetrievalStrategy))
+		return "", &resolveErr
+	}
 }
 
 // validateAssetURLs validates that asset URLs are properly formatted
@@ -442,197 +751,127 @@ func (s *Service) validateAssetURLs(version *PolicyVersion) error {
 	return nil
 }
 
-// groupRequestsByStrategy groups batch requests by their retrieval strategy
-func (s *Service) groupRequestsByStrategy(requests []ResolvePolicyRequest) map[string][]ResolvePolicyRequest {
-	groups := map[string][]ResolvePolicyRequest{
-		"exact":        {},
-		"latest_patch": {},
-		"latest_minor": {},
-		"latest_major": {},
-	}
-
-	for _, req := range requests {
-		if _, exists := groups[req.RetrievalStrategy]; exists {
-			groups[req.RetrievalStrategy] = append(groups[req.RetrievalStrategy], req)
-		}
-	}
-
-	return groups
+// indexedResolveRequest is a resolve request tagged with its position in the batch
+type indexedResolveRequest struct {
+	Index int
+	ResolvePolicyRequest
 }
 
-// bulkGetExact processes exact version requests in bulk
-func (s *Service) bulkGetExact(ctx context.Context, requests []ResolvePolicyRequest) ([]PolicyResolveItem, []PolicyResolveError) {
-	// Convert to repository format
-	repoRequests := make([]ExactVersionRequest, 0, len(requests))
-	for _, req := range requests {
-		repoRequests = append(repoRequests, ExactVersionRequest{
-			Name:    req.Name,
-			Version: req.BaseVersion,
-		})
+// describeConstraint names what the request asked for, for use in error messages
+func (req indexedResolveRequest) describeConstraint() string {
+	description := fmt.Sprintf("strategy %s", req.RetrievalStrategy)
+	if req.Constraint != "" {
+		description = fmt.Sprintf("constraint %q", req.Constraint)
 	}
-
-	// Bulk fetch from database
-	policyVersions, err := s.repo.BulkGetPolicyVersionsByExact(ctx, repoRequests)
-	if err != nil {
-		// Convert to errors for all requests
-		errors := make([]PolicyResolveError, 0, len(requests))
-		for _, req := range requests {
-			errors = append(errors, PolicyResolveError{
-				Name:    req.Name,
-				Version: req.BaseVersion,
-				Error:   "Failed to fetch policy version",
-			})
-		}
-		return []PolicyResolveItem{}, errors
+	if req.VerifiedOnly {
+		description += " with a verified signature"
 	}
-
-	return s.convertToResolveItems(policyVersions), []PolicyResolveError{}
+	return description
 }
 
-// bulkGetLatestPatch processes latest patch version requests in bulk
-func (s *Service) bulkGetLatestPatch(ctx context.Context, requests []ResolvePolicyRequest) ([]PolicyResolveItem, []PolicyResolveError) {
-	var validRequests []PatchVersionRequest
-	var errors []PolicyResolveError
-
-	// Parse base versions and validate
-	for _, req := range requests {
-		major, minor, err := s.parseMajorMinor(req.BaseVersion)
-		if err != nil {
-			errors = append(errors, PolicyResolveError{
-				Name:    req.Name,
-				Version: req.BaseVersion,
-				Error:   fmt.Sprintf("Invalid baseVersion format: %s", err.Error()),
-			})
-			continue
-		}
-
-		validRequests = append(validRequests, PatchVersionRequest{
-			Name:         req.Name,
-			MajorVersion: major,
-			MinorVersion: minor,
-		})
+// resolveError builds a per-item resolve error for a request
+func resolveError(req indexedResolveRequest, code ResolveErrorCode, message string) PolicyResolveError {
+	return PolicyResolveError{
+		Index:       req.Index,
+		Name:        req.Name,
+		Strategy:    req.RetrievalStrategy,
+		BaseVersion: req.BaseVersion,
+		Constraint:  req.Constraint,
+		Code:        code,
+		Error:       message,
 	}
+}
 
-	if len(validRequests) == 0 {
-		return []PolicyResolveItem{}, errors
-	}
+// matchResolveResults pairs positional bulk results with their requests and
+// returns the requests that had no matching version
+func (s *Service) matchResolveResults(requests []indexedResolveRequest, policyVersions []*PolicyVersion) ([]PolicyResolveItem, []indexedResolveRequest) {
+	results := make([]PolicyResolveItem, 0, len(requests))
+	var unmatched []indexedResolveRequest
 
-	// Bulk fetch from database
-	policyVersions, err := s.repo.BulkGetPolicyVersionsByLatestPatch(ctx, validRequests)
-	if err != nil {
-		// Add database errors for valid requests
-		for _, req := range validRequests {
-			errors = append(errors, PolicyResolveError{
-				Name:    req.Name,
-				Version: fmt.Sprintf("%d.%d", req.MajorVersion, req.MinorVersion),
-				Error:   "Failed to fetch latest patch version",
-			})
+	for i, req := range requests {
+		if i >= len(policyVersions) || policyVersions[i] == nil {
+			unmatched = append(unmatched, req)
+			continue
 		}
-		return []PolicyResolveItem{}, errors
+		results = append(results, s.convertToResolveItem(req.Index, policyVersions[i]))
 	}
 
-	return s.convertToResolveItems(policyVersions), errors
+	return results, unmatched
 }
 
-// bulkGetLatestMinor processes latest minor version requests in bulk
-func (s *Service) bulkGetLatestMinor(ctx context.Context, requests []ResolvePolicyRequest) ([]PolicyResolveItem, []PolicyResolveError) {
-	var validRequests []MinorVersionRequest
-	var errors []PolicyResolveError
+// bulkFetchFailed reports a database failure for every request in a bulk fetch
+func (s *Service) bulkFetchFailed(requests []indexedResolveRequest, err error) []PolicyResolveError {
+	s.logger.Error("Bulk policy resolve failed - database error",
+		zap.Int("count", len(requests)),
+		zap.Error(err))
 
-	// Parse base versions and validate
+	errors := make([]PolicyResolveError, 0, len(requests))
 	for _, req := range requests {
-		major, err := s.parseMajor(req.BaseVersion)
-		if err != nil {
-			errors = append(errors, PolicyResolveError{
-				Name:    req.Name,
-				Version: req.BaseVersion,
-				Error:   fmt.Sprintf("Invalid baseVersion format: %s", err.Error()),
-			})
-			continue
-		}
+		errors = append(errors, resolveError(req, ResolveErrorInternal, "Failed to fetch policy version"))
+	}
+	return errors
+}
 
-		validRequests = append(validRequests, MinorVersionRequest{
-			Name:         req.Name,
-			MajorVersion: major,
-		})
+// classifyUnmatched reports unmatched requests as POLICY_NOT_FOUND when no
+// version of the policy exists, and NO_MATCHING_VERSION otherwise
+func (s *Service) classifyUnmatched(ctx context.Context, unmatched []indexedResolveRequest) []PolicyResolveError {
+	if len(unmatched) == 0 {
+		return nil
 	}
 
-	if len(validRequests) == 0 {
-		return []PolicyResolveItem{}, errors
+	names := make([]string, 0, len(unmatched))
+	for _, req := range unmatched {
+		names = append(names, req.Name)
 	}
 
-	// Bulk fetch from database
-	policyVersions, err := s.repo.BulkGetPolicyVersionsByLatestMinor(ctx, validRequests)
+	existing, err := s.repo.GetExistingPolicyNames(ctx, names)
 	if err != nil {
-		// Add database errors for valid requests
-		for _, req := range validRequests {
-			errors = append(errors, PolicyResolveError{
-				Name:    req.Name,
-				Version: fmt.Sprintf("%d", req.MajorVersion),
-				Error:   "Failed to fetch latest minor version",
-			})
-		}
-		return []PolicyResolveItem{}, errors
+		return s.bulkFetchFailed(unmatched, err)
 	}
 
-	return s.convertToResolveItems(policyVersions), errors
-}
-
-// bulkGetLatestMajor processes latest major version requests in bulk
-func (s *Service) bulkGetLatestMajor(ctx context.Context, requests []ResolvePolicyRequest) ([]PolicyResolveItem, []PolicyResolveError) {
-	// Extract policy names
-	policyNames := make([]string, 0, len(requests))
-	for _, req := range requests {
-		policyNames = append(policyNames, req.Name)
+	existingSet := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		existingSet[name] = true
 	}
 
-	// Bulk fetch from database
-	policyVersions, err := s.repo.BulkGetPolicyVersionsByLatestMajor(ctx, policyNames)
-	if err != nil {
-		// Convert to errors for all requests
-		errors := make([]PolicyResolveError, 0, len(requests))
-		for _, req := range requests {
-			errors = append(errors, PolicyResolveError{
-				Name:    req.Name,
-				Version: "",
-				Error:   "Failed to fetch latest major version",
-			})
+	errors := make([]PolicyResolveError, 0, len(unmatched))
+	for _, req := range unmatched {
+		if !existingSet[req.Name] {
+			errors = append(errors, resolveError(req, ResolveErrorPolicyNotFound,
+				fmt.Sprintf("Policy %s does not exist", req.Name)))
+			continue
 		}
-		return []PolicyResolveItem{}, errors
+		errors = append(errors, resolveError(req, ResolveErrorNoMatchingVersion,
+			fmt.Sprintf("No version of policy %s matches %s", req.Name, req.describeConstraint())))
 	}
 
-	return s.convertToResolveItems(policyVersions), []PolicyResolveError{}
+	return errors
 }
 
-// convertToResolveItems converts PolicyVersion slice to PolicyResolveItem slice
-func (s *Service) convertToResolveItems(policyVersions []*PolicyVersion) []PolicyResolveItem {
-	items := make([]PolicyResolveItem, 0, len(policyVersions))
-
-	for _, pv := range policyVersions {
-		// Use the raw YAML definition for consistency with other endpoints
-		definition := map[string]interface{}{
-			"yaml": pv.DefinitionYAML,
-		}
-
-		// Determine source type and URL
-		sourceType := "filesystem"
-		sourceURL := ""
-		if pv.SourceType != nil {
-			sourceType = *pv.SourceType
-		}
-		if pv.SourceURL != nil {
-			sourceURL = *pv.SourceURL
-		}
+// convertToResolveItem converts a PolicyVersion into the PolicyResolveItem answering request index
+func (s *Service) convertToResolveItem(index int, pv *PolicyVersion) PolicyResolveItem {
+	// Use the raw YAML definition for consistency with other endpoints
+	definition := map[string]interface{}{
+		"yaml": pv.DefinitionYAML,
+	}
 
-		items = append(items, PolicyResolveItem{
-			Name:       pv.PolicyName,
-			Version:    pv.Version,
-			SourceType: sourceType,
-			SourceURL:  sourceURL,
-			Definition: definition,
-			Metadata:   pv,
-		})
+	// Determine source type and URL
+	sourceType := "filesystem"
+	sourceURL := ""
+	if pv.SourceType != nil {
+		sourceType = *pv.SourceType
+	}
+	if pv.SourceURL != nil {
+		sourceURL = *pv.SourceURL
 	}
 
-	return items
+	return PolicyResolveItem{
+		Index:      index,
+		Name:       pv.PolicyName,
+		Version:    pv.Version,
+		SourceType: sourceType,
+		SourceURL:  sourceURL,
+		Definition: definition,
+		Metadata:   pv,
+	}
 }
@@ -0,0 +1,268 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package policy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/wso2/policyhub/internal/semver"
+)
+
+func TestCompileConstraint(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      ResolvePolicyRequest
+		want     string // compiled constraint, "" when an error is expected
+		wantCode ResolveErrorCode
+		wantErr  string
+	}{
+		{name: "constraint", req: ResolvePolicyRequest{Constraint: "^1.2.0"}, want: "^1.2.0"},
+		{name: "padded constraint", req: ResolvePolicyRequest{Constraint: "  ~1.4 \t"}, want: "~1.4"},
+		{name: "exact strategy", req: ResolvePolicyRequest{RetrievalStrategy: "exact", BaseVersion: "1.2.3"}, want: "=1.2.3"},
+		{name: "latest patch strategy", req: ResolvePolicyRequest{RetrievalStrategy: "latest_patch", BaseVersion: "1.2"}, want: "~1.2"},
+		{name: "latest major strategy", req: ResolvePolicyRequest{RetrievalStrategy: "latest_major"}, want: "*"},
+		{name: "blank constraint", req: ResolvePolicyRequest{Constraint: "   "}, wantCode: ResolveErrorInvalidConstraint, wantErr: "must not be blank"},
+		{name: "whitespace constraint", req: ResolvePolicyRequest{Constraint: "\t\n"}, wantCode: ResolveErrorInvalidConstraint, wantErr: "must not be blank"},
+		{name: "blank constraint with strategy", req: ResolvePolicyRequest{Constraint: " ", RetrievalStrategy: "latest_major"}, wantCode: ResolveErrorInvalidConstraint, wantErr: "must not be blank"},
+		{name: "constraint and strategy", req: ResolvePolicyRequest{Constraint: "^1.0.0", RetrievalStrategy: "latest_major"}, wantCode: ResolveErrorInvalidConstraint, wantErr: "not both"},
+		{name: "neither", req: ResolvePolicyRequest{}, wantCode: ResolveErrorInvalidConstraint, wantErr: "required"},
+		{name: "invalid constraint", req: ResolvePolicyRequest{Constraint: "^1.x.bad"}, wantCode: ResolveErrorInvalidConstraint, wantErr: "Invalid constraint"},
+		{name: "invalid base version", req: ResolvePolicyRequest{RetrievalStrategy: "latest_patch", BaseVersion: "1"}, wantCode: ResolveErrorInvalidBaseVersion},
+	}
+
+	s := &Service{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.Name = "rate-limit"
+			constraint, resolveErr := s.compileConstraint(indexedResolveRequest{Index: 3, ResolvePolicyRequest: tt.req})
+			if tt.wantCode == "" {
+				if resolveErr != nil {
+					t.Fatalf("compileConstraint() error = %+v", resolveErr)
+				}
+				if got := constraint.String(); got != tt.want {
+					t.Errorf("constraint = %q, want %q", got, tt.want)
+				}
+				return
+			}
+			if resolveErr == nil {
+				t.Fatalf("compileConstraint() = %v, want %s", constraint, tt.wantCode)
+			}
+			if resolveErr.Code != tt.wantCode || resolveErr.Index != 3 || resolveErr.Name != "rate-limit" {
+				t.Errorf("error = %+v, want code %s for request 3", resolveErr, tt.wantCode)
+			}
+			if !strings.Contains(resolveErr.Error, tt.wantErr) {
+				t.Errorf("error message = %q, want it to contain %q", resolveErr.Error, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUnyankUpdate(t *testing.T) {
+	ptr := func(s string) *string { return &s }
+	deprecated, active := VersionStatusDeprecated, VersionStatusActive
+
+	tests := []struct {
+		name    string
+		current PolicyVersion
+		want    VersionStatusUpdate
+	}{
+		{
+			name:    "was active",
+			current: PolicyVersion{PreviousStatus: &active, StatusReason: ptr("broken build")},
+			want:    VersionStatusUpdate{Status: VersionStatusActive},
+		},
+		{
+			name: "was deprecated",
+			current: PolicyVersion{
+				PreviousStatus:       &deprecated,
+				PreviousStatusReason: ptr("use 2.x"),
+				StatusReason:         ptr("broken build"),
+				ReplacementVersion:   ptr("2.0.0"),
+			},
+			want: VersionStatusUpdate{Status: VersionStatusDeprecated, Reason: ptr("use 2.x"), ReplacementVersion: ptr("2.0.0")},
+		},
+		{
+			name:    "no previous status",
+			current: PolicyVersion{StatusReason: ptr("broken build")},
+			want:    VersionStatusUpdate{Status: VersionStatusActive},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.current.Status = VersionStatusYanked
+			got := unyankUpdate(&tt.current)
+			if got.Status != tt.want.Status || !equalPtr(got.Reason, tt.want.Reason) ||
+				!equalPtr(got.ReplacementVersion, tt.want.ReplacementVersion) || got.PreviousStatus != nil {
+				t.Errorf("unyankUpdate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func equalPtr(a, b *string) bool {
+	return (a == nil) == (b == nil) && (a == nil || *a == *b)
+}
+
+// rangeRepository serves BulkGetPolicyVersionsByRanges from one version per policy
+type rangeRepository struct {
+	Repository
+	versions map[string]*PolicyVersion
+}
+
+func (r *rangeRepository) BulkGetPolicyVersionsByRanges(_ context.Context, requests []VersionRangeRequest) ([]*PolicyVersion, error) {
+	results := make([]*PolicyVersion, len(requests))
+	for i, req := range requests {
+		pv, ok := r.versions[req.Name]
+		if !ok || len(req.Ranges) == 0 {
+			continue
+		}
+		if v, err := semver.Parse(pv.Version); err == nil && req.Ranges[0].Contains(v) {
+			results[i] = pv
+		}
+	}
+	return results, nil
+}
+
+func (r *rangeRepository) GetExistingPolicyNames(_ context.Context, names []string) ([]string, error) {
+	var existing []string
+	for _, name := range names {
+		if _, ok := r.versions[name]; ok {
+			existing = append(existing, name)
+		}
+	}
+	return existing, nil
+}
+
+func (r *rangeRepository) IncrementResolves(context.Context, []string) error {
+	return nil
+}
+
+func TestResolvePoliciesIndex(t *testing.T) {
+	repo := &rangeRepository{versions: map[string]*PolicyVersion{
+		"rate-limit": {PolicyName: "rate-limit", Version: "1.2.0", Status: VersionStatusActive},
+	}}
+	s := NewService(repo, nil)
+
+	// The same policy is requested twice; the failures in between shift positions in the results
+	items, resolveErrors := s.ResolvePolicies(context.Background(), []ResolvePolicyRequest{
+		{Name: "rate-limit", Constraint: "^1.0.0"},
+		{Name: "unknown", Constraint: "^1.0.0"},
+		{Name: "rate-limit", Constraint: "^2.0.0"},
+		{Name: "rate-limit", Constraint: "not a constraint"},
+		{Name: "rate-limit", Constraint: "~1.2"},
+	})
+
+	var itemIndexes, errorIndexes []int
+	for _, item := range items {
+		itemIndexes = append(itemIndexes, item.Index)
+	}
+	for _, resolveErr := range resolveErrors {
+		errorIndexes = append(errorIndexes, resolveErr.Index)
+	}
+	if fmt.Sprint(itemIndexes) != "[0 4]" || fmt.Sprint(errorIndexes) != "[1 2 3]" {
+		t.Errorf("item indexes = %v, error indexes = %v; want [0 4] and [1 2 3]", itemIndexes, errorIndexes)
+	}
+}
+
+func TestVerifyLockfile(t *testing.T) {
+	digest := ComputeDigest([]byte("definition"))
+	repo := &rangeRepository{versions: map[string]*PolicyVersion{
+		"rate-limit": {PolicyName: "rate-limit", Version: "1.2.0+b2", DefinitionDigest: digest, Status: VersionStatusActive},
+		"cors":       {PolicyName: "cors", Version: "2.0.0", DefinitionDigest: digest, Status: VersionStatusYanked},
+		"jwt":        {PolicyName: "jwt", Version: "1.0.0", DefinitionDigest: digest, Status: VersionStatusDeprecated},
+	}}
+	s := NewService(repo, nil)
+
+	tests := []struct {
+		name          string
+		entry         LockedPolicy
+		want          LockStatus
+		actualVersion string
+	}{
+		{name: "same build", entry: LockedPolicy{Name: "rate-limit", Version: "1.2.0+b2", Digest: digest}, want: LockStatusOK},
+		{name: "other build", entry: LockedPolicy{Name: "rate-limit", Version: "1.2.0+b1", Digest: digest}, want: LockStatusBuildMismatch, actualVersion: "1.2.0+b2"},
+		{name: "no build", entry: LockedPolicy{Name: "rate-limit", Version: "1.2.0", Digest: digest}, want: LockStatusBuildMismatch, actualVersion: "1.2.0+b2"},
+		{name: "other build and digest", entry: LockedPolicy{Name: "rate-limit", Version: "1.2.0+b1", Digest: "sha256:00"}, want: LockStatusBuildMismatch, actualVersion: "1.2.0+b2"},
+		{name: "digest mismatch", entry: LockedPolicy{Name: "rate-limit", Version: "1.2.0+b2", Digest: "sha256:00"}, want: LockStatusDigestMismatch},
+		{name: "other version", entry: LockedPolicy{Name: "rate-limit", Version: "1.2.1", Digest: digest}, want: LockStatusVersionNotFound},
+		{name: "invalid version", entry: LockedPolicy{Name: "rate-limit", Version: "1.2", Digest: digest}, want: LockStatusVersionNotFound},
+		{name: "unknown policy", entry: LockedPolicy{Name: "unknown", Version: "1.0.0", Digest: digest}, want: LockStatusVersionNotFound},
+		{name: "yanked", entry: LockedPolicy{Name: "cors", Version: "2.0.0", Digest: digest}, want: LockStatusYanked},
+		{name: "deprecated", entry: LockedPolicy{Name: "jwt", Version: "1.0.0", Digest: digest}, want: LockStatusDeprecated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := s.VerifyLockfile(context.Background(), []LockedPolicy{tt.entry})
+			if err != nil {
+				t.Fatalf("VerifyLockfile() error = %v", err)
+			}
+			result := results[0]
+			if result.Status != tt.want || result.ActualVersion != tt.actualVersion {
+				t.Errorf("status = %s (actual version %q), want %s (%q); message %q",
+					result.Status, result.ActualVersion, tt.want, tt.actualVersion, result.Message)
+			}
+			if drifted := result.Drifted(); drifted != (tt.want != LockStatusOK && tt.want != LockStatusDeprecated) {
+				t.Errorf("Drifted() = %v for %s", drifted, result.Status)
+			}
+		})
+	}
+}
+
+// listingRepository serves ListPolicies from a fixed page
+type listingRepository struct {
+	Repository
+	page   *PolicyPage
+	facets []Facet
+}
+
+func (r *listingRepository) ListPolicies(_ context.Context, _ PolicyFilters, facets []Facet, _ *Keyset, _, _ int) (*PolicyPage, error) {
+	r.facets = facets
+	return r.page, nil
+}
+
+func TestListPoliciesCounts(t *testing.T) {
+	providers := make([]FacetCount, MaxFacetValues+5)
+	for i := range providers {
+		providers[i] = FacetCount{Value: fmt.Sprintf("provider-%d", i), Count: len(providers) - i}
+	}
+	repo := &listingRepository{page: &PolicyPage{
+		Policies: []*PolicyVersion{{PolicyName: "rate-limit", Version: "1.0.0"}},
+		Total:    45,
+		Facets:   FacetCounts{FacetProvider: providers, FacetTag: {}},
+	}}
+	s := NewService(repo, nil)
+
+	filters := PolicyFilters{PageRequest: PageRequest{PageSize: 20, IncludeTotal: true}}
+	policies, pagination, counts, err := s.ListPolicies(context.Background(), filters, []Facet{FacetProvider, FacetTag})
+	if err != nil {
+		t.Fatalf("ListPolicies() error = %v", err)
+	}
+	if len(policies) != 1 || len(repo.facets) != 2 {
+		t.Errorf("ListPolicies() = %d policies after asking for %v facets", len(policies), repo.facets)
+	}
+	if pagination.TotalItems == nil || *pagination.TotalItems != 45 || *pagination.TotalPages != 3 {
+		t.Errorf("totals = %v items, %v pages; want 45 and 3", pagination.TotalItems, pagination.TotalPages)
+	}
+	if got := counts[FacetProvider]; len(got) != MaxFacetValues || got[0].Value != "provider-0" {
+		t.Errorf("provider counts = %d values starting with %v, want the %d most common", len(got), got[0], MaxFacetValues)
+	}
+	if got, ok := counts[FacetTag]; !ok || len(got) != 0 {
+		t.Errorf("tag counts = %v, want an empty list", got)
+	}
+
+	// Without IncludeTotal the total is left unset
+	filters.IncludeTotal = false
+	if _, pagination, _, _ := s.ListPolicies(context.Background(), filters, nil); pagination.TotalItems != nil {
+		t.Errorf("TotalItems = %d without IncludeTotal", *pagination.TotalItems)
+	}
+}
@@ -0,0 +1,375 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package semver
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MaxConstraintLength bounds the size of a constraint expression
+const MaxConstraintLength = 256
+
+// operatorSpacing matches an operator followed by whitespace, as in ">= 1.2.0"
+var operatorSpacing = regexp.MustCompile(`(>=|<=|>|<|=|\^|~)\s+`)
+
+// Bound is one end of a version range
+type Bound struct {
+	Version   Version
+	Inclusive bool
+}
+
+// Range is a contiguous interval of versions. A nil bound is unbounded.
+type Range struct {
+	Lower *Bound
+	Upper *Bound
+}
+
+// Contains reports whether v lies within the range, pre-releases included
+func (r Range) Contains(v Version) bool {
+	if r.Lower != nil {
+		cmp := v.Compare(r.Lower.Version)
+		if cmp < 0 || (cmp == 0 && !r.Lower.Inclusive) {
+			return false
+		}
+	}
+	if r.Upper != nil {
+		cmp := v.Compare(r.Upper.Version)
+		if cmp > 0 || (cmp == 0 && !r.Upper.Inclusive) {
+			return false
+		}
+	}
+	return true
+}
+
+// AdmitsPrerelease reports whether a pre-release v may match the range without
+// opting in to pre-releases: as in npm, one of the bounds must itself be a
+// pre-release of the same major.minor.patch
+func (r Range) AdmitsPrerelease(v Version) bool {
+	for _, b := range []*Bound{r.Lower, r.Upper} {
+		if b != nil && b.Version.IsPrerelease() &&
+			b.Version.Major == v.Major && b.Version.Minor == v.Minor && b.Version.Patch == v.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmpty reports whether no version can satisfy the range
+func (r Range) isEmpty() bool {
+	if r.Lower == nil || r.Upper == nil {
+		return false
+	}
+	cmp := r.Lower.Version.Compare(r.Upper.Version)
+	return cmp > 0 || (cmp == 0 && !(r.Lower.Inclusive && r.Upper.Inclusive))
+}
+
+// intersect narrows the range to the overlap with another range
+func (r Range) intersect(o Range) Range {
+	if o.Lower != nil {
+		if r.Lower == nil {
+			r.Lower = o.Lower
+		} else if cmp := o.Lower.Version.Compare(r.Lower.Version); cmp > 0 || (cmp == 0 && !o.Lower.Inclusive) {
+			r.Lower = o.Lower
+		}
+	}
+	if o.Upper != nil {
+		if r.Upper == nil {
+			r.Upper = o.Upper
+		} else if cmp := o.Upper.Version.Compare(r.Upper.Version); cmp < 0 || (cmp == 0 && !o.Upper.Inclusive) {
+			r.Upper = o.Upper
+		}
+	}
+	return r
+}
+
+// Constraint is a parsed version range expression: a union of ranges
+type Constraint struct {
+	raw    string
+	ranges []Range
+}
+
+// ParseConstraint parses an npm/Cargo-style range expression. Supported forms:
+//
+//	1.2.3, =1.2.3          exact version (pre-release allowed, build metadata ignored)
+//	^1.2.3, ^0.2, ^1       compatible with (no change in the left-most non-zero part)
+//	~1.2.3, ~1.2, ~1       patch-level changes (minor-level for a bare major)
+//	>1.0, >=1.0.0, <2, <=1.4.x
+//	1.x, 1.2.*, *          wildcards
+//	1.2.3 - 2.0            hyphen range (inclusive)
+//
+// Space or comma separated comparators must all match; "||" separates alternatives.
+// Upper bounds derived from partial versions exclude the next version's
+// pre-releases, so "^1.2.0" does not match "2.0.0-beta.1".
+func ParseConstraint(s string) (*Constraint, error) {
+	raw := strings.TrimSpace(s)
+	if len(raw) > MaxConstraintLength {
+		return nil, fmt.Errorf("constraint too long (max %d characters)", MaxConstraintLength)
+	}
+
+	c := &Constraint{raw: raw}
+	for _, alternative := range strings.Split(raw, "||") {
+		r, satisfiable, err := parseRange(strings.TrimSpace(alternative))
+		if err != nil {
+			return nil, err
+		}
+		if satisfiable {
+			c.ranges = append(c.ranges, r)
+		}
+	}
+
+	return c, nil
+}
+
+// Ranges returns the satisfiable ranges of the constraint
+func (c *Constraint) Ranges() []Range {
+	return c.ranges
+}
+
+// Check reports whether v satisfies the constraint. Pre-release versions only
+// match when includePrerelease is set or a range admits them explicitly.
+func (c *Constraint) Check(v Version, includePrerelease bool) bool {
+	for _, r := range c.ranges {
+		if r.Contains(v) && (!v.IsPrerelease() || includePrerelease || r.AdmitsPrerelease(v)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Exact returns the single version the constraint pins, if it pins one
+func (c *Constraint) Exact() (Version, bool) {
+	if len(c.ranges) != 1 {
+		return Version{}, false
+	}
+	r := c.ranges[0]
+	if r.Lower == nil || r.Upper == nil || !r.Lower.Inclusive || !r.Upper.Inclusive {
+		return Version{}, false
+	}
+	if r.Lower.Version.Compare(r.Upper.Version) != 0 {
+		return Version{}, false
+	}
+	return r.Lower.Version, true
+}
+
+// String returns the constraint as written
+func (c *Constraint) String() string {
+	return c.raw
+}
+
+// parseRange parses one "||" alternative into the intersection of its comparators
+func parseRange(s string) (Range, bool, error) {
+	if s == "" {
+		return Range{}, true, nil
+	}
+
+	fields := strings.Fields(s)
+	if len(fields) == 3 && fields[1] == "-" {
+		return parseHyphenRange(fields[0], fields[2])
+	}
+
+	normalized := operatorSpacing.ReplaceAllString(strings.ReplaceAll(s, ",", " "), "$1")
+
+	var result Range
+	for _, comparator := range strings.Fields(normalized) {
+		r, satisfiable, err := parseComparator(comparator)
+		if err != nil {
+			return Range{}, false, err
+		}
+		if !satisfiable {
+			return Range{}, false, nil
+		}
+		result = result.intersect(r)
+	}
+
+	return result, !result.isEmpty(), nil
+}
+
+// parseHyphenRange parses "a - b", which includes both ends
+func parseHyphenRange(from, to string) (Range, bool, error) {
+	lower, err := parsePartial(from)
+	if err != nil {
+		return Range{}, false, err
+	}
+	upper, err := parsePartial(to)
+	if err != nil {
+		return Range{}, false, err
+	}
+
+	var r Range
+	if lower.parts > 0 {
+		r.Lower = inclusive(lower.floor())
+	}
+	switch {
+	case upper.parts == 3:
+		r.Upper = inclusive(upper.floor())
+	case upper.parts > 0:
+		r.Upper = exclusive(lowest(upper.next()))
+	}
+
+	return r, !r.isEmpty(), nil
+}
+
+// parseComparator converts a single comparator into a range. The boolean is
+// false for comparators no version can satisfy, such as ">*".
+func parseComparator(s string) (Range, bool, error) {
+	op, operand := splitOperator(s)
+	p, err := parsePartial(operand)
+	if err != nil {
+		return Range{}, false, err
+	}
+
+	if p.parts == 0 {
+		// "*", "x" and friends: any version, or none for strict comparisons
+		return Range{}, op != ">" && op != "<", nil
+	}
+
+	switch op {
+	case "", "=":
+		if p.parts == 3 {
+			return Range{Lower: inclusive(p.floor()), Upper: inclusive(p.floor())}, true, nil
+		}
+		return Range{Lower: inclusive(p.floor()), Upper: exclusive(lowest(p.next()))}, true, nil
+
+	case "^":
+		var upper Version
+		switch {
+		case p.major > 0 || p.parts == 1:
+			upper = Version{Major: p.major + 1}
+		case p.minor > 0 || p.parts == 2:
+			upper = Version{Minor: p.minor + 1}
+		default:
+			upper = Version{Patch: p.patch + 1}
+		}
+		return Range{Lower: inclusive(p.floor()), Upper: exclusive(lowest(upper))}, true, nil
+
+	case "~":
+		if p.parts == 1 {
+			return Range{Lower: inclusive(p.floor()), Upper: exclusive(lowest(Version{Major: p.major + 1}))}, true, nil
+		}
+		return Range{Lower: inclusive(p.floor()), Upper: exclusive(lowest(Version{Major: p.major, Minor: p.minor + 1}))}, true, nil
+
+	case ">":
+		if p.parts == 3 {
+			return Range{Lower: exclusive(p.floor())}, true, nil
+		}
+		return Range{Lower: inclusive(p.next())}, true, nil
+
+	case ">=":
+		return Range{Lower: inclusive(p.floor())}, true, nil
+
+	case "<":
+		if p.parts == 3 {
+			return Range{Upper: exclusive(p.floor())}, true, nil
+		}
+		return Range{Upper: exclusive(lowest(p.floor()))}, true, nil
+
+	case "<=":
+		if p.parts == 3 {
+			return Range{Upper: inclusive(p.floor())}, true, nil
+		}
+		return Range{Upper: exclusive(lowest(p.next()))}, true, nil
+	}
+
+	return Range{}, false, fmt.Errorf("invalid comparator %q", s)
+}
+
+// splitOperator separates a comparator into its operator and version operand
+func splitOperator(s string) (string, string) {
+	for _, op := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(s, op) {
+			return op, s[len(op):]
+		}
+	}
+	return "", s
+}
+
+// partial is a possibly incomplete version such as "1", "1.2" or "1.2.x"
+type partial struct {
+	major, minor, patch int
+	prerelease          string
+	parts               int // number of leading numeric parts given
+}
+
+// parsePartial parses "1", "1.2", "1.2.3", "1.2.3-beta.1" and wildcard forms
+// ("1.x", "1.2.*", "*"). Build metadata is ignored.
+func parsePartial(s string) (partial, error) {
+	s = strings.TrimPrefix(s, "v")
+	if s == "" {
+		return partial{}, fmt.Errorf("missing version in constraint")
+	}
+
+	if strings.ContainsAny(s, "-+") {
+		v, err := Parse(s)
+		if err != nil {
+			return partial{}, fmt.Errorf("invalid version %q in constraint: pre-release and build metadata require a full version", s)
+		}
+		return partial{major: v.Major, minor: v.Minor, patch: v.Patch, prerelease: v.Prerelease, parts: 3}, nil
+	}
+
+	fields := strings.Split(s, ".")
+	if len(fields) > 3 {
+		return partial{}, fmt.Errorf("invalid version %q in constraint", s)
+	}
+
+	var p partial
+	numbers := []*int{&p.major, &p.minor, &p.patch}
+	wildcard := false
+	for i, field := range fields {
+		if field == "x" || field == "X" || field == "*" {
+			wildcard = true
+			continue
+		}
+		if wildcard {
+			return partial{}, fmt.Errorf("invalid version %q in constraint: number after wildcard", s)
+		}
+		n, err := parseNumber(field)
+		if err != nil {
+			return partial{}, fmt.Errorf("invalid version %q in constraint: %w", s, err)
+		}
+		*numbers[i] = n
+		p.parts = i + 1
+	}
+
+	return p, nil
+}
+
+// floor returns the lowest version matching the partial
+func (p partial) floor() Version {
+	return Version{Major: p.major, Minor: p.minor, Patch: p.patch, Prerelease: p.prerelease}
+}
+
+// next returns the lowest version above every version matching the partial
+func (p partial) next() Version {
+	switch p.parts {
+	case 1:
+		return Version{Major: p.major + 1}
+	case 2:
+		return Version{Major: p.major, Minor: p.minor + 1}
+	default:
+		return Version{Major: p.major, Minor: p.minor, Patch: p.patch + 1}
+	}
+}
+
+// lowest returns the lowest pre-release of v's major.minor.patch, the tightest
+// exclusive upper bound that keeps v's pre-releases out of a range
+func lowest(v Version) Version {
+	v.Prerelease = "0"
+	return v
+}
+
+func inclusive(v Version) *Bound {
+	return &Bound{Version: v, Inclusive: true}
+}
+
+func exclusive(v Version) *Bound {
+	return &Bound{Version: v, Inclusive: false}
+}
@@ -0,0 +1,172 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package semver
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConstraintCheck(t *testing.T) {
+	tests := []struct {
+		constraint string
+		match      []string
+		noMatch    []string
+	}{
+		{"1.2.3", []string{"1.2.3", "v1.2.3", "1.2.3+build.1"}, []string{"1.2.4", "1.2.2", "1.2.3-beta"}},
+		{"=1.2.3", []string{"1.2.3"}, []string{"1.2.4"}},
+		{"1.2.3+build.5", []string{"1.2.3", "1.2.3+build.6"}, []string{"1.2.4"}},
+		{"1.2", []string{"1.2.0", "1.2.99"}, []string{"1.3.0", "1.1.9"}},
+		{"^1.2.3", []string{"1.2.3", "1.9.9"}, []string{"1.2.2", "2.0.0", "1.3.0-beta", "2.0.0-0", "2.0.0-beta.1"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0", "0.2.2"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4", "0.0.2"}},
+		{"^1", []string{"1.0.0", "1.99.0"}, []string{"2.0.0", "0.9.0"}},
+		{"^0.2", []string{"0.2.0", "0.2.7"}, []string{"0.3.0"}},
+		{"^0", []string{"0.0.1", "0.9.9"}, []string{"1.0.0"}},
+		{"~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.3.0", "1.2.2"}},
+		{"~1.2", []string{"1.2.0", "1.2.9"}, []string{"1.3.0"}},
+		{"~1", []string{"1.0.0", "1.9.0"}, []string{"2.0.0"}},
+		{">1.2.3", []string{"1.2.4", "3.0.0"}, []string{"1.2.3", "1.0.0"}},
+		{">1.2", []string{"1.3.0"}, []string{"1.2.9"}},
+		{">=1.2.3", []string{"1.2.3", "2.0.0"}, []string{"1.2.2"}},
+		{"<2", []string{"1.99.99"}, []string{"2.0.0", "2.0.0-beta"}},
+		{"<2.0.0", []string{"1.99.99"}, []string{"2.0.0", "2.0.0-beta"}},
+		{"<=1.4.x", []string{"1.4.99"}, []string{"1.5.0"}},
+		{"<=1.4.2", []string{"1.4.2"}, []string{"1.4.3"}},
+		{"1.x", []string{"1.0.0", "1.9.9"}, []string{"2.0.0", "0.9.0"}},
+		{"1.2.*", []string{"1.2.0", "1.2.9"}, []string{"1.3.0"}},
+		{"*", []string{"0.0.1", "99.0.0"}, []string{"1.0.0-beta"}},
+		{"1.2 - 2.3.4", []string{"1.2.0", "2.3.4"}, []string{"1.1.9", "2.3.5"}},
+		{"1.2.3 - 2", []string{"1.2.3", "2.9.9"}, []string{"3.0.0"}},
+		{">= 1.2.0 < 2", []string{"1.2.0", "1.9.0"}, []string{"2.0.0", "1.1.0"}},
+		{">=1.2.0, <2", []string{"1.5.0"}, []string{"2.1.0"}},
+		{"<1.0.0 || >=2.0.0", []string{"0.9.0", "2.0.0"}, []string{"1.0.0", "1.5.0"}},
+		{"^1.2.3-beta.2", []string{"1.2.3-beta.2", "1.2.3-beta.10", "1.2.3-rc.1", "1.2.3", "1.5.0"}, []string{"1.2.3-beta.1", "1.2.3-alpha", "1.2.4-alpha"}},
+		{">*", nil, []string{"1.0.0"}},
+		{">2.0.0 <1.0.0", nil, []string{"1.5.0", "2.5.0"}},
+		{"=1.2.3 || >*", []string{"1.2.3"}, []string{"1.2.4"}},
+	}
+
+	for _, tt := range tests {
+		c, err := ParseConstraint(tt.constraint)
+		if err != nil {
+			t.Errorf("ParseConstraint(%q) error = %v", tt.constraint, err)
+			continue
+		}
+		for _, version := range tt.match {
+			if !c.Check(mustParse(t, version), false) {
+				t.Errorf("%q does not match %s", tt.constraint, version)
+			}
+		}
+		for _, version := range tt.noMatch {
+			if c.Check(mustParse(t, version), false) {
+				t.Errorf("%q matches %s", tt.constraint, version)
+			}
+		}
+	}
+}
+
+func TestConstraintCheckIncludePrerelease(t *testing.T) {
+	c, err := ParseConstraint("^1.2.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Check(mustParse(t, "1.3.0-beta.1"), false) {
+		t.Error("^1.2.0 matches 1.3.0-beta.1 without opting in to pre-releases")
+	}
+	if !c.Check(mustParse(t, "1.3.0-beta.1"), true) {
+		t.Error("^1.2.0 does not match 1.3.0-beta.1 with pre-releases included")
+	}
+	// The range's exclusive upper bound keeps the next major's pre-releases out either way
+	if c.Check(mustParse(t, "2.0.0-alpha"), true) {
+		t.Error("^1.2.0 matches 2.0.0-alpha")
+	}
+
+	// Upper bounds written as full versions admit that version's pre-releases on opt-in
+	c, err = ParseConstraint("<2.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Check(mustParse(t, "2.0.0-beta"), true) {
+		t.Error("<2.0.0 does not match 2.0.0-beta with pre-releases included")
+	}
+}
+
+func TestParseConstraintErrors(t *testing.T) {
+	tests := []struct {
+		constraint string
+		message    string
+	}{
+		{"^1.x.bad", "number after wildcard"},
+		{"1.2.3.4", "invalid version"},
+		{"01.2", "leading zero"},
+		{"abc", "invalid version"},
+		{">=", "missing version"},
+		{"1.2-beta", "require a full version"},
+		{"^1.2.3-", "require a full version"},
+		{"1.2 - ", "invalid version"},
+		{"1.2.3 || ^x.1", "number after wildcard"},
+		{strings.Repeat("1", MaxConstraintLength+1), "too long"},
+	}
+	for _, tt := range tests {
+		c, err := ParseConstraint(tt.constraint)
+		if err == nil {
+			t.Errorf("ParseConstraint(%q) = %v, want an error", tt.constraint, c.Ranges())
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.message) {
+			t.Errorf("ParseConstraint(%q) error = %q, want it to contain %q", tt.constraint, err, tt.message)
+		}
+	}
+}
+
+func TestConstraintExact(t *testing.T) {
+	tests := []struct {
+		constraint string
+		want       string // "" when the constraint does not pin a version
+	}{
+		{"1.2.3", "1.2.3"},
+		{"=v1.2.3-rc.1", "1.2.3-rc.1"},
+		{">=1.2.3 <=1.2.3", "1.2.3"},
+		{"1.2", ""},
+		{"^1.2.3", ""},
+		{"1.2.3 || 1.2.4", ""},
+		{">*", ""},
+	}
+	for _, tt := range tests {
+		c, err := ParseConstraint(tt.constraint)
+		if err != nil {
+			t.Fatalf("ParseConstraint(%q) error = %v", tt.constraint, err)
+		}
+		v, ok := c.Exact()
+		if got := map[bool]string{true: v.String(), false: ""}[ok]; got != tt.want {
+			t.Errorf("ParseConstraint(%q).Exact() = %q, want %q", tt.constraint, got, tt.want)
+		}
+	}
+}
+
+func TestConstraintString(t *testing.T) {
+	c, err := ParseConstraint("  >= 1.2.0 < 2  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.String(); got != ">= 1.2.0 < 2" {
+		t.Errorf("String() = %q, want the trimmed expression", got)
+	}
+}
+
+func mustParse(t *testing.T, s string) Version {
+	t.Helper()
+	v, err := Parse(s)
+	if err != nil {
+		t.Fatalf("Parse(%q) error = %v", s, err)
+	}
+	return v
+}
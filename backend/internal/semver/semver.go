@@ -0,0 +1,212 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+// Package semver parses policy versions and npm/Cargo-style version range constraints
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a SemVer 2.0 version: major.minor.patch[-prerelease][+build]
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string // dot-separated identifiers, e.g. "beta.1"; empty for releases
+	Build      string // build metadata; ignored for precedence
+}
+
+// Parse parses a full SemVer 2.0 version. A leading "v" is accepted.
+func Parse(s string) (Version, error) {
+	rest := strings.TrimPrefix(s, "v")
+
+	var v Version
+	if i := strings.IndexByte(rest, '+'); i >= 0 {
+		v.Build = rest[i+1:]
+		rest = rest[:i]
+		if err := validateIdentifiers(v.Build, false); err != nil {
+			return Version{}, fmt.Errorf("invalid build metadata in version %s: %w", s, err)
+		}
+	}
+	if i := strings.IndexByte(rest, '-'); i >= 0 {
+		v.Prerelease = rest[i+1:]
+		rest = rest[:i]
+		if err := validateIdentifiers(v.Prerelease, true); err != nil {
+			return Version{}, fmt.Errorf("invalid pre-release in version %s: %w", s, err)
+		}
+	}
+
+	parts := strings.Split(rest, ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("version must be in format 'major.minor.patch', got: %s", s)
+	}
+
+	numbers := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		n, err := parseNumber(part)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %s: %w", s, err)
+		}
+		*numbers[i] = n
+	}
+
+	return v, nil
+}
+
+// IsPrerelease reports whether v is a pre-release version
+func (v Version) IsPrerelease() bool {
+	return v.Prerelease != ""
+}
+
+// Compare returns -1, 0 or 1 when v has lower, equal or higher precedence than o.
+// Build metadata does not take part in precedence.
+func (v Version) Compare(o Version) int {
+	switch {
+	case v.Major != o.Major:
+		return compareInt(v.Major, o.Major)
+	case v.Minor != o.Minor:
+		return compareInt(v.Minor, o.Minor)
+	case v.Patch != o.Patch:
+		return compareInt(v.Patch, o.Patch)
+	default:
+		return comparePrerelease(v.Prerelease, o.Prerelease)
+	}
+}
+
+// String returns the version in SemVer notation
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// Compare compares two version strings; unparsable versions sort lowest
+func Compare(a, b string) int {
+	va, errA := Parse(a)
+	vb, errB := Parse(b)
+	switch {
+	case errA != nil && errB != nil:
+		return 0
+	case errA != nil:
+		return -1
+	case errB != nil:
+		return 1
+	}
+	return va.Compare(vb)
+}
+
+// IsPrerelease reports whether the version string is a valid pre-release version
+func IsPrerelease(s string) bool {
+	v, err := Parse(s)
+	return err == nil && v.IsPrerelease()
+}
+
+// comparePrerelease orders pre-release strings by SemVer precedence. A release
+// (empty pre-release) has higher precedence than any of its pre-releases.
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if cmp := compareIdentifier(as[i], bs[i]); cmp != 0 {
+			return cmp
+		}
+	}
+	return compareInt(len(as), len(bs))
+}
+
+// compareIdentifier compares pre-release identifiers: numeric ones numerically,
+// alphanumeric ones in ASCII order, and numeric below alphanumeric
+func compareIdentifier(a, b string) int {
+	aNum, bNum := isNumeric(a), isNumeric(b)
+	switch {
+	case aNum && bNum:
+		if len(a) != len(b) {
+			return compareInt(len(a), len(b))
+		}
+		return strings.Compare(a, b)
+	case aNum:
+		return -1
+	case bNum:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// validateIdentifiers checks dot-separated pre-release or build identifiers
+func validateIdentifiers(s string, prerelease bool) error {
+	for _, id := range strings.Split(s, ".") {
+		if id == "" {
+			return fmt.Errorf("empty identifier")
+		}
+		for _, c := range id {
+			if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '-') {
+				return fmt.Errorf("invalid character %q in identifier %q", c, id)
+			}
+		}
+		if prerelease && isNumeric(id) && len(id) > 1 && id[0] == '0' {
+			return fmt.Errorf("numeric identifier %q has a leading zero", id)
+		}
+	}
+	return nil
+}
+
+func isNumeric(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// parseNumber parses a non-negative decimal version number
+func parseNumber(s string) (int, error) {
+	if s == "" {
+		return 0, fmt.Errorf("empty version number")
+	}
+	if !isNumeric(s) {
+		return 0, fmt.Errorf("invalid version number %q", s)
+	}
+	if len(s) > 1 && s[0] == '0' {
+		return 0, fmt.Errorf("version number %q has a leading zero", s)
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid version number %q", s)
+	}
+	return n, nil
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
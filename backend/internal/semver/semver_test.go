@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package semver
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Version
+	}{
+		{"1.2.3", Version{Major: 1, Minor: 2, Patch: 3}},
+		{"v1.2.3", Version{Major: 1, Minor: 2, Patch: 3}},
+		{"0.0.0", Version{}},
+		{"10.20.30", Version{Major: 10, Minor: 20, Patch: 30}},
+		{"1.2.3-beta.1", Version{Major: 1, Minor: 2, Patch: 3, Prerelease: "beta.1"}},
+		{"1.2.3-0.3.7", Version{Major: 1, Minor: 2, Patch: 3, Prerelease: "0.3.7"}},
+		{"1.2.3-x-y-z.--", Version{Major: 1, Minor: 2, Patch: 3, Prerelease: "x-y-z.--"}},
+		{"1.2.3+build.5", Version{Major: 1, Minor: 2, Patch: 3, Build: "build.5"}},
+		{"1.2.3+001", Version{Major: 1, Minor: 2, Patch: 3, Build: "001"}},
+		{"1.2.3-rc.1+build-5.sha.abc", Version{Major: 1, Minor: 2, Patch: 3, Prerelease: "rc.1", Build: "build-5.sha.abc"}},
+		{"1.2.3-rc+build-1", Version{Major: 1, Minor: 2, Patch: 3, Prerelease: "rc", Build: "build-1"}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q) error = %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		message string
+	}{
+		{"", "major.minor.patch"},
+		{"1.2", "major.minor.patch"},
+		{"1.2.3.4", "major.minor.patch"},
+		{"1.2.x", "invalid version number"},
+		{"01.2.3", "leading zero"},
+		{"1.02.3", "leading zero"},
+		{"-1.2.3", "major.minor.patch"},
+		{"1..3", "empty version number"},
+		{"1.2.3-", "empty identifier"},
+		{"1.2.3-beta..1", "empty identifier"},
+		{"1.2.3-01", "leading zero"},
+		{"1.2.3-beta_1", "invalid character"},
+		{"1.2.3+", "empty identifier"},
+		{"1.2.3+build+other", "invalid character"},
+		{"1.2.3+build.ü", "invalid character"},
+		{"99999999999999999999.0.0", "invalid version number"},
+	}
+	for _, tt := range tests {
+		v, err := Parse(tt.input)
+		if err == nil {
+			t.Errorf("Parse(%q) = %+v, want an error", tt.input, v)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.message) {
+			t.Errorf("Parse(%q) error = %q, want it to contain %q", tt.input, err, tt.message)
+		}
+	}
+}
+
+func TestVersionString(t *testing.T) {
+	for _, s := range []string{"1.2.3", "1.2.3-beta.1", "1.2.3+build.5", "1.2.3-rc.1+build.5"} {
+		if got := mustParse(t, s).String(); got != s {
+			t.Errorf("Parse(%q).String() = %q", s, got)
+		}
+	}
+	if got := mustParse(t, "v1.2.3").String(); got != "1.2.3" {
+		t.Errorf("String() = %q, want the v prefix dropped", got)
+	}
+}
+
+// TestPrecedence checks the ordering example of the SemVer 2.0 specification
+func TestPrecedence(t *testing.T) {
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2",
+		"1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "1.10.0", "2.0.0",
+	}
+	for i := range ordered {
+		for j := range ordered {
+			want := compareInt(i, j)
+			if got := Compare(ordered[i], ordered[j]); got != want {
+				t.Errorf("Compare(%q, %q) = %d, want %d", ordered[i], ordered[j], got, want)
+			}
+		}
+	}
+
+	shuffled := []string{"1.0.0", "1.0.0-beta.11", "2.0.0", "1.0.0-alpha", "1.10.0", "1.0.0-rc.1",
+		"1.0.0-alpha.beta", "1.1.0", "1.0.0-beta", "1.0.1", "1.0.0-alpha.1", "1.0.0-beta.2"}
+	sort.Slice(shuffled, func(i, j int) bool { return Compare(shuffled[i], shuffled[j]) < 0 })
+	if strings.Join(shuffled, " ") != strings.Join(ordered, " ") {
+		t.Errorf("sorted = %v, want %v", shuffled, ordered)
+	}
+}
+
+func TestCompareIgnoresBuildMetadata(t *testing.T) {
+	tests := [][2]string{
+		{"1.2.3+b1", "1.2.3+b2"},
+		{"1.2.3", "1.2.3+b1"},
+		{"1.2.3-rc.1+b1", "1.2.3-rc.1"},
+		{"v1.2.3", "1.2.3"},
+	}
+	for _, tt := range tests {
+		if got := Compare(tt[0], tt[1]); got != 0 {
+			t.Errorf("Compare(%q, %q) = %d, want 0", tt[0], tt[1], got)
+		}
+	}
+}
+
+func TestCompareInvalid(t *testing.T) {
+	if got := Compare("bad", "1.0.0"); got != -1 {
+		t.Errorf("Compare(bad, 1.0.0) = %d, want -1", got)
+	}
+	if got := Compare("1.0.0", "bad"); got != 1 {
+		t.Errorf("Compare(1.0.0, bad) = %d, want 1", got)
+	}
+	if got := Compare("bad", "worse"); got != 0 {
+		t.Errorf("Compare(bad, worse) = %d, want 0", got)
+	}
+}
+
+func TestIsPrerelease(t *testing.T) {
+	tests := map[string]bool{
+		"1.2.3":         false,
+		"1.2.3+build.1": false,
+		"1.2.3-beta":    true,
+		"1.2.3-rc.1+b2": true,
+		"1.2.3-":        false,
+		"bad-version":   false,
+	}
+	for input, want := range tests {
+		if got := IsPrerelease(input); got != want {
+			t.Errorf("IsPrerelease(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
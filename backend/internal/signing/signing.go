@@ -0,0 +1,113 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+// Package signing builds the payload of signed policy releases and verifies
+// detached release signatures against provider public keys
+package signing
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// PayloadVersion is the first line of every signing payload
+const PayloadVersion = "policyhub-signature-v2"
+
+// Signature algorithms
+const (
+	AlgorithmEd25519     = "ed25519"
+	AlgorithmECDSASHA256 = "ecdsa-sha256" // ECDSA P-256 over SHA-256, as produced by cosign sign-blob
+)
+
+// UnsignedPolicy decides what happens to syncs that carry no signature
+type UnsignedPolicy string
+
+const (
+	UnsignedAllow  UnsignedPolicy = "allow"
+	UnsignedWarn   UnsignedPolicy = "warn"
+	UnsignedReject UnsignedPolicy = "reject"
+)
+
+// IsValid reports whether the policy is one of the known values
+func (p UnsignedPolicy) IsValid() bool {
+	switch p {
+	case UnsignedAllow, UnsignedWarn, UnsignedReject:
+		return true
+	default:
+		return false
+	}
+}
+
+// Signature is a detached signature over a release payload
+type Signature struct {
+	KeyID     string `json:"keyId"`
+	Algorithm string `json:"algorithm,omitempty"` // optional; inferred from the key when empty
+	Value     string `json:"value"`               // base64 encoded signature bytes
+}
+
+// Payload builds the bytes a provider signs for a release:
+//
+//	policyhub-signature-v2
+//	policy: <name>
+//	version: <version>
+//	definition: sha256:<hex digest of the definition file>
+//	artifact: <artifactDigest>
+//	metadata: sha256:<hex digest of the canonical metadata JSON>
+//
+// Every line ends with "\n". artifactDigest is the "sha256:<hex>" digest that
+// identifies the release artifact. The canonical metadata JSON has its object
+// keys sorted, no insignificant whitespace and no HTML escaping.
+func Payload(name, version string, definition []byte, artifactDigest string, metadata any) ([]byte, error) {
+	canonical, err := CanonicalJSON(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to canonicalize metadata: %w", err)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s\n", PayloadVersion)
+	fmt.Fprintf(&buf, "policy: %s\n", name)
+	fmt.Fprintf(&buf, "version: %s\n", version)
+	fmt.Fprintf(&buf, "definition: %s\n", digest(definition))
+	fmt.Fprintf(&buf, "artifact: %s\n", artifactDigest)
+	fmt.Fprintf(&buf, "metadata: %s\n", digest(canonical))
+	return buf.Bytes(), nil
+}
+
+// CanonicalJSON encodes v with sorted object keys, no insignificant whitespace
+// and no HTML escaping
+func CanonicalJSON(v any) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// Round-trip through generic values so every object is a map, which encodes with sorted keys
+	var generic any
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(generic); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
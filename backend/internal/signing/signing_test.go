@@ -0,0 +1,146 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package signing
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+)
+
+func TestPayload(t *testing.T) {
+	definition := []byte("name: rate-limit\nversion: 1.2.0\n")
+	artifact := "sha256:" + hex.EncodeToString(make([]byte, 32))
+	metadata := map[string]any{"provider": "WSO2", "displayName": "Rate Limit"}
+
+	payload, err := Payload("rate-limit", "1.2.0", definition, artifact, metadata)
+	if err != nil {
+		t.Fatalf("Payload() error = %v", err)
+	}
+	want := "policyhub-signature-v2\n" +
+		"policy: rate-limit\n" +
+		"version: 1.2.0\n" +
+		"definition: " + sha256Digest(definition) + "\n" +
+		"artifact: " + artifact + "\n" +
+		"metadata: " + sha256Digest([]byte(`{"displayName":"Rate Limit","provider":"WSO2"}`)) + "\n"
+	if string(payload) != want {
+		t.Errorf("Payload() =\n%s\nwant\n%s", payload, want)
+	}
+}
+
+func TestPayloadBindsEveryField(t *testing.T) {
+	definition := []byte("name: rate-limit\n")
+	artifact := "sha256:" + hex.EncodeToString(make([]byte, 32))
+	metadata := map[string]any{"provider": "WSO2"}
+	base, err := Payload("rate-limit", "1.2.0", definition, artifact, metadata)
+	if err != nil {
+		t.Fatalf("Payload() error = %v", err)
+	}
+
+	variants := map[string]func() ([]byte, error){
+		"name":    func() ([]byte, error) { return Payload("cors", "1.2.0", definition, artifact, metadata) },
+		"version": func() ([]byte, error) { return Payload("rate-limit", "1.2.1", definition, artifact, metadata) },
+		"definition": func() ([]byte, error) {
+			return Payload("rate-limit", "1.2.0", []byte("name: cors\n"), artifact, metadata)
+		},
+		"artifact": func() ([]byte, error) { return Payload("rate-limit", "1.2.0", definition, sha256Digest(nil), metadata) },
+		"metadata": func() ([]byte, error) {
+			return Payload("rate-limit", "1.2.0", definition, artifact, map[string]any{"provider": "Acme"})
+		},
+	}
+	for name, payload := range variants {
+		got, err := payload()
+		if err != nil {
+			t.Fatalf("Payload() with another %s error = %v", name, err)
+		}
+		if string(got) == string(base) {
+			t.Errorf("changing the %s kept the payload", name)
+		}
+	}
+}
+
+func TestPayloadInvalidMetadata(t *testing.T) {
+	if _, err := Payload("rate-limit", "1.2.0", nil, "", map[string]any{"bad": make(chan int)}); err == nil {
+		t.Error("Payload() accepted metadata that cannot be encoded as JSON")
+	}
+}
+
+func TestCanonicalJSON(t *testing.T) {
+	// Fields are declared out of order, so only sorting puts them in order
+	type metadata struct {
+		Provider    string   `json:"provider"`
+		DisplayName string   `json:"displayName"`
+		Tags        []string `json:"tags"`
+	}
+
+	tests := []struct {
+		name  string
+		input any
+		want  string
+	}{
+		{
+			name:  "struct fields sorted",
+			input: metadata{Provider: "WSO2", DisplayName: "Rate Limit", Tags: []string{"z", "a"}},
+			want:  `{"displayName":"Rate Limit","provider":"WSO2","tags":["z","a"]}`,
+		},
+		{
+			name:  "nested keys sorted",
+			input: map[string]any{"b": map[string]any{"y": 1, "x": 2}, "a": []any{map[string]any{"d": true, "c": nil}}},
+			want:  `{"a":[{"c":null,"d":true}],"b":{"x":2,"y":1}}`,
+		},
+		{
+			name:  "no HTML escaping",
+			input: map[string]string{"description": "<b>limits</b> & quotas"},
+			want:  `{"description":"<b>limits</b> & quotas"}`,
+		},
+		{
+			name:  "non-ASCII kept",
+			input: map[string]string{"displayName": "Débit ✓"},
+			want:  `{"displayName":"Débit ✓"}`,
+		},
+		{
+			name:  "whitespace removed",
+			input: json.RawMessage("{ \"b\" : [ 1, 2 ],\n  \"a\" : \"x\" }"),
+			want:  `{"a":"x","b":[1,2]}`,
+		},
+		{
+			name:  "numbers kept as written",
+			input: json.RawMessage(`{"big":12345678901234567890,"fraction":1.50,"exponent":1e3}`),
+			want:  `{"big":12345678901234567890,"exponent":1e3,"fraction":1.50}`,
+		},
+		{
+			name:  "empty list kept",
+			input: map[string]any{"tags": []string{}},
+			want:  `{"tags":[]}`,
+		},
+		{
+			name:  "nil list",
+			input: map[string]any{"tags": []string(nil)},
+			want:  `{"tags":null}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CanonicalJSON(tt.input)
+			if err != nil {
+				t.Fatalf("CanonicalJSON() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("CanonicalJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func sha256Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
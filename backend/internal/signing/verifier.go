@@ -0,0 +1,178 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package signing
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Verification errors
+var (
+	ErrUnknownKey       = errors.New("unknown signing key")
+	ErrProviderMismatch = errors.New("signing key does not belong to the policy provider")
+	ErrAlgorithm        = errors.New("signature algorithm does not match the signing key")
+	ErrBadSignature     = errors.New("signature does not match the release")
+)
+
+// keyEntry is a single entry of the signing keys file
+type keyEntry struct {
+	Provider  string `json:"provider"`
+	KeyID     string `json:"keyId"`
+	PublicKey string `json:"publicKey"` // PEM encoded PKIX public key
+}
+
+// providerKey is a loaded public key and the provider it signs for
+type providerKey struct {
+	provider  string
+	algorithm string
+	key       crypto.PublicKey
+}
+
+// Verifier checks release signatures against the configured provider keys
+type Verifier struct {
+	keys     map[string]providerKey
+	unsigned UnsignedPolicy
+}
+
+// NewVerifier loads provider public keys from a JSON file of the form
+// [{"provider": "WSO2", "keyId": "wso2-2025", "publicKey": "-----BEGIN PUBLIC KEY-----..."}].
+// An empty path configures no keys, so every signed sync fails verification.
+func NewVerifier(keysFile string, unsigned UnsignedPolicy) (*Verifier, error) {
+	if !unsigned.IsValid() {
+		return nil, fmt.Errorf("invalid unsigned sync policy %q (must be allow, warn or reject)", unsigned)
+	}
+
+	verifier := &Verifier{
+		keys:     make(map[string]providerKey),
+		unsigned: unsigned,
+	}
+	if keysFile == "" {
+		return verifier, nil
+	}
+
+	data, err := os.ReadFile(keysFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing keys file: %w", err)
+	}
+
+	var entries []keyEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse signing keys file: %w", err)
+	}
+
+	for i, entry := range entries {
+		if entry.KeyID == "" || entry.Provider == "" {
+			return nil, fmt.Errorf("signing key entry %d needs a keyId and a provider", i)
+		}
+		if _, exists := verifier.keys[entry.KeyID]; exists {
+			return nil, fmt.Errorf("duplicate signing key id %q", entry.KeyID)
+		}
+
+		key, algorithm, err := parsePublicKey(entry.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("signing key %q: %w", entry.KeyID, err)
+		}
+		verifier.keys[entry.KeyID] = providerKey{
+			provider:  entry.Provider,
+			algorithm: algorithm,
+			key:       key,
+		}
+	}
+
+	return verifier, nil
+}
+
+// UnsignedPolicy returns how unsigned syncs are treated
+func (v *Verifier) UnsignedPolicy() UnsignedPolicy {
+	return v.unsigned
+}
+
+// Verify checks that sig is a valid signature of payload by a key of provider
+func (v *Verifier) Verify(provider string, payload []byte, sig Signature) error {
+	key, ok := v.keys[sig.KeyID]
+	if !ok {
+		return ErrUnknownKey
+	}
+	if key.provider != provider {
+		return ErrProviderMismatch
+	}
+	if sig.Algorithm != "" && !strings.EqualFold(sig.Algorithm, key.algorithm) {
+		return ErrAlgorithm
+	}
+
+	signature, err := decodeSignature(sig.Value)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+
+	switch publicKey := key.key.(type) {
+	case ed25519.PublicKey:
+		if !ed25519.Verify(publicKey, payload, signature) {
+			return ErrBadSignature
+		}
+	case *ecdsa.PublicKey:
+		sum := sha256.Sum256(payload)
+		if !ecdsa.VerifyASN1(publicKey, sum[:], signature) {
+			return ErrBadSignature
+		}
+	default:
+		return ErrAlgorithm
+	}
+
+	return nil
+}
+
+// parsePublicKey decodes a PEM PKIX public key and names its signature algorithm
+func parsePublicKey(pemData string) (crypto.PublicKey, string, error) {
+	block, _ := pem.Decode([]byte(pemData))
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, "", fmt.Errorf("publicKey must be a PEM encoded PUBLIC KEY")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid public key: %w", err)
+	}
+
+	switch publicKey := key.(type) {
+	case ed25519.PublicKey:
+		return publicKey, AlgorithmEd25519, nil
+	case *ecdsa.PublicKey:
+		if publicKey.Curve != elliptic.P256() {
+			return nil, "", fmt.Errorf("unsupported ECDSA curve %s (must be P-256)", publicKey.Curve.Params().Name)
+		}
+		return publicKey, AlgorithmECDSASHA256, nil
+	default:
+		return nil, "", fmt.Errorf("unsupported public key type %T (must be Ed25519 or ECDSA P-256)", key)
+	}
+}
+
+// decodeSignature accepts standard or URL-safe base64, padded or not
+func decodeSignature(value string) ([]byte, error) {
+	value = strings.TrimSpace(value)
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if decoded, err := encoding.DecodeString(value); err == nil {
+			return decoded, nil
+		}
+	}
+	return nil, errors.New("not base64")
+}
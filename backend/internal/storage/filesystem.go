@@ -0,0 +1,160 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// metaSuffix names the sidecar file that records a blob's content type
+const metaSuffix = ".meta.json"
+
+// FilesystemStore stores blobs as files under a root directory
+type FilesystemStore struct {
+	root string
+}
+
+// fileMeta is the sidecar of a stored blob
+type fileMeta struct {
+	ContentType string `json:"contentType"`
+}
+
+// NewFilesystemStore opens a filesystem store, creating its root directory if needed
+func NewFilesystemStore(root string) (*FilesystemStore, error) {
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	return &FilesystemStore{root: root}, nil
+}
+
+// path maps a key to a file under the root, rejecting keys that would escape it
+func (s *FilesystemStore) path(key string) (string, error) {
+	if !fs.ValidPath(key) || key == "." {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+// Put writes the blob to a temporary file and renames it into place, so
+// readers never see a partial blob
+func (s *FilesystemStore) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o750); err != nil {
+		return fmt.Errorf("failed to create blob directory: %w", err)
+	}
+
+	meta, err := json.Marshal(fileMeta{ContentType: contentType})
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(name+metaSuffix, func(f *os.File) error {
+		_, err := f.Write(meta)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(name, func(f *os.File) error {
+		written, err := io.Copy(f, body)
+		if err != nil {
+			return err
+		}
+		if written != size {
+			return fmt.Errorf("blob %s is %d bytes, expected %d", key, written, size)
+		}
+		return nil
+	})
+}
+
+func (s *FilesystemStore) Get(ctx context.Context, key string) (io.ReadCloser, *Object, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return nil, nil, ErrNotFound
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, ErrNotFound
+		}
+		return nil, nil, err
+	}
+	object, err := s.stat(name, f)
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return f, object, nil
+}
+
+func (s *FilesystemStore) Stat(ctx context.Context, key string) (*Object, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	defer f.Close()
+	return s.stat(name, f)
+}
+
+// stat describes an open blob from its file and sidecar
+func (s *FilesystemStore) stat(name string, f *os.File) (*Object, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	object := &Object{Size: info.Size(), ContentType: "application/octet-stream"}
+
+	data, err := os.ReadFile(name + metaSuffix)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	var meta fileMeta
+	if err == nil && json.Unmarshal(data, &meta) == nil && meta.ContentType != "" {
+		object.ContentType = meta.ContentType
+	}
+	return object, nil
+}
+
+// writeFileAtomic writes a file through a temporary file in the same directory
+func writeFileAtomic(name string, write func(*os.File) error) error {
+	f, err := os.CreateTemp(filepath.Dir(name), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create blob file: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if err := write(f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write blob file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write blob file: %w", err)
+	}
+	if err := os.Rename(f.Name(), name); err != nil {
+		return fmt.Errorf("failed to store blob file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFilesystemStoreRoundTrip(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "blobs")
+	store, err := NewFilesystemStore(root)
+	if err != nil {
+		t.Fatalf("NewFilesystemStore: %v", err)
+	}
+	ctx := context.Background()
+	key := "sha256/ab/abcdef"
+	content := "policy artifact"
+
+	if err := store.Put(ctx, key, strings.NewReader(content), int64(len(content)), "application/gzip"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	object, err := store.Stat(ctx, key)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if object.Size != int64(len(content)) || object.ContentType != "application/gzip" {
+		t.Errorf("Stat = %+v, want size %d and type application/gzip", object, len(content))
+	}
+
+	body, object, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("reading blob: %v", err)
+	}
+	if string(data) != content {
+		t.Errorf("Get body = %q, want %q", data, content)
+	}
+	if object.ContentType != "application/gzip" {
+		t.Errorf("Get content type = %q, want application/gzip", object.ContentType)
+	}
+
+	// No temporary files are left next to the blob
+	entries, err := os.ReadDir(filepath.Join(root, "sha256", "ab"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".tmp-") {
+			t.Errorf("temporary file %s left behind", entry.Name())
+		}
+	}
+}
+
+func TestFilesystemStoreMissingSidecar(t *testing.T) {
+	root := t.TempDir()
+	store, err := NewFilesystemStore(root)
+	if err != nil {
+		t.Fatalf("NewFilesystemStore: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "blob"), []byte("data"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	object, err := store.Stat(context.Background(), "blob")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if object.ContentType != "application/octet-stream" {
+		t.Errorf("content type = %q, want application/octet-stream", object.ContentType)
+	}
+}
+
+func TestFilesystemStoreErrors(t *testing.T) {
+	root := t.TempDir()
+	store, err := NewFilesystemStore(filepath.Join(root, "blobs"))
+	if err != nil {
+		t.Fatalf("NewFilesystemStore: %v", err)
+	}
+	ctx := context.Background()
+
+	t.Run("missing blob", func(t *testing.T) {
+		if _, err := store.Stat(ctx, "sha256/00/missing"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Stat error = %v, want ErrNotFound", err)
+		}
+		if _, _, err := store.Get(ctx, "sha256/00/missing"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get error = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("size mismatch", func(t *testing.T) {
+		if err := store.Put(ctx, "sha256/00/short", strings.NewReader("abc"), 4, "text/plain"); err == nil {
+			t.Fatal("Put accepted a body shorter than its size")
+		}
+		if _, err := store.Stat(ctx, "sha256/00/short"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Stat error = %v, want ErrNotFound for a failed Put", err)
+		}
+	})
+
+	// Keys that would escape the root are never read or written
+	for _, key := range []string{"../outside", "/etc/passwd", "a/../../outside", ".", ""} {
+		t.Run("invalid key "+key, func(t *testing.T) {
+			if err := store.Put(ctx, key, strings.NewReader("x"), 1, "text/plain"); err == nil {
+				t.Errorf("Put(%q) succeeded", key)
+			}
+			if _, err := store.Stat(ctx, key); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Stat(%q) error = %v, want ErrNotFound", key, err)
+			}
+			if _, _, err := store.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Get(%q) error = %v, want ErrNotFound", key, err)
+			}
+		})
+	}
+	if _, err := os.Stat(filepath.Join(root, "outside")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("a blob was written outside the store root")
+	}
+}
+
+func TestDigestKey(t *testing.T) {
+	sum := strings.Repeat("ab", 32)
+	tests := []struct {
+		digest  string
+		want    string
+		wantErr bool
+	}{
+		{digest: "sha256:" + sum, want: "sha256/ab/" + sum},
+		{digest: sum, wantErr: true},
+		{digest: "sha512:" + sum, wantErr: true},
+		{digest: "sha256:" + sum[:62], wantErr: true},
+		{digest: "sha256:" + strings.ToUpper(sum), wantErr: true},
+		{digest: "sha256:" + strings.Repeat("zz", 32), wantErr: true},
+		{digest: "sha256:../" + sum[3:], wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := DigestKey(tt.digest)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("DigestKey(%q) error = %v, wantErr %v", tt.digest, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("DigestKey(%q) = %q, want %q", tt.digest, got, tt.want)
+		}
+	}
+}
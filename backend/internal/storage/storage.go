@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package storage
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/wso2/policyhub/internal/config"
+)
+
+// Storage backends
+const (
+	BackendFilesystem = "filesystem"
+	BackendS3         = "s3"
+)
+
+// digestPrefix is the algorithm prefix of the digests blobs are addressed by
+const digestPrefix = "sha256:"
+
+// ErrNotFound is returned for blobs that are not in the store
+var ErrNotFound = errors.New("blob not found")
+
+// Object describes a stored blob
+type Object struct {
+	Size        int64
+	ContentType string
+}
+
+// Store is a blob store. Keys are slash-separated relative paths; blobs are
+// written once and never modified.
+type Store interface {
+	// Put stores size bytes read from body under key
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Get opens a blob; missing blobs return ErrNotFound
+	Get(ctx context.Context, key string) (io.ReadCloser, *Object, error)
+	// Stat describes a blob; missing blobs return ErrNotFound
+	Stat(ctx context.Context, key string) (*Object, error)
+}
+
+// New opens the configured blob store. It returns nil when none is configured.
+func New(cfg *config.StorageConfig) (Store, error) {
+	switch cfg.Backend {
+	case "":
+		return nil, nil
+	case BackendFilesystem:
+		return NewFilesystemStore(cfg.Path)
+	case BackendS3:
+		return NewS3Store(&cfg.S3)
+	default:
+		return nil, fmt.Errorf("invalid storage backend %q (must be filesystem or s3)", cfg.Backend)
+	}
+}
+
+// DigestKey returns the content-addressed key of a "sha256:<hex>" digest,
+// sha256/<first two hex digits>/<hex>, so no directory grows too large
+func DigestKey(digest string) (string, error) {
+	sum, ok := strings.CutPrefix(digest, digestPrefix)
+	if !ok || len(sum) != 64 {
+		return "", fmt.Errorf("invalid digest %q", digest)
+	}
+	if _, err := hex.DecodeString(sum); err != nil || strings.ToLower(sum) != sum {
+		return "", fmt.Errorf("invalid digest %q", digest)
+	}
+	return "sha256/" + sum[:2] + "/" + sum, nil
+}
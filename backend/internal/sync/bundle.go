@@ -0,0 +1,521 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package sync
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/wso2/policyhub/internal/errs"
+	"github.com/wso2/policyhub/internal/policy"
+	"github.com/wso2/policyhub/internal/signing"
+)
+
+// BundleScheme is the URL scheme of files in an uploaded bundle. Requests
+// queued for a bundle reference them as bundle://<id>/<path>, and the archive
+// itself as bundle://<id>/.
+const BundleScheme = "bundle"
+
+// SourceTypeBundle is the source type of versions published from an uploaded bundle
+const SourceTypeBundle = "bundle"
+
+// maxBundlePathLength matches the path column of policy_bundle_file
+const maxBundlePathLength = 512
+
+// BundleFormat is the archive format of a bundle
+type BundleFormat string
+
+const (
+	BundleTarGz BundleFormat = "tar.gz"
+	BundleZip   BundleFormat = "zip"
+)
+
+// Bundle is an uploaded policy bundle, unpacked and validated. It holds the
+// same files as a version directory of a sync source.
+type Bundle struct {
+	PolicyName string
+	Version    string
+	Format     BundleFormat
+	Archive    []byte
+	Files      map[string][]byte // files kept from the archive, by path relative to the bundle root
+	Metadata   *policy.PolicyMetadata
+	Signature  *signing.Signature
+}
+
+// BundleArchive is the archive a version was published from
+type BundleArchive struct {
+	Format BundleFormat
+	Digest string
+	Data   []byte
+}
+
+// bundleURL returns the URL of a file in a bundle; an empty name is the archive
+func bundleURL(id int64, name string) string {
+	return (&url.URL{Scheme: BundleScheme, Host: strconv.FormatInt(id, 10), Path: "/" + name}).String()
+}
+
+// isBundleURL reports whether rawURL points into an uploaded bundle
+func isBundleURL(rawURL string) bool {
+	return strings.HasPrefix(rawURL, BundleScheme+"://")
+}
+
+// detectBundleFormat identifies an archive by its leading bytes
+func detectBundleFormat(archive []byte) (BundleFormat, bool) {
+	switch {
+	case bytes.HasPrefix(archive, []byte{0x1f, 0x8b}):
+		return BundleTarGz, true
+	case bytes.HasPrefix(archive, []byte("PK\x03\x04")):
+		return BundleZip, true
+	default:
+		return "", false
+	}
+}
+
+// MaxBundleSize returns the largest archive accepted for upload, in bytes
+func (s *Service) MaxBundleSize() int64 {
+	return s.cfg.Bundle.MaxSize
+}
+
+// OpenBundle unpacks and validates an uploaded .tar.gz or .zip bundle. Entries
+// must be regular files or directories with relative paths inside the bundle;
+// the archive may wrap its files in a single top-level directory.
+func (s *Service) OpenBundle(name, version string, archive []byte) (*Bundle, error) {
+	format, files, err := s.unpackBundle(archive)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := files[sourceDefinitionFile]; !ok {
+		return nil, errs.NewValidationError("policy bundle has no "+sourceDefinitionFile, nil)
+	}
+	metadataJSON, ok := files[sourceMetadataFile]
+	if !ok {
+		return nil, errs.NewValidationError("policy bundle has no "+sourceMetadataFile, nil)
+	}
+
+	var metadata policy.PolicyMetadata
+	if err := json.Unmarshal(metadataJSON, &metadata); err != nil {
+		return nil, errs.NewValidationError("invalid "+sourceMetadataFile, map[string]any{"error": err.Error()})
+	}
+	if metadata.DisplayName == "" || metadata.Provider == "" {
+		return nil, errs.NewValidationError(sourceMetadataFile+" requires a displayName and a provider", nil)
+	}
+
+	bundle := &Bundle{
+		PolicyName: name,
+		Version:    version,
+		Format:     format,
+		Archive:    archive,
+		Files:      files,
+		Metadata:   &metadata,
+	}
+
+	if data, ok := files[sourceSignatureFile]; ok {
+		var signature signing.Signature
+		if err := json.Unmarshal(data, &signature); err != nil {
+			return nil, errs.NewValidationError("invalid "+sourceSignatureFile, map[string]any{"error": err.Error()})
+		}
+		bundle.Signature = &signature
+	}
+
+	return bundle, nil
+}
+
+// unpackBundle unpacks an archive within the configured limits and returns
+// the files of the version layout
+func (s *Service) unpackBundle(archive []byte) (BundleFormat, map[string][]byte, error) {
+	limits := s.cfg.Bundle
+	if int64(len(archive)) > limits.MaxSize {
+		return "", nil, errs.BundleTooLarge("size", limits.MaxSize)
+	}
+
+	format, ok := detectBundleFormat(archive)
+	if !ok {
+		return "", nil, errs.NewValidationError("policy bundle must be a .tar.gz or .zip archive", nil)
+	}
+
+	unpacker := &bundleUnpacker{
+		files:      make(map[string][]byte),
+		maxEntries: limits.MaxEntries,
+		remaining:  limits.MaxUnpackedSize,
+		maxSize:    limits.MaxUnpackedSize,
+	}
+	var err error
+	if format == BundleZip {
+		err = unpacker.unzip(archive)
+	} else {
+		err = unpacker.untar(archive)
+	}
+	if err != nil {
+		var appErr *errs.AppError
+		if errors.As(err, &appErr) {
+			return "", nil, appErr
+		}
+		return "", nil, errs.NewValidationError("invalid policy bundle", map[string]any{"error": err.Error()})
+	}
+
+	return format, layoutFiles(stripBundleRoot(unpacker.files)), nil
+}
+
+// BundleContentDigest returns the artifact digest bundle signatures cover. A
+// signature.json inside the archive cannot sign the archive's own digest, so
+// it signs the files instead: the SHA-256 of a sha256sum style listing with a
+// "<hex>  <path>\n" line per layout file other than signature.json, sorted by path.
+func BundleContentDigest(files map[string][]byte) string {
+	files = layoutFiles(files)
+	paths := make([]string, 0, len(files))
+	for name := range files {
+		if name != sourceSignatureFile {
+			paths = append(paths, name)
+		}
+	}
+	sort.Strings(paths)
+
+	listing := sha256.New()
+	for _, name := range paths {
+		sum := sha256.Sum256(files[name])
+		fmt.Fprintf(listing, "%s  %s\n", hex.EncodeToString(sum[:]), name)
+	}
+	return policy.FormatDigest(listing.Sum(nil))
+}
+
+// signedBundleDigest returns the content digest of a stored bundle
+func (s *Service) signedBundleDigest(ctx context.Context, id int64) (string, error) {
+	archive, err := s.bundles.GetBundleArchive(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to read policy bundle", zap.Int64("bundle_id", id), zap.Error(err))
+		return "", errs.SanitizeDatabaseError("read policy bundle")
+	}
+	if archive == nil {
+		return "", fmt.Errorf("policy bundle %d not found", id)
+	}
+	_, files, err := s.unpackBundle(archive)
+	if err != nil {
+		return "", err
+	}
+	return BundleContentDigest(files), nil
+}
+
+// SubmitBundle stores an opened bundle and queues a sync of it. The job reads
+// the definition and docs from the stored bundle; the archive and assets are
+// served by the hub under the version's public URL.
+func (s *Service) SubmitBundle(ctx context.Context, bundle *Bundle) (*Job, error) {
+	id, err := s.bundles.CreateBundle(ctx, bundle)
+	if err != nil {
+		s.logger.Error("Failed to store policy bundle",
+			zap.String("policy", bundle.PolicyName),
+			zap.String("version", bundle.Version),
+			zap.Error(err))
+		return nil, errs.SanitizeDatabaseError("store policy bundle")
+	}
+
+	// Must match the public bundle routes in the router
+	versionURL := s.publicURL + "/api/v1/policies/" + url.PathEscape(bundle.PolicyName) + "/versions/" + url.PathEscape(bundle.Version)
+	req := &SyncRequest{
+		PolicyName:    bundle.PolicyName,
+		Version:       bundle.Version,
+		SourceType:    SourceTypeBundle,
+		SourceURL:     versionURL + "/bundle",
+		DefinitionURL: bundleURL(id, sourceDefinitionFile),
+		Metadata:      bundle.Metadata,
+		Documentation: make(map[string]string),
+		Signature:     bundle.Signature,
+		BundleID:      &id,
+	}
+	for file := range bundle.Files {
+		if page, ok := strings.CutPrefix(file, sourceDocsDir+"/"); ok {
+			req.Documentation[strings.TrimSuffix(page, ".md")] = bundleURL(id, file)
+		} else if strings.HasPrefix(file, sourceAssetsDir+"/") {
+			req.AssetsBaseURL = versionURL + "/" + sourceAssetsDir + "/"
+		}
+	}
+
+	job, err := s.submit(ctx, req, "")
+	if err != nil {
+		// Nothing reads the bundle now; the orphan cleanup catches failed deletes
+		if deleteErr := s.bundles.DeleteBundle(context.WithoutCancel(ctx), id); deleteErr != nil {
+			s.logger.Warn("Failed to delete unused policy bundle", zap.Int64("bundle_id", id), zap.Error(deleteErr))
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+// GetBundleArchive returns the archive a version was published from
+func (s *Service) GetBundleArchive(ctx context.Context, name, version string) (*BundleArchive, error) {
+	policyVersion, err := s.policyService.GetPolicyVersion(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+	if policyVersion.BundleID == nil {
+		return nil, errs.BundleNotFound(name, version)
+	}
+
+	archive, err := s.bundles.GetBundleArchive(ctx, *policyVersion.BundleID)
+	if err != nil {
+		s.logger.Error("Failed to read policy bundle", zap.Int64("bundle_id", *policyVersion.BundleID), zap.Error(err))
+		return nil, errs.SanitizeDatabaseError("read policy bundle")
+	}
+	format, ok := detectBundleFormat(archive)
+	if !ok {
+		return nil, errs.BundleNotFound(name, version)
+	}
+
+	result := &BundleArchive{Format: format, Data: archive}
+	if policyVersion.ArtifactDigest != nil {
+		result.Digest = *policyVersion.ArtifactDigest
+	}
+	s.policyService.RecordDownload(ctx, name)
+	return result, nil
+}
+
+// GetBundleAsset returns a file under assets/ in the bundle a version was published from
+func (s *Service) GetBundleAsset(ctx context.Context, name, version, assetPath string) ([]byte, error) {
+	if !fs.ValidPath(assetPath) || assetPath == "." {
+		return nil, errs.AssetNotFound(name, version, assetPath)
+	}
+
+	policyVersion, err := s.policyService.GetPolicyVersion(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+	if policyVersion.BundleID == nil {
+		return nil, errs.AssetNotFound(name, version, assetPath)
+	}
+
+	content, err := s.bundles.GetBundleFile(ctx, *policyVersion.BundleID, sourceAssetsDir+"/"+assetPath)
+	if err != nil {
+		s.logger.Error("Failed to read policy bundle file", zap.Int64("bundle_id", *policyVersion.BundleID), zap.Error(err))
+		return nil, errs.SanitizeDatabaseError("read policy bundle asset")
+	}
+	if content == nil {
+		return nil, errs.AssetNotFound(name, version, assetPath)
+	}
+	return content, nil
+}
+
+// bundleUnpacker collects the files of an archive while enforcing the entry
+// and unpacked size limits. Sizes are counted as content is read, so headers
+// that understate them do not get past the limit.
+type bundleUnpacker struct {
+	files      map[string][]byte
+	entries    int
+	maxEntries int
+	remaining  int64
+	maxSize    int64
+}
+
+// entry checks an archive entry's name and counts it against the entry limit.
+// It returns the cleaned path, or "" for directories.
+func (u *bundleUnpacker) entry(name string, isDir, isRegular bool) (string, error) {
+	u.entries++
+	if u.entries > u.maxEntries {
+		return "", errs.BundleTooLarge("entry count", int64(u.maxEntries))
+	}
+
+	cleaned := strings.TrimSuffix(strings.TrimPrefix(name, "./"), "/")
+	if isDir && (cleaned == "" || cleaned == ".") {
+		return "", nil
+	}
+	if strings.Contains(name, `\`) || !fs.ValidPath(cleaned) || cleaned == "." || len(cleaned) > maxBundlePathLength {
+		return "", fmt.Errorf("entry %q has an invalid path", name)
+	}
+	if isDir {
+		return "", nil
+	}
+	if !isRegular {
+		return "", fmt.Errorf("entry %q is not a regular file or directory", name)
+	}
+	if _, ok := u.files[cleaned]; ok {
+		return "", fmt.Errorf("entry %q appears more than once", name)
+	}
+	return cleaned, nil
+}
+
+// read reads an entry's content, counting it against the unpacked size limit
+func (u *bundleUnpacker) read(name string, r io.Reader) error {
+	content, err := io.ReadAll(io.LimitReader(r, u.remaining+1))
+	if err != nil {
+		return fmt.Errorf("failed to read entry %q: %w", name, err)
+	}
+	if int64(len(content)) > u.remaining {
+		return errs.BundleTooLarge("unpacked size", u.maxSize)
+	}
+	u.remaining -= int64(len(content))
+	u.files[name] = content
+	return nil
+}
+
+func (u *bundleUnpacker) untar(archive []byte) error {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// Global PAX headers, as written by git archive, carry no file
+		if header.Typeflag == tar.TypeXGlobalHeader {
+			continue
+		}
+		// Anything that is not a file or directory (links, devices) is rejected
+		name, err := u.entry(header.Name, header.Typeflag == tar.TypeDir, header.Typeflag == tar.TypeReg)
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			continue
+		}
+		if err := u.read(name, tr); err != nil {
+			return err
+		}
+	}
+}
+
+func (u *bundleUnpacker) unzip(archive []byte) error {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return err
+	}
+	if len(zr.File) > u.maxEntries {
+		return errs.BundleTooLarge("entry count", int64(u.maxEntries))
+	}
+
+	for _, file := range zr.File {
+		mode := file.Mode()
+		name, err := u.entry(file.Name, mode.IsDir(), mode.IsRegular())
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open entry %q: %w", file.Name, err)
+		}
+		err = u.read(name, rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stripBundleRoot removes a single top-level directory that wraps every file,
+// as produced by archiving a version directory rather than its contents
+func stripBundleRoot(files map[string][]byte) map[string][]byte {
+	if _, ok := files[sourceDefinitionFile]; ok {
+		return files
+	}
+
+	root := ""
+	for name := range files {
+		dir, _, found := strings.Cut(name, "/")
+		if !found || (root != "" && dir != root) {
+			return files
+		}
+		root = dir
+	}
+
+	stripped := make(map[string][]byte, len(files))
+	for name, content := range files {
+		stripped[strings.TrimPrefix(name, root+"/")] = content
+	}
+	return stripped
+}
+
+// layoutFiles keeps the files of the version layout: the definition, metadata,
+// signature, known doc pages and everything under assets/
+func layoutFiles(files map[string][]byte) map[string][]byte {
+	validDocTypes := policy.ValidDocTypes()
+	kept := make(map[string][]byte, len(files))
+	for name, content := range files {
+		parts := strings.Split(name, "/")
+		switch {
+		case len(parts) == 1 && (name == sourceDefinitionFile || name == sourceMetadataFile || name == sourceSignatureFile):
+		case len(parts) == 2 && parts[0] == sourceDocsDir && path.Ext(parts[1]) == ".md" && validDocTypes[strings.TrimSuffix(parts[1], ".md")]:
+		case len(parts) > 1 && parts[0] == sourceAssetsDir:
+		default:
+			continue
+		}
+		kept[name] = content
+	}
+	return kept
+}
+
+// bundleTransport serves bundle:// URLs from stored bundles, answering 404 for
+// missing files like an HTTP file server
+type bundleTransport struct {
+	bundles BundleRepository
+}
+
+func (t *bundleTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return nil, fmt.Errorf("method %s not supported for bundle files", req.Method)
+	}
+
+	var data []byte
+	id, err := strconv.ParseInt(req.URL.Host, 10, 64)
+	if err == nil {
+		if name := strings.TrimPrefix(req.URL.Path, "/"); name == "" {
+			data, err = t.bundles.GetBundleArchive(req.Context(), id)
+		} else {
+			data, err = t.bundles.GetBundleFile(req.Context(), id, name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	status := http.StatusOK
+	if data == nil {
+		status = http.StatusNotFound
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        make(http.Header),
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}, nil
+}
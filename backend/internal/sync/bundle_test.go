@@ -0,0 +1,336 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package sync
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/wso2/policyhub/internal/config"
+	"github.com/wso2/policyhub/internal/errs"
+)
+
+// bundleEntry is a file, directory or symlink written into a test archive
+type bundleEntry struct {
+	name    string
+	content string
+	mode    byte // tar type flag; regular file when zero
+}
+
+func file(name, content string) bundleEntry {
+	return bundleEntry{name: name, content: content}
+}
+
+func tarGz(t *testing.T, entries ...bundleEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Mode: 0o644, Typeflag: entry.mode, Size: int64(len(entry.content))}
+		switch entry.mode {
+		case 0:
+			header.Typeflag = tar.TypeReg
+		case tar.TypeSymlink, tar.TypeLink:
+			header.Linkname, header.Size = entry.content, 0
+		case tar.TypeDir:
+			header.Size = 0
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if header.Size > 0 {
+			if _, err := tw.Write([]byte(entry.content)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func zipArchive(t *testing.T, entries ...bundleEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, entry := range entries {
+		w, err := zw.Create(entry.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(entry.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func newBundleService(limits config.SyncBundleConfig) *Service {
+	return &Service{cfg: &config.SyncConfig{Bundle: limits}}
+}
+
+var defaultBundleLimits = config.SyncBundleConfig{MaxSize: 1 << 20, MaxUnpackedSize: 1 << 20, MaxEntries: 50}
+
+const (
+	testDefinition = "name: rate-limit\nversion: 1.2.0\n"
+	testMetadata   = `{"displayName": "Rate Limit", "provider": "WSO2"}`
+)
+
+func TestOpenBundle(t *testing.T) {
+	entries := []bundleEntry{
+		{name: "rate-limit-1.2.0/", mode: tar.TypeDir},
+		file("rate-limit-1.2.0/policy-definition.yml", testDefinition),
+		file("rate-limit-1.2.0/metadata.json", testMetadata),
+		file("rate-limit-1.2.0/signature.json", `{"keyId": "wso2-release", "value": "c2lnbmF0dXJl"}`),
+		file("rate-limit-1.2.0/docs/overview.md", "# Rate Limit"),
+		file("rate-limit-1.2.0/docs/notes.md", "not a doc page"),
+		file("rate-limit-1.2.0/assets/images/logo.png", "png"),
+		file("rate-limit-1.2.0/README.md", "ignored"),
+	}
+	archives := map[BundleFormat][]byte{
+		BundleTarGz: tarGz(t, entries...),
+		BundleZip:   zipArchive(t, entries[1:]...),
+	}
+
+	s := newBundleService(defaultBundleLimits)
+	for format, archive := range archives {
+		t.Run(string(format), func(t *testing.T) {
+			bundle, err := s.OpenBundle("rate-limit", "1.2.0", archive)
+			if err != nil {
+				t.Fatalf("OpenBundle() error = %v", err)
+			}
+			if bundle.Format != format {
+				t.Errorf("format = %s, want %s", bundle.Format, format)
+			}
+
+			want := []string{"assets/images/logo.png", "docs/overview.md", "metadata.json", "policy-definition.yml", "signature.json"}
+			if got := sortedKeys(bundle.Files); strings.Join(got, " ") != strings.Join(want, " ") {
+				t.Errorf("files = %v, want %v", got, want)
+			}
+			if string(bundle.Files["policy-definition.yml"]) != testDefinition {
+				t.Errorf("definition = %q", bundle.Files["policy-definition.yml"])
+			}
+			if bundle.Metadata.Provider != "WSO2" || bundle.Metadata.DisplayName != "Rate Limit" {
+				t.Errorf("metadata = %+v", bundle.Metadata)
+			}
+			if bundle.Signature == nil || bundle.Signature.KeyID != "wso2-release" {
+				t.Errorf("signature = %+v, want key wso2-release", bundle.Signature)
+			}
+		})
+	}
+}
+
+func TestOpenBundleLimits(t *testing.T) {
+	layout := []bundleEntry{file("policy-definition.yml", testDefinition), file("metadata.json", testMetadata)}
+	many := append([]bundleEntry{}, layout...)
+	for i := 0; i < 10; i++ {
+		many = append(many, file("assets/"+strings.Repeat("a", i+1), "x"))
+	}
+	large := append([]bundleEntry{}, layout...)
+	large = append(large, file("assets/large.bin", strings.Repeat("x", 600)), file("assets/other.bin", strings.Repeat("y", 600)))
+
+	limits := config.SyncBundleConfig{MaxSize: 8000, MaxUnpackedSize: 1000, MaxEntries: 5}
+	tests := []struct {
+		name    string
+		archive []byte
+		limit   string
+	}{
+		{"tar entry count", tarGz(t, many...), "entry count"},
+		{"zip entry count", zipArchive(t, many...), "entry count"},
+		{"tar directories count as entries", tarGz(t, append(layout,
+			bundleEntry{name: "a/", mode: tar.TypeDir}, bundleEntry{name: "b/", mode: tar.TypeDir},
+			bundleEntry{name: "c/", mode: tar.TypeDir}, bundleEntry{name: "d/", mode: tar.TypeDir})...), "entry count"},
+		{"tar unpacked size", tarGz(t, large...), "unpacked size"},
+		{"zip unpacked size", zipArchive(t, large...), "unpacked size"},
+		{"archive size", append(tarGz(t, layout...), make([]byte, 10000)...), "size"},
+	}
+
+	s := newBundleService(limits)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.OpenBundle("rate-limit", "1.2.0", tt.archive)
+			appErr, ok := err.(*errs.AppError)
+			if !ok || appErr.Code != errs.CodeBundleTooLarge {
+				t.Fatalf("OpenBundle() error = %v, want %s", err, errs.CodeBundleTooLarge)
+			}
+			if appErr.Details["limit"] != tt.limit {
+				t.Errorf("limit = %v, want %s", appErr.Details["limit"], tt.limit)
+			}
+		})
+	}
+}
+
+func TestOpenBundleRejectsUnsafeEntries(t *testing.T) {
+	layout := []bundleEntry{file("policy-definition.yml", testDefinition), file("metadata.json", testMetadata)}
+	tests := []struct {
+		name    string
+		entry   bundleEntry
+		message string
+	}{
+		{"parent directory", file("../evil.txt", "x"), "invalid path"},
+		{"nested parent directory", file("assets/../../evil.txt", "x"), "invalid path"},
+		{"absolute path", file("/etc/passwd", "x"), "invalid path"},
+		{"backslash", file(`assets\..\evil.txt`, "x"), "invalid path"},
+		{"too long path", file("assets/"+strings.Repeat("a", maxBundlePathLength), "x"), "invalid path"},
+		{"duplicate entry", file("metadata.json", testMetadata), "more than once"},
+		{"symlink", bundleEntry{name: "assets/link", content: "/etc/passwd", mode: tar.TypeSymlink}, "not a regular file"},
+		{"hard link", bundleEntry{name: "assets/link", content: "metadata.json", mode: tar.TypeLink}, "not a regular file"},
+	}
+
+	s := newBundleService(defaultBundleLimits)
+	for _, tt := range tests {
+		archives := map[string][]byte{"tar.gz": tarGz(t, append(layout, tt.entry)...)}
+		if tt.entry.mode == 0 {
+			archives["zip"] = zipArchive(t, append(layout, tt.entry)...)
+		}
+		for format, archive := range archives {
+			t.Run(tt.name+" "+format, func(t *testing.T) {
+				_, err := s.OpenBundle("rate-limit", "1.2.0", archive)
+				appErr, ok := err.(*errs.AppError)
+				if !ok || appErr.Code != errs.CodeValidationError {
+					t.Fatalf("OpenBundle() error = %v, want %s", err, errs.CodeValidationError)
+				}
+				if detail, _ := appErr.Details["error"].(string); !strings.Contains(detail, tt.message) {
+					t.Errorf("error detail = %q, want it to contain %q", detail, tt.message)
+				}
+			})
+		}
+	}
+}
+
+func TestOpenBundleInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		archive []byte
+		message string
+	}{
+		{"not an archive", []byte("policy-definition.yml"), "must be a .tar.gz or .zip archive"},
+		{"corrupt gzip", append([]byte{0x1f, 0x8b}, "not gzip"...), "invalid policy bundle"},
+		{"missing definition", tarGz(t, file("metadata.json", testMetadata)), "no policy-definition.yml"},
+		{"missing metadata", zipArchive(t, file("policy-definition.yml", testDefinition)), "no metadata.json"},
+		{"invalid metadata", tarGz(t, file("policy-definition.yml", testDefinition), file("metadata.json", "{")), "invalid metadata.json"},
+		{"metadata without provider", tarGz(t, file("policy-definition.yml", testDefinition), file("metadata.json", `{"displayName": "Rate Limit"}`)), "requires a displayName and a provider"},
+		{"invalid signature", tarGz(t, file("policy-definition.yml", testDefinition), file("metadata.json", testMetadata), file("signature.json", "[]")), "invalid signature.json"},
+		// Two top-level directories are not stripped, so the definition is not at the root
+		{"two roots", tarGz(t, file("a/policy-definition.yml", testDefinition), file("b/metadata.json", testMetadata)), "no policy-definition.yml"},
+	}
+
+	s := newBundleService(defaultBundleLimits)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.OpenBundle("rate-limit", "1.2.0", tt.archive)
+			appErr, ok := err.(*errs.AppError)
+			if !ok || appErr.Code != errs.CodeValidationError {
+				t.Fatalf("OpenBundle() error = %v, want %s", err, errs.CodeValidationError)
+			}
+			if !strings.Contains(appErr.Message, tt.message) {
+				t.Errorf("message = %q, want it to contain %q", appErr.Message, tt.message)
+			}
+		})
+	}
+}
+
+func TestStripBundleRoot(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  []string
+	}{
+		{"root", []string{"policy-definition.yml", "docs/overview.md"}, []string{"docs/overview.md", "policy-definition.yml"}},
+		{"wrapped", []string{"v1/policy-definition.yml", "v1/docs/overview.md"}, []string{"docs/overview.md", "policy-definition.yml"}},
+		{"two roots", []string{"a/policy-definition.yml", "b/metadata.json"}, []string{"a/policy-definition.yml", "b/metadata.json"}},
+		{"file beside root", []string{"v1/policy-definition.yml", "README.md"}, []string{"README.md", "v1/policy-definition.yml"}},
+	}
+	for _, tt := range tests {
+		files := make(map[string][]byte)
+		for _, name := range tt.files {
+			files[name] = nil
+		}
+		if got := sortedKeys(stripBundleRoot(files)); strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("%s: stripBundleRoot() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBundleContentDigest(t *testing.T) {
+	files := map[string][]byte{
+		"policy-definition.yml":  []byte(testDefinition),
+		"metadata.json":          []byte(testMetadata),
+		"docs/overview.md":       []byte("# Rate Limit\n"),
+		"assets/images/logo.png": {0x89, 'P', 'N', 'G'},
+	}
+	// The digest of "sha256sum <files sorted by path> | sha256sum"
+	var listing strings.Builder
+	for _, name := range sortedKeys(files) {
+		sum := sha256.Sum256(files[name])
+		listing.WriteString(hex.EncodeToString(sum[:]) + "  " + name + "\n")
+	}
+	sum := sha256.Sum256([]byte(listing.String()))
+	want := "sha256:" + hex.EncodeToString(sum[:])
+
+	if got := BundleContentDigest(files); got != want {
+		t.Errorf("BundleContentDigest() = %s, want %s", got, want)
+	}
+
+	// The signature and files outside the version layout are not signed
+	extra := map[string][]byte{
+		"signature.json":    []byte(`{"keyId":"k","value":"c2ln"}`),
+		"README.md":         []byte("readme"),
+		"docs/notes.md":     []byte("notes"),
+		"docs/overview.txt": []byte("overview"),
+	}
+	for name, content := range files {
+		extra[name] = content
+	}
+	if got := BundleContentDigest(extra); got != want {
+		t.Errorf("BundleContentDigest() with files outside the layout = %s, want %s", got, want)
+	}
+
+	for name := range files {
+		changed := make(map[string][]byte, len(files))
+		for other, content := range files {
+			changed[other] = content
+		}
+		changed[name] = append([]byte("x"), files[name]...)
+		if BundleContentDigest(changed) == want {
+			t.Errorf("changing %s kept the digest", name)
+		}
+
+		delete(changed, name)
+		changed["assets/"+name] = files[name]
+		if BundleContentDigest(changed) == want {
+			t.Errorf("moving %s kept the digest", name)
+		}
+	}
+}
+
+func sortedKeys(files map[string][]byte) []string {
+	keys := make([]string, 0, len(files))
+	for name := range files {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,228 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	gosync "sync"
+	"time"
+
+	"github.com/wso2/policyhub/internal/errs"
+	"github.com/wso2/policyhub/internal/policy"
+	"go.uber.org/zap"
+)
+
+// fetchDocs fetches the documentation pages of a sync, a bounded number at a
+// time, and reports the outcome of each page. Pages that do not exist or
+// cannot be fetched are left out unless they are required, which fails the
+// sync, as does any page the hub refuses to fetch.
+func (s *Service) fetchDocs(ctx context.Context, req *SyncRequest) ([]*policy.PolicyDoc, []DocResult, error) {
+	required := make(map[string]bool, len(req.RequireDocs))
+	for _, page := range req.RequireDocs {
+		required[page] = true
+	}
+
+	pages := make([]string, 0, len(req.Documentation))
+	for page := range req.Documentation {
+		pages = append(pages, page)
+	}
+	sort.Strings(pages)
+
+	// A page that fails the sync stops the fetches still running
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		contents = make([]string, len(pages))
+		results  = make([]DocResult, len(pages))
+		slots    = make(chan struct{}, s.cfg.Docs.Concurrency)
+		wg       gosync.WaitGroup
+		mu       gosync.Mutex
+		fatal    error
+	)
+	for i, page := range pages {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			docURL := req.Documentation[page]
+			result := &results[i]
+			result.Page = page
+			content, err := s.fetchDoc(ctx, docURL, result)
+			if err == nil && required[page] && strings.TrimSpace(content) == "" {
+				err = fmt.Errorf("page is empty")
+				result.Status, result.Error = DocFailed, err.Error()
+			}
+			contents[i] = content
+
+			var appErr *errs.AppError
+			switch {
+			case err == nil:
+				s.logger.Debug("Fetched doc page", zap.String("docType", page), zap.Bool("cached", result.Cached))
+				return
+			case isRejected(err):
+				// Refused fetches fail the sync even for optional pages
+				appErr = fetchError(docURL, err)
+			case required[page]:
+				appErr = errs.SyncFetchFailed(docURL, fmt.Errorf("required doc page %s: %w", page, err))
+			case result.Status == DocSkipped:
+				s.logger.Debug("Doc page not found", zap.String("docType", page), zap.String("path", docURL), zap.Error(err))
+				return
+			default:
+				s.logger.Warn("Failed to fetch doc page", zap.String("docType", page), zap.String("path", docURL),
+					zap.Int("attempts", result.Attempts), zap.Error(err))
+				return
+			}
+
+			mu.Lock()
+			if fatal == nil {
+				fatal = appErr
+			}
+			mu.Unlock()
+			cancel()
+		}()
+	}
+	wg.Wait()
+
+	if fatal != nil {
+		return nil, nil, fatal
+	}
+
+	docs := make([]*policy.PolicyDoc, 0, len(pages))
+	for i, page := range pages {
+		if results[i].Status != DocSynced {
+			continue
+		}
+		docs = append(docs, &policy.PolicyDoc{
+			Page:      page,
+			ContentMd: s.rewriteImageReferences(contents[i], req.AssetsBaseURL, nil),
+		})
+	}
+	return docs, results, nil
+}
+
+// fetchDoc fetches a doc page, retrying network errors and 429 and 5xx
+// responses with exponential backoff, and records the attempts and outcome in
+// result. Remote pages a previous sync cached are requested conditionally.
+func (s *Service) fetchDoc(ctx context.Context, docURL string, result *DocResult) (string, error) {
+	cached := s.cachedDoc(ctx, docURL)
+	var etag string
+	if cached != nil {
+		etag = cached.ETag
+	}
+
+	delay := s.cfg.Docs.RetryDelay
+	for {
+		result.Attempts++
+		doc, notModified, err := s.fetchMarkdown(ctx, docURL, etag)
+		if err == nil {
+			if notModified {
+				doc, result.Cached = cached, true
+			}
+			if doc.ETag != "" && isRemoteURL(docURL) {
+				s.cacheDoc(ctx, docURL, doc)
+			}
+			result.Status = DocSynced
+			return doc.Content, nil
+		}
+
+		if result.Attempts > s.cfg.Docs.Retries || !isTransientFetch(err) || ctx.Err() != nil {
+			result.Status, result.Error = DocFailed, err.Error()
+			var status *statusError
+			if errors.As(err, &status) && (status.code == http.StatusNotFound || status.code == http.StatusGone) {
+				result.Status = DocSkipped
+			}
+			return "", err
+		}
+
+		s.logger.Debug("Retrying doc page",
+			zap.String("path", docURL),
+			zap.Int("attempt", result.Attempts),
+			zap.Duration("delay", delay),
+			zap.Error(err))
+		select {
+		case <-ctx.Done():
+			result.Status, result.Error = DocFailed, ctx.Err().Error()
+			return "", ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// fetchMarkdown fetches markdown content from a URL, as a conditional request
+// when etag is set. The returned doc carries the ETag the content was served
+// with; notModified reports a 304 answer, which carries no content.
+func (s *Service) fetchMarkdown(ctx context.Context, url, etag string) (doc *CachedDoc, notModified bool, err error) {
+	resp, err := s.fetchIfNoneMatch(ctx, url, fetchDocKind, etag)
+	if err != nil {
+		return nil, false, err
+	}
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, true, nil
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &CachedDoc{ETag: resp.Header.Get("ETag"), Content: string(body)}, false, nil
+}
+
+// cachedDoc returns the copy of a remote doc page a previous sync cached, if
+// any. Cache failures only cost the conditional request, so they are logged.
+func (s *Service) cachedDoc(ctx context.Context, docURL string) *CachedDoc {
+	if !isRemoteURL(docURL) {
+		return nil
+	}
+	doc, err := s.docCache.GetCachedDoc(ctx, docURL)
+	if err != nil {
+		s.logger.Warn("Failed to read cached doc page", zap.String("path", docURL), zap.Error(err))
+		return nil
+	}
+	return doc
+}
+
+// cacheDoc stores a doc page for the conditional requests of later syncs
+func (s *Service) cacheDoc(ctx context.Context, docURL string, doc *CachedDoc) {
+	if err := s.docCache.PutCachedDoc(ctx, docURL, doc); err != nil {
+		s.logger.Warn("Failed to cache doc page", zap.String("path", docURL), zap.Error(err))
+	}
+}
+
+// isRemoteURL reports whether url is fetched over http(s) rather than from the
+// sync source or an uploaded bundle
+func isRemoteURL(url string) bool {
+	return strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")
+}
+
+// isTransientFetch reports whether a failed fetch may succeed when retried:
+// network errors and 429 and 5xx responses are, refused fetches and other
+// status codes are not
+func isTransientFetch(err error) bool {
+	if isRejected(err) {
+		return false
+	}
+	var status *statusError
+	if errors.As(err, &status) {
+		return status.code == http.StatusTooManyRequests || status.code >= http.StatusInternalServerError
+	}
+	return true
+}
@@ -0,0 +1,316 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/wso2/policyhub/internal/config"
+	"github.com/wso2/policyhub/internal/errs"
+)
+
+// nonPublicPrefixes are special-purpose ranges that netip's predicates do not
+// exclude; loopback, link-local (cloud metadata services included), private,
+// multicast and unspecified addresses are excluded by isPublicAddr itself
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "this" network
+	netip.MustParsePrefix("100.64.0.0/10"),  // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),  // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),    // reserved
+	netip.MustParsePrefix("100::/64"),       // discard-only
+	netip.MustParsePrefix("64:ff9b:1::/48"), // local-use NAT64
+	netip.MustParsePrefix("2001::/32"),      // Teredo, which can embed any IPv4 address
+	netip.MustParsePrefix("2002::/16"),      // 6to4, likewise
+	netip.MustParsePrefix("fec0::/10"),      // deprecated site-local
+}
+
+// fetchKind describes what a fetched file is expected to be
+type fetchKind struct {
+	name string
+	// mediaTypes lists the content types the file may be served with; a
+	// "type/*" entry allows any subtype. Empty allows any type but HTML.
+	mediaTypes []string
+	// maxSize returns the size limit of the file in bytes
+	maxSize func(cfg *config.SyncFetchConfig) int64
+}
+
+var (
+	fetchDefinitionKind = fetchKind{
+		name:       "policy definition",
+		mediaTypes: []string{"application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml", "text/plain", "application/octet-stream"},
+		maxSize:    func(cfg *config.SyncFetchConfig) int64 { return cfg.MaxDefinitionSize },
+	}
+	fetchDocKind = fetchKind{
+		name:       "doc page",
+		mediaTypes: []string{"text/markdown", "text/x-markdown", "text/plain", "application/octet-stream"},
+		maxSize:    func(cfg *config.SyncFetchConfig) int64 { return cfg.MaxDocSize },
+	}
+	fetchImageKind = fetchKind{
+		name:       "image",
+		mediaTypes: []string{"image/*", "application/octet-stream"},
+		maxSize:    func(cfg *config.SyncFetchConfig) int64 { return cfg.MaxAssetSize },
+	}
+	fetchArtifactKind = fetchKind{
+		name:    "artifact",
+		maxSize: func(cfg *config.SyncFetchConfig) int64 { return cfg.MaxArtifactSize },
+	}
+)
+
+// blockedError is a fetch that violates the fetch policy
+type blockedError struct {
+	reason string
+}
+
+func (e *blockedError) Error() string {
+	return e.reason
+}
+
+// tooLargeError is a fetched file over its size limit
+type tooLargeError struct {
+	kind  fetchKind
+	limit int64
+}
+
+func (e *tooLargeError) Error() string {
+	return fmt.Sprintf("%s exceeds %d bytes", e.kind.name, e.limit)
+}
+
+// statusError is a fetch answered with an unexpected status code
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("status code %d", e.code)
+}
+
+// fetchGuard enforces the fetch policy on the connections and redirects of
+// remote fetches. Files served through source:// and bundle:// never reach it.
+type fetchGuard struct {
+	cfg *config.SyncFetchConfig
+}
+
+// newFetchTransport returns a transport that only dials addresses the guard
+// allows. The address is checked after DNS resolution, on every connection, so
+// a host cannot be rebound to a private address between checks. Proxies are
+// not used, since the guard must see the address it connects to.
+func newFetchTransport(guard *fetchGuard) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   guard.checkDial,
+	}).DialContext
+	return transport
+}
+
+// checkDial rejects connections to non-public addresses
+func (g *fetchGuard) checkDial(network, address string, _ syscall.RawConn) error {
+	if g.cfg.AllowPrivateNetworks {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !isPublicAddr(addr) {
+		return &blockedError{reason: fmt.Sprintf("address %s is not a public address", addr)}
+	}
+	return nil
+}
+
+// checkURL rejects remote URLs that are not http(s) or whose host is not allowed
+func (g *fetchGuard) checkURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return &blockedError{reason: fmt.Sprintf("scheme %q is not allowed", u.Scheme)}
+	}
+	if u.User != nil {
+		return &blockedError{reason: "URLs with credentials are not allowed"}
+	}
+	if !g.hostAllowed(u.Hostname()) {
+		return &blockedError{reason: fmt.Sprintf("host %s is not in the allowed hosts", u.Hostname())}
+	}
+	return nil
+}
+
+// checkRedirect applies checkURL to every redirect hop. Redirects can only
+// lead to remote http(s) URLs, never into source:// or bundle:// files.
+func (g *fetchGuard) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) > g.cfg.MaxRedirects {
+		return &blockedError{reason: fmt.Sprintf("more than %d redirects", g.cfg.MaxRedirects)}
+	}
+	return g.checkURL(req.URL)
+}
+
+// hostAllowed matches host against the allowed hosts
+func (g *fetchGuard) hostAllowed(host string) bool {
+	if len(g.cfg.AllowedHosts) == 0 {
+		return true
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, allowed := range g.cfg.AllowedHosts {
+		if domain, ok := strings.CutPrefix(allowed, "*."); ok {
+			if strings.HasSuffix(host, "."+domain) {
+				return true
+			}
+		} else if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// isPublicAddr reports whether addr is a globally reachable unicast address
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// fetch GETs a file the sync reads. Remote URLs are checked against the fetch
+// policy and the response must be 200 with a content type allowed for kind.
+// Responses declaring more than the kind's size limit are refused; otherwise
+// the limit is enforced as the body is read, so callers may stream or read it
+// whole without holding more than the limit in memory.
+func (s *Service) fetch(ctx context.Context, rawURL string, kind fetchKind) (*http.Response, error) {
+	return s.fetchIfNoneMatch(ctx, rawURL, kind, "")
+}
+
+// fetchIfNoneMatch is fetch as a conditional request: when etag is set, a 304
+// Not Modified response is returned, with its body closed, rather than failing
+func (s *Service) fetchIfNoneMatch(ctx context.Context, rawURL string, kind fetchKind, etag string) (*http.Response, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != SourceScheme && u.Scheme != BundleScheme {
+		if err := s.guard.checkURL(u); err != nil {
+			return nil, err
+		}
+	}
+
+	var header http.Header
+	if etag != "" {
+		header = http.Header{"If-None-Match": {etag}}
+	}
+	resp, err := s.get(ctx, rawURL, header)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotModified && etag != "" {
+		resp.Body.Close()
+		return resp, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, &statusError{code: resp.StatusCode}
+	}
+	if err := checkContentType(resp.Header.Get("Content-Type"), kind); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	limit := kind.maxSize(&s.cfg.Fetch)
+	if resp.ContentLength > limit {
+		resp.Body.Close()
+		return nil, &tooLargeError{kind: kind, limit: limit}
+	}
+	resp.Body = &limitedBody{ReadCloser: resp.Body, kind: kind, limit: limit}
+	return resp, nil
+}
+
+// checkContentType rejects a declared content type that kind does not allow.
+// Files served without a content type are accepted.
+func checkContentType(contentType string, kind fetchKind) error {
+	if contentType == "" {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return &blockedError{reason: fmt.Sprintf("invalid content type %q for %s", contentType, kind.name)}
+	}
+	if len(kind.mediaTypes) == 0 {
+		if mediaType == "text/html" || mediaType == "application/xhtml+xml" {
+			return &blockedError{reason: fmt.Sprintf("content type %s is not allowed for %s", mediaType, kind.name)}
+		}
+		return nil
+	}
+	for _, allowed := range kind.mediaTypes {
+		if mediaType == allowed {
+			return nil
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "*"); ok && strings.HasPrefix(mediaType, prefix) {
+			return nil
+		}
+	}
+	return &blockedError{reason: fmt.Sprintf("content type %s is not allowed for %s", mediaType, kind.name)}
+}
+
+// limitedBody fails reads once more than limit bytes have been read
+type limitedBody struct {
+	io.ReadCloser
+	kind  fetchKind
+	limit int64
+	read  int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if b.read > b.limit {
+		return n, &tooLargeError{kind: b.kind, limit: b.limit}
+	}
+	return n, err
+}
+
+// isRejected reports whether err is a fetch the hub refused, rather than one
+// that failed: a fetch policy violation or a file over its size limit
+func isRejected(err error) bool {
+	var blocked *blockedError
+	var tooLarge *tooLargeError
+	return errors.As(err, &blocked) || errors.As(err, &tooLarge)
+}
+
+// fetchError converts a failed fetch into an API error
+func fetchError(url string, err error) *errs.AppError {
+	var blocked *blockedError
+	if errors.As(err, &blocked) {
+		return errs.SyncFetchBlocked(url, blocked.reason)
+	}
+	var tooLarge *tooLargeError
+	if errors.As(err, &tooLarge) {
+		return errs.SyncFetchTooLarge(url, tooLarge.kind.name, tooLarge.limit)
+	}
+	return errs.SyncFetchFailed(url, err)
+}
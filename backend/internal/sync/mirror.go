@@ -0,0 +1,166 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package sync
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/wso2/policyhub/internal/errs"
+	"github.com/wso2/policyhub/internal/policy"
+	"github.com/wso2/policyhub/internal/storage"
+	"go.uber.org/zap"
+)
+
+// blobsPath is the hub route mirrored blobs are served from, by digest
+const blobsPath = "/api/v1/blobs/"
+
+// imagePattern matches markdown images: ![alt](url "optional title")
+var imagePattern = regexp.MustCompile(`!\[(.*?)\]\(([^)\s]+)((?:\s+"[^"]*")?)\)`)
+
+// mirroredFile is a file copied into the blob store
+type mirroredFile struct {
+	digest string
+	url    string // hub URL the copy is served from
+}
+
+// blobURL returns the hub URL of a mirrored blob
+func (s *Service) blobURL(digest string) string {
+	return s.publicURL + blobsPath + digest
+}
+
+// shouldMirror reports whether url is a remote file to copy into the blob
+// store. Files the hub already serves, such as bundle assets, are left alone.
+func (s *Service) shouldMirror(url string) bool {
+	if s.store == nil {
+		return false
+	}
+	if !isRemoteURL(url) {
+		return false
+	}
+	return url != s.publicURL && !strings.HasPrefix(url, s.publicURL+"/")
+}
+
+// mirrorFile downloads url into the blob store under its content digest.
+// The download is spooled to a temporary file so it is hashed before it is
+// stored; blobs that are already stored are not written again.
+func (s *Service) mirrorFile(ctx context.Context, url string, kind fetchKind) (*mirroredFile, error) {
+	resp, err := s.fetch(ctx, url, kind)
+	if err != nil {
+		return nil, fetchError(url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.ContentLength > s.maxObjectSize {
+		return nil, mirrorTooLarge(url, s.maxObjectSize)
+	}
+
+	tmp, err := os.CreateTemp("", "policyhub-mirror-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mirror file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(resp.Body, s.maxObjectSize+1))
+	if err != nil {
+		return nil, fetchError(url, err)
+	}
+	if size > s.maxObjectSize {
+		return nil, mirrorTooLarge(url, s.maxObjectSize)
+	}
+
+	digest := policy.FormatDigest(hash.Sum(nil))
+	key, err := storage.DigestKey(digest)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.store.Stat(ctx, key)
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("failed to read mirror file: %w", err)
+		}
+		if err := s.store.Put(ctx, key, tmp, size, mirrorContentType(resp.Header.Get("Content-Type"))); err != nil {
+			return nil, err
+		}
+		s.logger.Debug("Mirrored file", zap.String("url", url), zap.String("digest", digest), zap.Int64("size", size))
+	case err != nil:
+		return nil, err
+	}
+
+	return &mirroredFile{digest: digest, url: s.blobURL(digest)}, nil
+}
+
+// mirrorAssets mirrors the logo and banner of a release and the images its
+// docs embed. It returns the metadata to store, which points at the mirrored
+// logo and banner; the signed request metadata is left as is. Docs are
+// rewritten in place. Images that cannot be mirrored keep their original URL.
+func (s *Service) mirrorAssets(ctx context.Context, metadata *policy.PolicyMetadata, docs []*policy.PolicyDoc) (*policy.PolicyMetadata, error) {
+	mirrored := *metadata
+	for _, assetURL := range []*string{&mirrored.LogoURL, &mirrored.BannerURL} {
+		if !s.shouldMirror(*assetURL) {
+			continue
+		}
+		file, err := s.mirrorFile(ctx, *assetURL, fetchImageKind)
+		if err != nil {
+			return nil, err
+		}
+		*assetURL = file.url
+	}
+
+	images := make(map[string]string)
+	for _, doc := range docs {
+		for _, match := range imagePattern.FindAllStringSubmatch(doc.ContentMd, -1) {
+			imageURL := match[2]
+			if _, seen := images[imageURL]; seen || !s.shouldMirror(imageURL) {
+				continue
+			}
+			file, err := s.mirrorFile(ctx, imageURL, fetchImageKind)
+			if err != nil {
+				s.logger.Warn("Failed to mirror doc image", zap.String("url", imageURL), zap.Error(err))
+				images[imageURL] = imageURL
+				continue
+			}
+			images[imageURL] = file.url
+		}
+	}
+	for _, doc := range docs {
+		doc.ContentMd = s.rewriteImageReferences(doc.ContentMd, "", images)
+	}
+
+	return &mirrored, nil
+}
+
+// mirrorContentType normalizes the content type a mirrored file was served with
+func mirrorContentType(contentType string) string {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "application/octet-stream"
+	}
+	return mime.FormatMediaType(mediaType, params)
+}
+
+// mirrorTooLarge creates an error for a file over the blob store's object size limit
+func mirrorTooLarge(url string, max int64) *errs.AppError {
+	return errs.NewValidationError("file exceeds the storage object size limit", map[string]any{
+		"url": url,
+		"max": max,
+	})
+}
@@ -9,23 +9,147 @@
 
 package sync
 
-import "github.com/wso2/policyhub/internal/policy"
+import (
+	"time"
 
-// SyncRequest represents a policy sync request
+	"github.com/wso2/policyhub/internal/policy"
+	"github.com/wso2/policyhub/internal/signing"
+)
+
+// SyncRequest represents a policy sync request. It is persisted with its sync job.
 type SyncRequest struct {
-	PolicyName    string
-	Version       string
-	SourceType    string
-	SourceURL     string
-	DefinitionURL string
-	Metadata      *policy.PolicyMetadata
-	Documentation map[string]string
-	AssetsBaseURL string
+	PolicyName    string                 `json:"policyName"`
+	Version       string                 `json:"version"`
+	SourceType    string                 `json:"sourceType"`
+	SourceURL     string                 `json:"downloadUrl"`
+	DefinitionURL string                 `json:"definitionUrl"`
+	Metadata      *policy.PolicyMetadata `json:"metadata"`
+	Documentation map[string]string      `json:"documentation,omitempty"`
+	RequireDocs   []string               `json:"requireDocs,omitempty"` // doc pages that must be fetched for the sync to succeed
+	AssetsBaseURL string                 `json:"assetsBaseUrl,omitempty"`
+	Signature     *signing.Signature     `json:"signature,omitempty"` // detached signature over the release payload, see signing.Payload
+	BundleID      *int64                 `json:"bundleId,omitempty"`  // uploaded bundle the request's bundle:// URLs point into
 }
 
 // SyncResult represents the result of a sync operation
 type SyncResult struct {
-	PolicyName string
-	Version    string
-	Status     string
+	PolicyName       string                 `json:"policyName"`
+	Version          string                 `json:"version"`
+	Status           string                 `json:"status"`
+	DefinitionDigest string                 `json:"definitionDigest"`
+	ArtifactDigest   string                 `json:"artifactDigest"`
+	SignatureStatus  policy.SignatureStatus `json:"signatureStatus"`
+	Warning          string                 `json:"warning,omitempty"`
+	Docs             []DocResult            `json:"docs,omitempty"` // outcome of each requested doc page, by page name
+}
+
+// DocStatus is the outcome of fetching a doc page
+type DocStatus string
+
+const (
+	DocSynced  DocStatus = "synced"
+	DocSkipped DocStatus = "skipped" // the page does not exist
+	DocFailed  DocStatus = "failed"  // the page could not be fetched after retries
+)
+
+// DocResult reports how a doc page of a sync was fetched
+type DocResult struct {
+	Page     string    `json:"page"`
+	Status   DocStatus `json:"status"`
+	Cached   bool      `json:"cached,omitempty"` // unchanged since a previous sync, per a conditional request
+	Attempts int       `json:"attempts"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// CachedDoc is a doc page kept for conditional requests
+type CachedDoc struct {
+	ETag    string
+	Content string
+}
+
+// verifiedSignature is a release signature that passed verification
+type verifiedSignature struct {
+	keyID      string
+	value      string
+	verifiedAt time.Time
+}
+
+// JobStatus is the state of a sync job
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+// StepStatus is the state of a single step of a sync attempt
+type StepStatus string
+
+const (
+	StepPending   StepStatus = "pending"
+	StepRunning   StepStatus = "running"
+	StepSucceeded StepStatus = "succeeded"
+	StepFailed    StepStatus = "failed"
+	StepSkipped   StepStatus = "skipped"
+)
+
+// Sync steps, in the order they run
+const (
+	StepFetchDefinition = "fetch_definition"
+	StepFetchArtifact   = "fetch_artifact"
+	StepVerifySignature = "verify_signature"
+	StepFetchDocs       = "fetch_docs"
+	StepMirrorAssets    = "mirror_assets"
+	StepStoreVersion    = "store_version"
+)
+
+// Steps lists the sync steps in the order they run. Nothing is visible before
+// store_version, which writes the version and its docs in one transaction;
+// blobs mirrored by earlier steps are only referenced once it succeeds.
+func Steps() []string {
+	return []string{StepFetchDefinition, StepFetchArtifact, StepVerifySignature, StepFetchDocs, StepMirrorAssets, StepStoreVersion}
+}
+
+// ProgressFunc is called as each sync step changes state; err is set for failed steps
+type ProgressFunc func(step string, status StepStatus, err error)
+
+// JobStep is the progress of one step in the current attempt of a job
+type JobStep struct {
+	Name       string     `json:"name"`
+	Status     StepStatus `json:"status"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// JobError is the error of the most recent failed attempt of a job
+type JobError struct {
+	Code    string         `json:"code"`
+	Message string         `json:"message"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+// Job is a sync request queued for, or processed by, the sync workers
+type Job struct {
+	ID          int64
+	PolicyName  string
+	Version     string
+	Provider    string
+	Request     *SyncRequest
+	Status      JobStatus
+	Attempts    int
+	MaxAttempts int
+	Steps       []JobStep
+	Result      *SyncResult
+	LastError   *JobError
+	NextRunAt   time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	StartedAt   *time.Time
+	FinishedAt  *time.Time
+	// SourceRevision is the revision of the source files a scan queued the job
+	// from; it is empty for pushed and uploaded syncs
+	SourceRevision string
 }
@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package sync
+
+import (
+	"context"
+	"time"
+)
+
+// JobRepository defines persistence for sync jobs. Updates to a claimed job
+// take the attempt number it was claimed with and are ignored once another
+// worker has claimed the job again.
+type JobRepository interface {
+	CreateJob(ctx context.Context, job *Job) (*Job, error)
+	GetJob(ctx context.Context, id int64) (*Job, error)
+	GetActiveJob(ctx context.Context, name, version string) (*Job, error)
+	// ListLatestJobs returns the most recent job of each version of the given policies
+	ListLatestJobs(ctx context.Context, names []string) ([]*Job, error)
+
+	// ClaimJob leases the next due job to the caller; it returns nil when no job is due
+	ClaimJob(ctx context.Context, lease time.Duration) (*Job, error)
+	UpdateJobSteps(ctx context.Context, id int64, attempt int, steps []JobStep) error
+	CompleteJob(ctx context.Context, id int64, attempt int, result *SyncResult) error
+	RetryJob(ctx context.Context, id int64, attempt int, jobErr *JobError, delay time.Duration) error
+	FailJob(ctx context.Context, id int64, attempt int, jobErr *JobError) error
+	ReleaseJob(ctx context.Context, id int64, attempt int) error
+	FailAbandonedJobs(ctx context.Context, jobErr *JobError) (int64, error)
+	// DeleteFinishedJobs deletes succeeded and failed jobs that finished more than minAge ago
+	DeleteFinishedJobs(ctx context.Context, minAge time.Duration) (int64, error)
+}
+
+// BundleRepository defines persistence for uploaded policy bundles
+type BundleRepository interface {
+	// CreateBundle stores a bundle archive and its unpacked files in one transaction
+	CreateBundle(ctx context.Context, bundle *Bundle) (int64, error)
+	// GetBundleArchive and GetBundleFile return nil when the bundle or file does not exist
+	GetBundleArchive(ctx context.Context, id int64) ([]byte, error)
+	GetBundleFile(ctx context.Context, id int64, path string) ([]byte, error)
+	// DeleteBundle deletes a bundle unless a version was published from it
+	DeleteBundle(ctx context.Context, id int64) error
+	// DeleteOrphanedBundles deletes bundles older than minAge that no version
+	// was published from and no unfinished job reads
+	DeleteOrphanedBundles(ctx context.Context, minAge time.Duration) (int64, error)
+}
+
+// DocCacheRepository defines persistence for doc pages cached by URL for
+// conditional requests
+type DocCacheRepository interface {
+	// GetCachedDoc returns nil when the URL has not been cached
+	GetCachedDoc(ctx context.Context, url string) (*CachedDoc, error)
+	// PutCachedDoc stores a page, or marks an unchanged one as used
+	PutCachedDoc(ctx context.Context, url string, doc *CachedDoc) error
+	// DeleteStaleDocs deletes pages no sync has used for maxAge
+	DeleteStaleDocs(ctx context.Context, maxAge time.Duration) (int64, error)
+}
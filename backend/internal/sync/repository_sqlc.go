@@ -0,0 +1,400 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/wso2/policyhub/internal/db"
+	"github.com/wso2/policyhub/internal/db/sqlc"
+	"github.com/wso2/policyhub/internal/errs"
+)
+
+// SQLCJobRepository implements JobRepository using sqlc-generated code
+type SQLCJobRepository struct {
+	queries *sqlc.Queries
+}
+
+// NewSQLCJobRepository creates a new SQLC-based sync job repository
+func NewSQLCJobRepository(database *db.DB) JobRepository {
+	return &SQLCJobRepository{
+		queries: sqlc.New(database.Pool),
+	}
+}
+
+// Helper to convert pgtype.Timestamptz to *time.Time
+func pgtypeTimestamptzToPtr(ts pgtype.Timestamptz) *time.Time {
+	if ts.Valid {
+		return &ts.Time
+	}
+	return nil
+}
+
+func sqlcToJob(sj sqlc.SyncJob) (*Job, error) {
+	job := &Job{
+		ID:          sj.ID,
+		PolicyName:  sj.PolicyName,
+		Version:     sj.Version,
+		Provider:    sj.Provider,
+		Status:      JobStatus(sj.Status),
+		Attempts:    int(sj.Attempts),
+		MaxAttempts: int(sj.MaxAttempts),
+		NextRunAt:   sj.NextRunAt.Time,
+		CreatedAt:   sj.CreatedAt.Time,
+		UpdatedAt:   sj.UpdatedAt.Time,
+		StartedAt:   pgtypeTimestamptzToPtr(sj.StartedAt),
+		FinishedAt:  pgtypeTimestamptzToPtr(sj.FinishedAt),
+	}
+	if sj.SourceRevision.Valid {
+		job.SourceRevision = sj.SourceRevision.String
+	}
+
+	if err := json.Unmarshal(sj.Request, &job.Request); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal sync request: %w", err)
+	}
+	if err := json.Unmarshal(sj.Steps, &job.Steps); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal steps: %w", err)
+	}
+	if len(sj.Result) > 0 {
+		if err := json.Unmarshal(sj.Result, &job.Result); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal result: %w", err)
+		}
+	}
+	if len(sj.LastError) > 0 {
+		if err := json.Unmarshal(sj.LastError, &job.LastError); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal last error: %w", err)
+		}
+	}
+
+	return job, nil
+}
+
+// CreateJob queues a new job. A unique violation means the version already has an unfinished job.
+func (r *SQLCJobRepository) CreateJob(ctx context.Context, job *Job) (*Job, error) {
+	requestJSON, err := json.Marshal(job.Request)
+	if err != nil {
+		return nil, errs.NewInternalError("failed to marshal sync request", map[string]any{"error": err.Error()})
+	}
+	stepsJSON, err := json.Marshal(job.Steps)
+	if err != nil {
+		return nil, errs.NewInternalError("failed to marshal steps", map[string]any{"error": err.Error()})
+	}
+
+	sj, err := r.queries.InsertSyncJob(ctx, sqlc.InsertSyncJobParams{
+		PolicyName:     job.PolicyName,
+		Version:        job.Version,
+		Provider:       job.Provider,
+		Request:        requestJSON,
+		MaxAttempts:    int32(job.MaxAttempts),
+		Steps:          stepsJSON,
+		SourceRevision: pgtype.Text{String: job.SourceRevision, Valid: job.SourceRevision != ""},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.toJob(sj)
+}
+
+func (r *SQLCJobRepository) GetJob(ctx context.Context, id int64) (*Job, error) {
+	sj, err := r.queries.GetSyncJob(ctx, id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.SyncJobNotFound(id)
+		}
+		return nil, errs.NewDatabaseError("failed to get sync job", map[string]any{"error": err.Error()})
+	}
+	return r.toJob(sj)
+}
+
+func (r *SQLCJobRepository) GetActiveJob(ctx context.Context, name, version string) (*Job, error) {
+	sj, err := r.queries.GetActiveSyncJob(ctx, sqlc.GetActiveSyncJobParams{
+		PolicyName: name,
+		Version:    version,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errs.NewDatabaseError("failed to get active sync job", map[string]any{"error": err.Error()})
+	}
+	return r.toJob(sj)
+}
+
+// ListLatestJobs implements JobRepository
+func (r *SQLCJobRepository) ListLatestJobs(ctx context.Context, names []string) ([]*Job, error) {
+	rows, err := r.queries.ListLatestSyncJobs(ctx, names)
+	if err != nil {
+		return nil, errs.NewDatabaseError("failed to list latest sync jobs", map[string]any{"error": err.Error()})
+	}
+	jobs := make([]*Job, 0, len(rows))
+	for _, row := range rows {
+		job, err := r.toJob(row)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+func (r *SQLCJobRepository) ClaimJob(ctx context.Context, lease time.Duration) (*Job, error) {
+	sj, err := r.queries.ClaimSyncJob(ctx, int32(lease.Seconds()))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errs.NewDatabaseError("failed to claim sync job", map[string]any{"error": err.Error()})
+	}
+	return r.toJob(sj)
+}
+
+func (r *SQLCJobRepository) UpdateJobSteps(ctx context.Context, id int64, attempt int, steps []JobStep) error {
+	stepsJSON, err := json.Marshal(steps)
+	if err != nil {
+		return errs.NewInternalError("failed to marshal steps", map[string]any{"error": err.Error()})
+	}
+	err = r.queries.UpdateSyncJobSteps(ctx, sqlc.UpdateSyncJobStepsParams{
+		Steps:   stepsJSON,
+		ID:      id,
+		Attempt: int32(attempt),
+	})
+	if err != nil {
+		return errs.NewDatabaseError("failed to update sync job steps", map[string]any{"error": err.Error()})
+	}
+	return nil
+}
+
+func (r *SQLCJobRepository) CompleteJob(ctx context.Context, id int64, attempt int, result *SyncResult) error {
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return errs.NewInternalError("failed to marshal sync result", map[string]any{"error": err.Error()})
+	}
+	err = r.queries.CompleteSyncJob(ctx, sqlc.CompleteSyncJobParams{
+		Result:  resultJSON,
+		ID:      id,
+		Attempt: int32(attempt),
+	})
+	if err != nil {
+		return errs.NewDatabaseError("failed to complete sync job", map[string]any{"error": err.Error()})
+	}
+	return nil
+}
+
+func (r *SQLCJobRepository) RetryJob(ctx context.Context, id int64, attempt int, jobErr *JobError, delay time.Duration) error {
+	errorJSON, err := json.Marshal(jobErr)
+	if err != nil {
+		return errs.NewInternalError("failed to marshal sync job error", map[string]any{"error": err.Error()})
+	}
+	err = r.queries.RetrySyncJob(ctx, sqlc.RetrySyncJobParams{
+		LastError:    errorJSON,
+		DelaySeconds: int32(delay.Seconds()),
+		ID:           id,
+		Attempt:      int32(attempt),
+	})
+	if err != nil {
+		return errs.NewDatabaseError("failed to reschedule sync job", map[string]any{"error": err.Error()})
+	}
+	return nil
+}
+
+func (r *SQLCJobRepository) FailJob(ctx context.Context, id int64, attempt int, jobErr *JobError) error {
+	errorJSON, err := json.Marshal(jobErr)
+	if err != nil {
+		return errs.NewInternalError("failed to marshal sync job error", map[string]any{"error": err.Error()})
+	}
+	err = r.queries.FailSyncJob(ctx, sqlc.FailSyncJobParams{
+		LastError: errorJSON,
+		ID:        id,
+		Attempt:   int32(attempt),
+	})
+	if err != nil {
+		return errs.NewDatabaseError("failed to fail sync job", map[string]any{"error": err.Error()})
+	}
+	return nil
+}
+
+func (r *SQLCJobRepository) ReleaseJob(ctx context.Context, id int64, attempt int) error {
+	err := r.queries.ReleaseSyncJob(ctx, sqlc.ReleaseSyncJobParams{
+		ID:      id,
+		Attempt: int32(attempt),
+	})
+	if err != nil {
+		return errs.NewDatabaseError("failed to release sync job", map[string]any{"error": err.Error()})
+	}
+	return nil
+}
+
+func (r *SQLCJobRepository) FailAbandonedJobs(ctx context.Context, jobErr *JobError) (int64, error) {
+	errorJSON, err := json.Marshal(jobErr)
+	if err != nil {
+		return 0, errs.NewInternalError("failed to marshal sync job error", map[string]any{"error": err.Error()})
+	}
+	count, err := r.queries.FailAbandonedSyncJobs(ctx, errorJSON)
+	if err != nil {
+		return 0, errs.NewDatabaseError("failed to fail abandoned sync jobs", map[string]any{"error": err.Error()})
+	}
+	return count, nil
+}
+
+// DeleteFinishedJobs implements JobRepository
+func (r *SQLCJobRepository) DeleteFinishedJobs(ctx context.Context, minAge time.Duration) (int64, error) {
+	count, err := r.queries.DeleteFinishedSyncJobs(ctx, int32(minAge.Seconds()))
+	if err != nil {
+		return 0, errs.NewDatabaseError("failed to delete finished sync jobs", map[string]any{"error": err.Error()})
+	}
+	return count, nil
+}
+
+// toJob maps a job row, reporting undecodable rows as database errors
+func (r *SQLCJobRepository) toJob(sj sqlc.SyncJob) (*Job, error) {
+	job, err := sqlcToJob(sj)
+	if err != nil {
+		return nil, errs.NewDatabaseError("failed to decode sync job", map[string]any{"jobId": sj.ID, "error": err.Error()})
+	}
+	return job, nil
+}
+
+// SQLCBundleRepository implements BundleRepository using sqlc-generated code
+type SQLCBundleRepository struct {
+	db      *db.DB
+	queries *sqlc.Queries
+}
+
+// NewSQLCBundleRepository creates a new SQLC-based policy bundle repository
+func NewSQLCBundleRepository(database *db.DB) BundleRepository {
+	return &SQLCBundleRepository{
+		db:      database,
+		queries: sqlc.New(database.Pool),
+	}
+}
+
+func (r *SQLCBundleRepository) CreateBundle(ctx context.Context, bundle *Bundle) (int64, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, errs.NewDatabaseError("failed to start transaction", map[string]any{"error": err.Error()})
+	}
+	defer tx.Rollback(ctx)
+
+	q := sqlc.New(tx)
+
+	id, err := q.InsertPolicyBundle(ctx, sqlc.InsertPolicyBundleParams{
+		PolicyName: bundle.PolicyName,
+		Version:    bundle.Version,
+		Format:     string(bundle.Format),
+		Archive:    bundle.Archive,
+	})
+	if err != nil {
+		return 0, errs.NewDatabaseError("failed to store policy bundle", map[string]any{"error": err.Error()})
+	}
+
+	for path, content := range bundle.Files {
+		err := q.InsertPolicyBundleFile(ctx, sqlc.InsertPolicyBundleFileParams{
+			BundleID: id,
+			Path:     path,
+			Content:  content,
+		})
+		if err != nil {
+			return 0, errs.NewDatabaseError("failed to store policy bundle file", map[string]any{"path": path, "error": err.Error()})
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, errs.NewDatabaseError("failed to commit transaction", map[string]any{"error": err.Error()})
+	}
+	return id, nil
+}
+
+func (r *SQLCBundleRepository) GetBundleArchive(ctx context.Context, id int64) ([]byte, error) {
+	bundle, err := r.queries.GetPolicyBundle(ctx, id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errs.NewDatabaseError("failed to get policy bundle", map[string]any{"error": err.Error()})
+	}
+	return bundle.Archive, nil
+}
+
+func (r *SQLCBundleRepository) GetBundleFile(ctx context.Context, id int64, path string) ([]byte, error) {
+	content, err := r.queries.GetPolicyBundleFile(ctx, sqlc.GetPolicyBundleFileParams{
+		BundleID: id,
+		Path:     path,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errs.NewDatabaseError("failed to get policy bundle file", map[string]any{"error": err.Error()})
+	}
+	return content, nil
+}
+
+func (r *SQLCBundleRepository) DeleteBundle(ctx context.Context, id int64) error {
+	if err := r.queries.DeletePolicyBundle(ctx, id); err != nil {
+		return errs.NewDatabaseError("failed to delete policy bundle", map[string]any{"error": err.Error()})
+	}
+	return nil
+}
+
+func (r *SQLCBundleRepository) DeleteOrphanedBundles(ctx context.Context, minAge time.Duration) (int64, error) {
+	count, err := r.queries.DeleteOrphanedPolicyBundles(ctx, int32(minAge.Seconds()))
+	if err != nil {
+		return 0, errs.NewDatabaseError("failed to delete orphaned policy bundles", map[string]any{"error": err.Error()})
+	}
+	return count, nil
+}
+
+// SQLCDocCacheRepository implements DocCacheRepository using sqlc-generated code
+type SQLCDocCacheRepository struct {
+	queries *sqlc.Queries
+}
+
+// NewSQLCDocCacheRepository creates a new SQLC-based doc fetch cache repository
+func NewSQLCDocCacheRepository(database *db.DB) DocCacheRepository {
+	return &SQLCDocCacheRepository{
+		queries: sqlc.New(database.Pool),
+	}
+}
+
+func (r *SQLCDocCacheRepository) GetCachedDoc(ctx context.Context, url string) (*CachedDoc, error) {
+	row, err := r.queries.GetDocFetchCache(ctx, url)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errs.NewDatabaseError("failed to get cached doc page", map[string]any{"error": err.Error()})
+	}
+	return &CachedDoc{ETag: row.Etag, Content: row.Content}, nil
+}
+
+func (r *SQLCDocCacheRepository) PutCachedDoc(ctx context.Context, url string, doc *CachedDoc) error {
+	err := r.queries.UpsertDocFetchCache(ctx, sqlc.UpsertDocFetchCacheParams{
+		Url:     url,
+		Etag:    doc.ETag,
+		Content: doc.Content,
+	})
+	if err != nil {
+		return errs.NewDatabaseError("failed to cache doc page", map[string]any{"error": err.Error()})
+	}
+	return nil
+}
+
+func (r *SQLCDocCacheRepository) DeleteStaleDocs(ctx context.Context, maxAge time.Duration) (int64, error) {
+	count, err := r.queries.DeleteStaleDocFetchCache(ctx, int32(maxAge.Seconds()))
+	if err != nil {
+		return 0, errs.NewDatabaseError("failed to delete stale cached doc pages", map[string]any{"error": err.Error()})
+	}
+	return count, nil
+}
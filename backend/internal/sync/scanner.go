@@ -0,0 +1,336 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package sync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wso2/policyhub/internal/errs"
+	"github.com/wso2/policyhub/internal/policy"
+	"github.com/wso2/policyhub/internal/signing"
+)
+
+// Files of a policy version in a sync source, relative to <policy>/<version>/
+const (
+	sourceDefinitionFile = "policy-definition.yml"
+	sourceMetadataFile   = "metadata.json"
+	sourceSignatureFile  = "signature.json" // optional detached signature, as in SyncRequest.Signature
+	sourceDocsDir        = "docs"
+	sourceAssetsDir      = "assets"
+)
+
+// ScanResult summarizes a scan of the sync source
+type ScanResult struct {
+	Revision   string
+	Found      int // versions in the source
+	Existing   int // versions already stored
+	InProgress int // versions already queued or running
+	Failed     int // versions whose last job failed for good on the same files
+	Queued     []ScannedVersion
+	Errors     []ScanError
+}
+
+// ScannedVersion is a version a scan queued for sync
+type ScannedVersion struct {
+	PolicyName string
+	Version    string
+	JobID      int64
+}
+
+// ScanError is a version a scan could not queue
+type ScanError struct {
+	PolicyName string
+	Version    string
+	Message    string
+}
+
+// sourceVersion is a policy version found in the sync source
+type sourceVersion struct {
+	policyName string
+	version    string
+	docs       map[string]string // doc page -> file path
+	files      []string          // every file under the version's directory
+	hasSigned  bool
+	hasAssets  bool
+}
+
+// dir returns the version's directory in the source
+func (v *sourceVersion) dir() string {
+	return v.policyName + "/" + v.version
+}
+
+// ScanSource queues a sync job for every version in the sync source that is
+// not stored yet. Versions with a queued or running job are left alone. A
+// version whose last job failed is only queued again once its files changed,
+// or when the failure was transient; see requeueFailed.
+func (s *Service) ScanSource(ctx context.Context) (*ScanResult, error) {
+	if s.source == nil {
+		return nil, errs.SyncSourceNotConfigured()
+	}
+
+	s.scanMu.Lock()
+	defer s.scanMu.Unlock()
+
+	startTime := time.Now()
+
+	revision, err := s.source.Revision(ctx)
+	if err != nil {
+		s.logger.Error("Failed to resolve sync source revision", zap.Error(err))
+		return nil, errs.SyncFetchFailed(s.cfg.Source.Path, err)
+	}
+
+	files, err := s.source.ListFiles(ctx, revision)
+	if err != nil {
+		s.logger.Error("Failed to list sync source files", zap.String("revision", revision), zap.Error(err))
+		return nil, errs.SyncFetchFailed(s.cfg.Source.Path, err)
+	}
+
+	versions := findSourceVersions(files)
+	result := &ScanResult{
+		Revision: revision,
+		Found:    len(versions),
+		Queued:   []ScannedVersion{},
+		Errors:   []ScanError{},
+	}
+	if len(versions) == 0 {
+		return result, nil
+	}
+
+	names := make([]string, 0, len(versions))
+	for _, v := range versions {
+		if len(names) == 0 || names[len(names)-1] != v.policyName {
+			names = append(names, v.policyName)
+		}
+	}
+	existing, err := s.policyService.ListExistingVersions(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	latestJobs, err := s.jobs.ListLatestJobs(ctx, names)
+	if err != nil {
+		s.logger.Error("Failed to list latest sync jobs", zap.Error(err))
+		return nil, errs.SanitizeDatabaseError("list sync jobs")
+	}
+	failed := make(map[string]*Job)
+	for _, job := range latestJobs {
+		if job.Status == JobFailed {
+			failed[job.PolicyName+"/"+job.Version] = job
+		}
+	}
+
+	for _, v := range versions {
+		if slices.Contains(existing[v.policyName], v.version) {
+			result.Existing++
+			continue
+		}
+
+		sourceRevision, err := s.versionRevision(ctx, revision, v)
+		if err == nil {
+			if job := failed[v.dir()]; job != nil && !requeueFailed(job, sourceRevision) {
+				result.Failed++
+				continue
+			}
+
+			var req *SyncRequest
+			if req, err = s.sourceRequest(ctx, revision, v); err == nil {
+				var job *Job
+				if job, err = s.submit(ctx, req, sourceRevision); err == nil {
+					result.Queued = append(result.Queued, ScannedVersion{PolicyName: v.policyName, Version: v.version, JobID: job.ID})
+					continue
+				}
+			}
+		}
+
+		var appErr *errs.AppError
+		if errors.As(err, &appErr) && appErr.Code == errs.CodeSyncInProgress {
+			result.InProgress++
+			continue
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		message := err.Error()
+		if appErr != nil {
+			message = appErr.Message
+			if detail, ok := appErr.Details["error"].(string); ok {
+				message += ": " + detail
+			}
+		}
+		s.logger.Warn("Sync source version not queued",
+			zap.String("policy", v.policyName),
+			zap.String("version", v.version),
+			zap.String("error", message))
+		result.Errors = append(result.Errors, ScanError{PolicyName: v.policyName, Version: v.version, Message: message})
+	}
+
+	s.logger.Info("Sync source scanned",
+		zap.String("revision", revision),
+		zap.Int("found", result.Found),
+		zap.Int("existing", result.Existing),
+		zap.Int("in_progress", result.InProgress),
+		zap.Int("failed", result.Failed),
+		zap.Int("queued", len(result.Queued)),
+		zap.Int("errors", len(result.Errors)),
+		zap.Duration("duration", time.Since(startTime)))
+
+	return result, nil
+}
+
+// findSourceVersions groups source files into policy versions, sorted by policy
+// and version. A version is any <policy>/<version>/ directory with a definition.
+func findSourceVersions(files []string) []*sourceVersion {
+	byDir := make(map[string]*sourceVersion)
+	for _, file := range files {
+		parts := strings.Split(file, "/")
+		if len(parts) == 3 && parts[2] == sourceDefinitionFile {
+			byDir[parts[0]+"/"+parts[1]] = &sourceVersion{
+				policyName: parts[0],
+				version:    parts[1],
+				docs:       make(map[string]string),
+			}
+		}
+	}
+
+	validDocTypes := policy.ValidDocTypes()
+	for _, file := range files {
+		parts := strings.Split(file, "/")
+		if len(parts) < 3 {
+			continue
+		}
+		v, ok := byDir[parts[0]+"/"+parts[1]]
+		if !ok {
+			continue
+		}
+		v.files = append(v.files, file)
+
+		switch {
+		case len(parts) == 3 && parts[2] == sourceSignatureFile:
+			v.hasSigned = true
+		case len(parts) == 4 && parts[2] == sourceDocsDir && path.Ext(parts[3]) == ".md":
+			if page := strings.TrimSuffix(parts[3], ".md"); validDocTypes[page] {
+				v.docs[page] = file
+			}
+		case len(parts) > 3 && parts[2] == sourceAssetsDir:
+			v.hasAssets = true
+		}
+	}
+
+	versions := make([]*sourceVersion, 0, len(byDir))
+	for _, v := range byDir {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		if versions[i].policyName != versions[j].policyName {
+			return versions[i].policyName < versions[j].policyName
+		}
+		return versions[i].version < versions[j].version
+	})
+	return versions
+}
+
+// requeueFailed reports whether a scan queues a version again after its last
+// job failed. Failures caused by the version's files recur until they change;
+// transient failures, such as an unreachable download URL, may not. Failed jobs
+// are deleted after the job retention, so a version is retried once per period.
+func requeueFailed(job *Job, sourceRevision string) bool {
+	if job.SourceRevision != sourceRevision {
+		return true
+	}
+	return job.LastError == nil || isRetryableCode(errs.Code(job.LastError.Code))
+}
+
+// versionRevision returns the revision of a version's files: the SHA-256 of a
+// sha256sum style listing of every file under its directory, sorted by path.
+// Unlike the source revision it only changes when the version itself changes.
+func (s *Service) versionRevision(ctx context.Context, revision string, v *sourceVersion) (string, error) {
+	files := make(map[string][]byte, len(v.files))
+	for _, file := range v.files {
+		data, err := s.source.ReadFile(ctx, revision, file)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		files[strings.TrimPrefix(file, v.dir()+"/")] = data
+	}
+	return filesDigest(files), nil
+}
+
+// filesDigest returns the SHA-256 of a "<hex>  <path>\n" listing of the files, sorted by path
+func filesDigest(files map[string][]byte) string {
+	listing := sha256.New()
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		sum := sha256.Sum256(files[name])
+		fmt.Fprintf(listing, "%s  %s\n", hex.EncodeToString(sum[:]), name)
+	}
+	return policy.FormatDigest(listing.Sum(nil))
+}
+
+// sourceRequest builds the sync request of a source version. The definition and
+// docs are read from the source by the sync job; the artifact and assets are
+// served from the configured public URLs.
+func (s *Service) sourceRequest(ctx context.Context, revision string, v *sourceVersion) (*SyncRequest, error) {
+	data, err := s.source.ReadFile(ctx, revision, v.dir()+"/"+sourceMetadataFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", sourceMetadataFile, err)
+	}
+	var metadata policy.PolicyMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", sourceMetadataFile, err)
+	}
+
+	source := s.cfg.Source
+	placeholders := strings.NewReplacer("{policy}", url.PathEscape(v.policyName), "{version}", url.PathEscape(v.version))
+	req := &SyncRequest{
+		PolicyName:    v.policyName,
+		Version:       v.version,
+		SourceType:    s.source.Type(),
+		SourceURL:     placeholders.Replace(source.DownloadURL),
+		DefinitionURL: sourceURL(revision, v.dir()+"/"+sourceDefinitionFile),
+		Metadata:      &metadata,
+		Documentation: make(map[string]string, len(v.docs)),
+	}
+	for page, file := range v.docs {
+		req.Documentation[page] = sourceURL(revision, file)
+	}
+
+	// metadata.json is used as is, since it is what the release signature covers
+	if source.PublicURL != "" && v.hasAssets {
+		req.AssetsBaseURL = strings.TrimSuffix(source.PublicURL, "/") + "/" +
+			url.PathEscape(v.policyName) + "/" + url.PathEscape(v.version) + "/" + sourceAssetsDir + "/"
+	}
+
+	if v.hasSigned {
+		data, err := s.source.ReadFile(ctx, revision, v.dir()+"/"+sourceSignatureFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", sourceSignatureFile, err)
+		}
+		var signature signing.Signature
+		if err := json.Unmarshal(data, &signature); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", sourceSignatureFile, err)
+		}
+		req.Signature = &signature
+	}
+
+	return req, nil
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package sync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/wso2/policyhub/internal/config"
+	"github.com/wso2/policyhub/internal/errs"
+)
+
+func TestRequeueFailed(t *testing.T) {
+	failedWith := func(revision string, code errs.Code) *Job {
+		return &Job{Status: JobFailed, SourceRevision: revision, LastError: &JobError{Code: string(code)}}
+	}
+
+	tests := []struct {
+		name     string
+		job      *Job
+		revision string
+		want     bool
+	}{
+		{name: "invalid definition, same files", job: failedWith("sha256:a", errs.CodeDefinitionInvalid), revision: "sha256:a", want: false},
+		{name: "invalid signature, same files", job: failedWith("sha256:a", errs.CodeSignatureInvalid), revision: "sha256:a", want: false},
+		{name: "invalid definition, changed files", job: failedWith("sha256:a", errs.CodeDefinitionInvalid), revision: "sha256:b", want: true},
+		{name: "fetch failed, same files", job: failedWith("sha256:a", errs.CodeSyncFetchFailed), revision: "sha256:a", want: true},
+		{name: "worker stopped, same files", job: failedWith("sha256:a", errs.CodeInternalServerError), revision: "sha256:a", want: true},
+		{name: "pushed job", job: failedWith("", errs.CodeDefinitionInvalid), revision: "sha256:a", want: true},
+		{name: "no recorded error", job: &Job{Status: JobFailed, SourceRevision: "sha256:a"}, revision: "sha256:a", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := requeueFailed(tt.job, tt.revision); got != tt.want {
+				t.Errorf("requeueFailed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionRevision(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("rate-limit/1.0.0/policy-definition.yml", "name: rate-limit\n")
+	write("rate-limit/1.0.0/metadata.json", `{"provider":"WSO2"}`)
+	write("rate-limit/1.0.0/assets/images/logo.svg", "<svg/>")
+	write("rate-limit/1.1.0/policy-definition.yml", "name: rate-limit\n")
+	write("rate-limit/1.1.0/metadata.json", `{"provider":"WSO2"}`)
+
+	source, err := NewSource(&config.SyncSourceConfig{Path: dir, Type: SourceTypeDirectory})
+	if err != nil {
+		t.Fatalf("NewSource() error = %v", err)
+	}
+	s := &Service{source: source}
+	ctx := context.Background()
+
+	revisions := func() []string {
+		t.Helper()
+		files, err := source.ListFiles(ctx, "")
+		if err != nil {
+			t.Fatalf("ListFiles() error = %v", err)
+		}
+		var revisions []string
+		for _, v := range findSourceVersions(files) {
+			revision, err := s.versionRevision(ctx, "", v)
+			if err != nil {
+				t.Fatalf("versionRevision(%s) error = %v", v.dir(), err)
+			}
+			revisions = append(revisions, revision)
+		}
+		return revisions
+	}
+
+	before := revisions()
+	if len(before) != 2 || before[0] == before[1] {
+		t.Fatalf("revisions = %v, want one per version, differing by their files", before)
+	}
+	if again := revisions(); !slices.Equal(again, before) {
+		t.Errorf("revisions changed without a change to the files: %v, then %v", before, again)
+	}
+
+	// Changing a file of one version only changes that version's revision
+	write("rate-limit/1.0.0/assets/images/logo.svg", "<svg></svg>")
+	after := revisions()
+	if after[0] == before[0] {
+		t.Error("changing an asset kept the revision of its version")
+	}
+	if after[1] != before[1] {
+		t.Error("changing another version's asset changed the revision")
+	}
+}
@@ -11,37 +11,146 @@ package sync
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"net/http"
-	"regexp"
 	"strings"
+	gosync "sync"
 	"time"
 
+	"github.com/wso2/policyhub/internal/config"
 	"github.com/wso2/policyhub/internal/errs"
 	"github.com/wso2/policyhub/internal/logging"
 	"github.com/wso2/policyhub/internal/policy"
+	"github.com/wso2/policyhub/internal/signing"
+	"github.com/wso2/policyhub/internal/storage"
 	"github.com/wso2/policyhub/internal/validation"
 	"go.uber.org/zap"
-	"gopkg.in/yaml.v3"
 )
 
 // Service handles policy synchronization
 type Service struct {
 	policyService *policy.Service
+	jobs          JobRepository
+	bundles       BundleRepository
+	docCache      DocCacheRepository
+	verifier      *signing.Verifier
+	source        Source        // nil when pull-based sync is disabled
+	publicURL     string        // base URL of the hub, for the files it serves from bundles and the blob store
+	store         storage.Store // nil when mirroring is disabled
+	maxObjectSize int64
+	cfg           *config.SyncConfig
 	logger        *logging.Logger
 	httpClient    *http.Client
+	guard         *fetchGuard
+	// submitted wakes an idle worker when a job is queued
+	submitted chan struct{}
+	// scanMu serializes scans of the sync source
+	scanMu gosync.Mutex
 }
 
-// NewService creates a new sync service
-func NewService(policyService *policy.Service, logger *logging.Logger) *Service {
+// NewService creates a new sync service. source and store may be nil.
+func NewService(policyService *policy.Service, jobs JobRepository, bundles BundleRepository, docCache DocCacheRepository, verifier *signing.Verifier, source Source, store storage.Store, publicURL string, storageCfg *config.StorageConfig, cfg *config.SyncConfig, logger *logging.Logger) *Service {
+	// Source and bundle files are fetched like remote ones, through source:// and bundle:// transports;
+	// remote fetches are held to the fetch policy
+	guard := &fetchGuard{cfg: &cfg.Fetch}
+	transport := newFetchTransport(guard)
+	transport.RegisterProtocol(BundleScheme, &bundleTransport{bundles: bundles})
+	if source != nil {
+		transport.RegisterProtocol(SourceScheme, &sourceTransport{source: source})
+	}
+
 	return &Service{
 		policyService: policyService,
+		jobs:          jobs,
+		bundles:       bundles,
+		docCache:      docCache,
+		verifier:      verifier,
+		source:        source,
+		publicURL:     publicURL,
+		store:         store,
+		maxObjectSize: storageCfg.MaxObjectSize,
+		cfg:           cfg,
 		logger:        logger,
 		httpClient: &http.Client{
-			Timeout: policy.HTTPTimeout,
+			Timeout:       policy.HTTPTimeout,
+			Transport:     transport,
+			CheckRedirect: guard.checkRedirect,
 		},
+		guard:     guard,
+		submitted: make(chan struct{}, 1),
+	}
+}
+
+// SubmitSync validates a sync request and queues it as a job for the workers.
+// Source and bundle URLs are reserved for requests queued by source scans and
+// bundle uploads.
+func (s *Service) SubmitSync(ctx context.Context, req *SyncRequest) (*Job, error) {
+	if req.usesSource() {
+		return nil, errs.NewValidationError("source URLs can only be used by sync source scans", nil)
 	}
+	if req.usesBundle() {
+		return nil, errs.NewValidationError("bundle URLs can only be used by bundle uploads", nil)
+	}
+	return s.submit(ctx, req, "")
+}
+
+// submit validates a sync request and queues it as a job. sourceRevision is
+// the revision of the source files a scan read the request from.
+func (s *Service) submit(ctx context.Context, req *SyncRequest, sourceRevision string) (*Job, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	// Fail early; publishing checks the owner again under the policy lock
+	if err := s.policyService.CheckPolicyOwner(ctx, req.PolicyName, req.Metadata.Provider); err != nil {
+		return nil, err
+	}
+
+	steps := make([]JobStep, 0, len(Steps()))
+	for _, step := range Steps() {
+		steps = append(steps, JobStep{Name: step, Status: StepPending})
+	}
+
+	job, err := s.jobs.CreateJob(ctx, &Job{
+		PolicyName:     req.PolicyName,
+		Version:        req.Version,
+		Provider:       req.Metadata.Provider,
+		Request:        req,
+		MaxAttempts:    s.cfg.MaxAttempts,
+		Steps:          steps,
+		SourceRevision: sourceRevision,
+	})
+	if err != nil {
+		if errs.IsUniqueConstraintError(err) {
+			active, lookupErr := s.jobs.GetActiveJob(ctx, req.PolicyName, req.Version)
+			if lookupErr == nil && active != nil {
+				return nil, errs.SyncInProgress(req.PolicyName, req.Version, active.ID)
+			}
+		}
+		if appErr, ok := err.(*errs.AppError); ok {
+			return nil, appErr
+		}
+		s.logger.Error("Failed to queue sync job", zap.Error(err))
+		return nil, errs.SanitizeDatabaseError("queue sync job")
+	}
+
+	s.logger.Info("Policy sync queued",
+		zap.Int64("job_id", job.ID),
+		zap.String("policy", req.PolicyName),
+		zap.String("version", req.Version))
+
+	select {
+	case s.submitted <- struct{}{}:
+	default:
+	}
+
+	return job, nil
+}
+
+// GetJob returns a sync job by ID
+func (s *Service) GetJob(ctx context.Context, id int64) (*Job, error) {
+	return s.jobs.GetJob(ctx, id)
 }
 
 // Validate validates the sync request
@@ -80,8 +189,10 @@ func (r *SyncRequest) Validate() *errs.AppError {
 	if err := validation.ValidateURL(r.SourceURL); err != nil {
 		return errs.NewValidationError("invalid source URL", map[string]any{"error": err.Message})
 	}
-	if err := validation.ValidateURL(r.DefinitionURL); err != nil {
-		return errs.NewValidationError("invalid definition URL", map[string]any{"error": err.Message})
+	if !isSourceURL(r.DefinitionURL) && !isBundleURL(r.DefinitionURL) {
+		if err := validation.ValidateURL(r.DefinitionURL); err != nil {
+			return errs.NewValidationError("invalid definition URL", map[string]any{"error": err.Message})
+		}
 	}
 
 	// Validate metadata
@@ -101,11 +212,53 @@ func (r *SyncRequest) Validate() *errs.AppError {
 		return err
 	}
 
+	// Required docs must be known pages listed in the request
+	for _, page := range r.RequireDocs {
+		if !policy.ValidDocTypes()[page] {
+			return errs.NewValidationError("invalid required documentation page", map[string]any{"page": page})
+		}
+		if r.Documentation[page] == "" {
+			return errs.NewValidationError("required documentation page has no URL in documentation", map[string]any{"page": page})
+		}
+	}
+
+	// Validate signature
+	if r.Signature != nil && (r.Signature.KeyID == "" || r.Signature.Value == "") {
+		return errs.NewValidationError("signature requires a keyId and a value", nil)
+	}
+
 	return nil
 }
 
-// SyncPolicy synchronizes a policy from a remote source
-func (s *Service) SyncPolicy(ctx context.Context, req *SyncRequest) (*SyncResult, error) {
+// usesSource reports whether the request fetches any file from the sync source
+func (r *SyncRequest) usesSource() bool {
+	if isSourceURL(r.DefinitionURL) || isSourceURL(r.SourceURL) {
+		return true
+	}
+	for _, docURL := range r.Documentation {
+		if isSourceURL(docURL) {
+			return true
+		}
+	}
+	return false
+}
+
+// usesBundle reports whether the request fetches any file from an uploaded bundle
+func (r *SyncRequest) usesBundle() bool {
+	if r.BundleID != nil || isBundleURL(r.DefinitionURL) || isBundleURL(r.SourceURL) {
+		return true
+	}
+	for _, docURL := range r.Documentation {
+		if isBundleURL(docURL) {
+			return true
+		}
+	}
+	return false
+}
+
+// SyncPolicy synchronizes a policy from a remote source, reporting each step to
+// progress when it is not nil
+func (s *Service) SyncPolicy(ctx context.Context, req *SyncRequest, progress ProgressFunc) (*SyncResult, error) {
 	startTime := time.Now()
 
 	s.logger.Info("Policy synchronization started",
@@ -127,25 +280,90 @@ func (s *Service) SyncPolicy(ctx context.Context, req *SyncRequest) (*SyncResult
 	// Get metadata (inline only)
 	metadata := req.Metadata
 
-	// Validate metadata matches request
-
 	// Fetch policy definition
-	definition, err := s.fetchPolicyDefinition(req.DefinitionURL)
+	var definition string
+	err := runStep(progress, StepFetchDefinition, func() (err error) {
+		definition, err = s.fetchPolicyDefinition(ctx, req)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	policyVersion, err := s.createPolicyVersion(ctx, req.PolicyName, req.Version, metadata, definition, req)
+	// Digest the artifact consumers download so they can verify what they fetched,
+	// mirroring remote artifacts into the blob store when one is configured.
+	// Bundle archives are read from the database rather than through the hub.
+	artifactURL := req.SourceURL
+	if req.BundleID != nil {
+		artifactURL = bundleURL(*req.BundleID, "")
+	}
+	var artifactDigest, downloadURL string
+	err = runStep(progress, StepFetchArtifact, func() error {
+		if !s.shouldMirror(artifactURL) {
+			digest, err := s.fetchArtifactDigest(ctx, artifactURL)
+			artifactDigest = digest
+			return err
+		}
+		file, err := s.mirrorFile(ctx, artifactURL, fetchArtifactKind)
+		if err != nil {
+			return err
+		}
+		artifactDigest, downloadURL = file.digest, file.url
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// Sync documentation
+	// Verify the release signature, which covers the artifact digest, before docs
+	// are fetched or anything is stored. A mirrored artifact is content addressed
+	// and referenced by no version until the version is stored.
+	var signature *verifiedSignature
+	var warning string
+	err = runStep(progress, StepVerifySignature, func() (err error) {
+		signature, warning, err = s.verifySignature(ctx, req, definition, artifactDigest)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch every doc page before anything is written
+	var docs []*policy.PolicyDoc
+	var docResults []DocResult
 	if len(req.Documentation) > 0 {
-		if err := s.syncDocs(ctx, policyVersion.ID, req.Documentation, req.PolicyName, req.Version, req.AssetsBaseURL); err != nil {
-			s.logger.Warn("Failed to sync docs", zap.Error(err))
-			// Don't fail the entire sync if docs fail
+		err = runStep(progress, StepFetchDocs, func() (err error) {
+			docs, docResults, err = s.fetchDocs(ctx, req)
+			return err
+		})
+		if err != nil {
+			return nil, err
 		}
+	} else if progress != nil {
+		progress(StepFetchDocs, StepSkipped, nil)
+	}
+
+	// Copy the logo, banner and doc images into the blob store
+	if s.store != nil {
+		err = runStep(progress, StepMirrorAssets, func() (err error) {
+			metadata, err = s.mirrorAssets(ctx, metadata, docs)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else if progress != nil {
+		progress(StepMirrorAssets, StepSkipped, nil)
+	}
+
+	// Store the version and its docs atomically; nothing is visible on failure
+	var policyVersion *policy.PolicyVersion
+	err = runStep(progress, StepStoreVersion, func() (err error) {
+		policyVersion, err = s.createPolicyVersion(ctx, req.PolicyName, req.Version, metadata, definition, artifactDigest, downloadURL, signature, docs, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	if req.AssetsBaseURL != "" {
@@ -166,45 +384,155 @@ func (s *Service) SyncPolicy(ctx context.Context, req *SyncRequest) (*SyncResult
 		zap.String("policy", req.PolicyName),
 		zap.String("version", req.Version),
 		zap.Duration("duration", time.Since(startTime)),
-		zap.Int("docs_synced", len(req.Documentation)),
+		zap.Int("docs_synced", len(docs)),
 		zap.Bool("asset_urls_stored", req.AssetsBaseURL != ""))
 
 	return &SyncResult{
-		PolicyName: req.PolicyName,
-		Version:    req.Version,
-		Status:     "synced",
+		PolicyName:       req.PolicyName,
+		Version:          req.Version,
+		Status:           "synced",
+		DefinitionDigest: policyVersion.DefinitionDigest,
+		ArtifactDigest:   artifactDigest,
+		SignatureStatus:  policyVersion.SignatureStatus,
+		Warning:          warning,
+		Docs:             docResults,
 	}, nil
 }
 
-// fetchPolicyDefinition fetches policy-definition.yml as YAML
-func (s *Service) fetchPolicyDefinition(url string) (string, error) {
-	s.logger.Debug("Fetching policy definition", zap.String("url", url))
+// runStep runs fn as one sync step, reporting its start and outcome to progress
+func runStep(progress ProgressFunc, step string, fn func() error) error {
+	if progress == nil {
+		return fn()
+	}
+
+	progress(step, StepRunning, nil)
+	if err := fn(); err != nil {
+		progress(step, StepFailed, err)
+		return err
+	}
+	progress(step, StepSucceeded, nil)
+	return nil
+}
 
-	resp, err := s.httpClient.Get(url)
+// get issues a GET request with the given headers that is cancelled with ctx
+func (s *Service) get(ctx context.Context, url string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return "", errs.SyncFetchFailed(url, err)
+		return nil, err
 	}
-	defer resp.Body.Close()
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	return s.httpClient.Do(req)
+}
+
+// fetchPolicyDefinition fetches policy-definition.yml and validates it against
+// the policy definition schema and the name and version it is published as
+func (s *Service) fetchPolicyDefinition(ctx context.Context, req *SyncRequest) (string, error) {
+	url := req.DefinitionURL
+	s.logger.Debug("Fetching policy definition", zap.String("url", url))
 
-	if resp.StatusCode != http.StatusOK {
-		return "", errs.SyncFetchFailed(url, fmt.Errorf("status code %d", resp.StatusCode))
+	resp, err := s.fetch(ctx, url, fetchDefinitionKind)
+	if err != nil {
+		return "", fetchError(url, err)
 	}
+	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", errs.SyncFetchFailed(url, err)
+		return "", fetchError(url, err)
 	}
 
-	// Validate YAML format
-	var yamlData interface{}
-	if err := yaml.Unmarshal(body, &yamlData); err != nil {
-		return "", errs.NewValidationError("invalid policy definition YAML", map[string]any{"error": err.Error()})
+	if err := validation.ValidateDefinition(body, req.PolicyName, req.Version); err != nil {
+		return "", err
 	}
 
 	// Return YAML as string for storage
 	return string(body), nil
 }
 
+// verifySignature checks the release signature against the provider's keys.
+// Unsigned releases are allowed, allowed with a warning or rejected depending
+// on the configured policy. Bundle signatures cover the bundle's content
+// digest rather than the digest of the archive holding them.
+func (s *Service) verifySignature(ctx context.Context, req *SyncRequest, definition, artifactDigest string) (*verifiedSignature, string, error) {
+	if req.Signature == nil {
+		switch s.verifier.UnsignedPolicy() {
+		case signing.UnsignedReject:
+			s.logger.Warn("Unsigned policy sync rejected",
+				zap.String("policy", req.PolicyName),
+				zap.String("version", req.Version))
+			return nil, "", errs.SignatureRequired(req.PolicyName, req.Version)
+		case signing.UnsignedWarn:
+			s.logger.Warn("Syncing unsigned policy release",
+				zap.String("policy", req.PolicyName),
+				zap.String("version", req.Version))
+			return nil, fmt.Sprintf("Policy %s version %s is not signed", req.PolicyName, req.Version), nil
+		default:
+			return nil, "", nil
+		}
+	}
+
+	if req.BundleID != nil {
+		digest, err := s.signedBundleDigest(ctx, *req.BundleID)
+		if err != nil {
+			return nil, "", err
+		}
+		artifactDigest = digest
+	}
+
+	payload, err := ReleasePayload(req.PolicyName, req.Version, []byte(definition), artifactDigest, req.Metadata)
+	if err != nil {
+		return nil, "", errs.SignatureInvalid(req.PolicyName, req.Version, err)
+	}
+
+	if err := s.verifier.Verify(req.Metadata.Provider, payload, *req.Signature); err != nil {
+		s.logger.Warn("Policy release signature verification failed",
+			zap.String("policy", req.PolicyName),
+			zap.String("version", req.Version),
+			zap.String("provider", req.Metadata.Provider),
+			zap.String("key_id", req.Signature.KeyID),
+			zap.Error(err))
+		return nil, "", errs.SignatureInvalid(req.PolicyName, req.Version, err)
+	}
+
+	return &verifiedSignature{
+		keyID:      req.Signature.KeyID,
+		value:      req.Signature.Value,
+		verifiedAt: time.Now(),
+	}, "", nil
+}
+
+// ReleasePayload builds the signing payload of a release. Absent metadata lists
+// are signed as empty arrays so signers need not distinguish null from [].
+func ReleasePayload(name, version string, definition []byte, artifactDigest string, metadata *policy.PolicyMetadata) ([]byte, error) {
+	signed := *metadata
+	for _, list := range []*[]string{&signed.Categories, &signed.Tags, &signed.SupportedPlatforms} {
+		if *list == nil {
+			*list = []string{}
+		}
+	}
+	return signing.Payload(name, version, definition, artifactDigest, signed)
+}
+
+// fetchArtifactDigest downloads the artifact at url and returns its SHA-256 digest
+func (s *Service) fetchArtifactDigest(ctx context.Context, url string) (string, error) {
+	s.logger.Debug("Fetching policy artifact", zap.String("url", url))
+
+	resp, err := s.fetch(ctx, url, fetchArtifactKind)
+	if err != nil {
+		return "", fetchError(url, err)
+	}
+	defer resp.Body.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, resp.Body); err != nil {
+		return "", fetchError(url, err)
+	}
+
+	return policy.FormatDigest(hash.Sum(nil)), nil
+}
+
 // createPolicyVersion creates a new policy version
 func (s *Service) createPolicyVersion(
 	ctx context.Context,
@@ -212,6 +540,10 @@ func (s *Service) createPolicyVersion(
 	version string,
 	metadata *policy.PolicyMetadata,
 	definition string,
+	artifactDigest string,
+	downloadURL string,
+	signature *verifiedSignature,
+	docs []*policy.PolicyDoc,
 	req *SyncRequest,
 ) (*policy.PolicyVersion, error) {
 	policyVersion := &policy.PolicyVersion{
@@ -224,6 +556,7 @@ func (s *Service) createPolicyVersion(
 		Tags:               metadata.Tags,
 		SupportedPlatforms: metadata.SupportedPlatforms,
 		DefinitionYAML:     definition,
+		ArtifactDigest:     &artifactDigest,
 	}
 
 	// Set source information from sync request
@@ -233,10 +566,24 @@ func (s *Service) createPolicyVersion(
 	if req.SourceURL != "" {
 		policyVersion.SourceURL = &req.SourceURL
 	}
+	// Mirrored artifacts are downloaded from the hub
+	if downloadURL != "" {
+		policyVersion.SourceURL = &downloadURL
+	}
+	policyVersion.BundleID = req.BundleID
 
 	desc := metadata.Description
 	policyVersion.Description = &desc
 
+	// Record the verified release signature
+	policyVersion.SignatureStatus = policy.SignatureStatusUnsigned
+	if signature != nil {
+		policyVersion.SignatureStatus = policy.SignatureStatusVerified
+		policyVersion.SignatureKeyID = &signature.keyID
+		policyVersion.Signature = &signature.value
+		policyVersion.SignatureVerifiedAt = &signature.verifiedAt
+	}
+
 	// Set release date to now
 	now := time.Now()
 	policyVersion.ReleaseDate = &now
@@ -253,65 +600,27 @@ func (s *Service) createPolicyVersion(
 		policyVersion.BannerPath = &metadata.BannerURL
 	}
 
-	return s.policyService.CreatePolicyVersion(ctx, policyVersion)
+	return s.policyService.CreatePolicyVersion(ctx, policyVersion, docs)
 }
 
-// syncDocs synchronizes documentation files
-func (s *Service) syncDocs(ctx context.Context, versionID int32, documentation map[string]string, policyName, version, assetsBaseURL string) error {
-	for docType, docPath := range documentation {
-		content, err := s.fetchMarkdown(docPath)
-		if err != nil {
-			s.logger.Debug("Doc page not found", zap.String("docType", docType), zap.String("path", docPath), zap.Error(err))
-			continue // Skip missing docs
-		}
-
-		// Rewrite image references
-		content = s.rewriteImageReferences(content, assetsBaseURL)
-
-		doc := &policy.PolicyDoc{
-			PolicyVersionID: versionID,
-			Page:            docType,
-			ContentMd:       content,
-		}
-
-		if _, err := s.policyService.UpsertPolicyDoc(ctx, doc); err != nil {
-			return err
-		}
-
-		s.logger.Debug("Synced doc page", zap.String("docType", docType))
-	}
-
-	return nil
-}
-
-// fetchMarkdown fetches markdown content from a URL
-func (s *Service) fetchMarkdown(url string) (string, error) {
-	resp, err := s.httpClient.Get(url)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("status code %d", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-
-	return string(body), nil
-}
-
-// rewriteImageReferences rewrites relative image paths to absolute asset URLs
-func (s *Service) rewriteImageReferences(markdown, assetsBaseURL string) string {
-	if assetsBaseURL == "" {
+// rewriteImageReferences rewrites relative image paths to absolute asset URLs,
+// then points images found in mirrored at their copies in the blob store
+func (s *Service) rewriteImageReferences(markdown, assetsBaseURL string, mirrored map[string]string) string {
+	if assetsBaseURL == "" && len(mirrored) == 0 {
 		return markdown
 	}
 
 	// Replace patterns like ![alt](images/foo.png) with ![alt](https://cdn.example.com/assets/images/foo.png)
-	pattern := regexp.MustCompile(`!\[(.*?)\]\((images/.*?)\)`)
 	baseURL := strings.TrimSuffix(assetsBaseURL, "/")
-	return pattern.ReplaceAllString(markdown, fmt.Sprintf("![$1](%s/$2)", baseURL))
+	return imagePattern.ReplaceAllStringFunc(markdown, func(image string) string {
+		match := imagePattern.FindStringSubmatch(image)
+		imageURL := match[2]
+		if baseURL != "" && strings.HasPrefix(imageURL, "images/") {
+			imageURL = baseURL + "/" + imageURL
+		}
+		if mirroredURL, ok := mirrored[imageURL]; ok {
+			imageURL = mirroredURL
+		}
+		return "![" + match[1] + "](" + imageURL + match[3] + ")"
+	})
 }
@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package sync
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wso2/policyhub/internal/policy"
+)
+
+func TestReleasePayloadNilLists(t *testing.T) {
+	definition := []byte("name: rate-limit\nversion: 1.2.0\n")
+	artifact := policy.ComputeDigest([]byte("artifact"))
+	unset := &policy.PolicyMetadata{DisplayName: "Rate Limit", Provider: "WSO2"}
+	empty := &policy.PolicyMetadata{
+		DisplayName:        "Rate Limit",
+		Provider:           "WSO2",
+		Categories:         []string{},
+		Tags:               []string{},
+		SupportedPlatforms: []string{},
+	}
+
+	got, err := ReleasePayload("rate-limit", "1.2.0", definition, artifact, unset)
+	if err != nil {
+		t.Fatalf("ReleasePayload() error = %v", err)
+	}
+	want, err := ReleasePayload("rate-limit", "1.2.0", definition, artifact, empty)
+	if err != nil {
+		t.Fatalf("ReleasePayload() error = %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("payload with unset lists =\n%s\nwant the payload with empty lists\n%s", got, want)
+	}
+	metadataJSON := `{"bannerUrl":"","categories":[],"description":"","displayName":"Rate Limit",` +
+		`"logoUrl":"","provider":"WSO2","supportedPlatforms":[],"tags":[]}`
+	if line := "metadata: " + policy.ComputeDigest([]byte(metadataJSON)) + "\n"; !strings.HasSuffix(string(got), line) {
+		t.Errorf("payload =\n%s\nwant it to end with %q", got, line)
+	}
+	if unset.Categories != nil || unset.Tags != nil || unset.SupportedPlatforms != nil {
+		t.Error("ReleasePayload() modified the metadata it was given")
+	}
+}
@@ -0,0 +1,228 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package sync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/wso2/policyhub/internal/config"
+)
+
+// SourceScheme is the URL scheme of files in the sync source. Requests queued
+// by a source scan reference them as source://<revision>/<path>.
+const SourceScheme = "source"
+
+// Source types
+const (
+	SourceTypeDirectory = "directory"
+	SourceTypeGit       = "git"
+)
+
+// Source is a policy repository laid out as <policy>/<version>/ holding
+// policy-definition.yml, metadata.json, docs/*.md and assets/
+type Source interface {
+	// Type is the source type recorded on synced versions
+	Type() string
+	// Revision returns the revision to scan; directories have none
+	Revision(ctx context.Context) (string, error)
+	// ListFiles lists the slash-separated paths of the files at a revision
+	ListFiles(ctx context.Context, revision string) ([]string, error)
+	// ReadFile reads a file at a revision; missing files return an fs.ErrNotExist error
+	ReadFile(ctx context.Context, revision, name string) ([]byte, error)
+}
+
+// NewSource opens the configured sync source. It returns nil when none is configured.
+func NewSource(cfg *config.SyncSourceConfig) (Source, error) {
+	if cfg.Path == "" {
+		return nil, nil
+	}
+
+	switch cfg.Type {
+	case SourceTypeDirectory:
+		root, err := os.OpenRoot(cfg.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open sync source directory: %w", err)
+		}
+		return &directorySource{root: root}, nil
+	case SourceTypeGit:
+		if _, err := exec.LookPath("git"); err != nil {
+			return nil, fmt.Errorf("git sync source needs the git executable: %w", err)
+		}
+		if _, err := os.Stat(cfg.Path); err != nil {
+			return nil, fmt.Errorf("failed to open sync source repository: %w", err)
+		}
+		return &gitSource{gitDir: cfg.Path, ref: cfg.Ref}, nil
+	default:
+		return nil, fmt.Errorf("invalid sync source type %q (must be directory or git)", cfg.Type)
+	}
+}
+
+// sourceURL returns the URL of a file in the sync source
+func sourceURL(revision, name string) string {
+	return (&url.URL{Scheme: SourceScheme, Host: revision, Path: "/" + name}).String()
+}
+
+// isSourceURL reports whether rawURL points into the sync source
+func isSourceURL(rawURL string) bool {
+	return strings.HasPrefix(rawURL, SourceScheme+"://")
+}
+
+// directorySource reads the working tree of a local checkout. Reads are
+// confined to the directory, symbolic links included.
+type directorySource struct {
+	root *os.Root
+}
+
+func (d *directorySource) Type() string {
+	return SourceTypeDirectory
+}
+
+func (d *directorySource) Revision(ctx context.Context) (string, error) {
+	return "", nil
+}
+
+func (d *directorySource) ListFiles(ctx context.Context, revision string) ([]string, error) {
+	var files []string
+	err := fs.WalkDir(d.root.FS(), ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// Skip .git and other hidden directories
+		if entry.IsDir() && name != "." && strings.HasPrefix(entry.Name(), ".") {
+			return fs.SkipDir
+		}
+		if entry.Type().IsRegular() {
+			files = append(files, name)
+		}
+		return ctx.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sync source files: %w", err)
+	}
+	return files, nil
+}
+
+func (d *directorySource) ReadFile(ctx context.Context, revision, name string) ([]byte, error) {
+	return fs.ReadFile(d.root.FS(), name)
+}
+
+// gitSource reads a git repository, usually a bare mirror, at a pinned commit
+// so retried jobs see the files their scan saw
+type gitSource struct {
+	gitDir string
+	ref    string
+}
+
+func (g *gitSource) Type() string {
+	return SourceTypeGit
+}
+
+func (g *gitSource) Revision(ctx context.Context) (string, error) {
+	out, err := g.git(ctx, "rev-parse", "--verify", "--end-of-options", g.ref+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve ref %s: %w", g.ref, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (g *gitSource) ListFiles(ctx context.Context, revision string) ([]string, error) {
+	out, err := g.git(ctx, "ls-tree", "-r", "-z", "--end-of-options", revision)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files at %s: %w", revision, err)
+	}
+
+	// Entries are "<mode> <type> <object>\t<path>"; only regular files are listed
+	var files []string
+	for _, entry := range strings.Split(string(out), "\x00") {
+		info, name, found := strings.Cut(entry, "\t")
+		if found && (strings.HasPrefix(info, "100644 ") || strings.HasPrefix(info, "100755 ")) {
+			files = append(files, name)
+		}
+	}
+	return files, nil
+}
+
+func (g *gitSource) ReadFile(ctx context.Context, revision, name string) ([]byte, error) {
+	if revision == "" || !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+
+	object := revision + ":" + name
+	out, err := g.git(ctx, "cat-file", "blob", object)
+	if err != nil {
+		// Tell missing files apart from git failures
+		if _, existsErr := g.git(ctx, "cat-file", "-e", object); existsErr != nil {
+			var exitErr *exec.ExitError
+			if errors.As(existsErr, &exitErr) {
+				return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+			}
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", object, err)
+	}
+	return out, nil
+}
+
+// git runs a git command against the repository and returns its output
+func (g *gitSource) git(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"--git-dir", g.gitDir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// sourceTransport serves source:// URLs from the sync source, answering 404
+// for missing files like an HTTP file server
+type sourceTransport struct {
+	source Source
+}
+
+func (t *sourceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return nil, fmt.Errorf("method %s not supported for sync source files", req.Method)
+	}
+
+	name := strings.TrimPrefix(req.URL.Path, "/")
+	data, err := t.source.ReadFile(req.Context(), req.URL.Host, name)
+	status := http.StatusOK
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrInvalid) {
+		status, data = http.StatusNotFound, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        make(http.Header),
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,347 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package sync
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	gosync "sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wso2/policyhub/internal/errs"
+)
+
+const (
+	// leaseGrace is how long a job's lease outlives its attempt timeout, so a
+	// slow worker finishes before another may claim the job
+	leaseGrace = time.Minute
+	// abandonedCheckInterval is how often jobs whose final attempt lost its lease are failed
+	abandonedCheckInterval = time.Minute
+	// updateTimeout bounds job state updates, which also run during shutdown
+	updateTimeout = 10 * time.Second
+	// cleanupInterval is how often orphaned bundles and stale cached doc pages are deleted
+	cleanupInterval = time.Hour
+)
+
+// WorkerPool processes queued sync jobs
+type WorkerPool struct {
+	service *Service
+	wg      gosync.WaitGroup
+}
+
+// NewWorkerPool creates a worker pool for the sync service's jobs
+func NewWorkerPool(service *Service) *WorkerPool {
+	return &WorkerPool{service: service}
+}
+
+// Start launches the configured number of workers. They stop when ctx is
+// cancelled; interrupted jobs are handed back to the queue.
+func (p *WorkerPool) Start(ctx context.Context) {
+	s := p.service
+	s.logger.Info("Starting sync workers",
+		zap.Int("workers", s.cfg.Workers),
+		zap.Int("max_attempts", s.cfg.MaxAttempts))
+
+	for i := 0; i < s.cfg.Workers; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.work(ctx)
+		}()
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.failAbandoned(ctx)
+	}()
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.cleanup(ctx)
+	}()
+
+	if s.source != nil && s.cfg.Source.ScanInterval > 0 {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.scanSource(ctx)
+		}()
+	}
+}
+
+// Wait blocks until all workers have stopped
+func (p *WorkerPool) Wait() {
+	p.wg.Wait()
+}
+
+// work claims and runs due jobs until ctx is cancelled
+func (p *WorkerPool) work(ctx context.Context) {
+	s := p.service
+	lease := s.cfg.JobTimeout + leaseGrace
+
+	for ctx.Err() == nil {
+		job, err := s.jobs.ClaimJob(ctx, lease)
+		if err != nil && ctx.Err() == nil {
+			s.logger.Error("Failed to claim sync job", zap.Error(err))
+		}
+		if job != nil {
+			p.run(ctx, job)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-s.submitted:
+		case <-time.After(s.cfg.PollInterval):
+		}
+	}
+}
+
+// run executes one attempt of a claimed job and records its outcome
+func (p *WorkerPool) run(ctx context.Context, job *Job) {
+	s := p.service
+	logger := s.logger.With(
+		zap.Int64("job_id", job.ID),
+		zap.String("policy", job.PolicyName),
+		zap.String("version", job.Version),
+		zap.Int("attempt", job.Attempts))
+
+	logger.Info("Sync job attempt started")
+
+	tracker := newStepTracker(s.jobs, job, logger)
+	attemptCtx, cancel := context.WithTimeout(ctx, s.cfg.JobTimeout)
+	result, err := s.SyncPolicy(attemptCtx, job.Request, tracker.report)
+	cancel()
+
+	updateCtx, cancelUpdate := context.WithTimeout(context.Background(), updateTimeout)
+	defer cancelUpdate()
+
+	switch {
+	case err == nil:
+		if err := s.jobs.CompleteJob(updateCtx, job.ID, job.Attempts, result); err != nil {
+			logger.Error("Failed to record sync job success", zap.Error(err))
+			return
+		}
+		logger.Info("Sync job succeeded")
+
+	case ctx.Err() != nil:
+		// Shutting down: the attempt did not fail on its own, so it is not counted
+		if err := s.jobs.ReleaseJob(updateCtx, job.ID, job.Attempts); err != nil {
+			logger.Error("Failed to release interrupted sync job", zap.Error(err))
+			return
+		}
+		logger.Info("Sync job interrupted by shutdown and requeued")
+
+	case isRetryable(err) && job.Attempts < job.MaxAttempts:
+		delay := p.backoff(job.Attempts)
+		if err := s.jobs.RetryJob(updateCtx, job.ID, job.Attempts, toJobError(err), delay); err != nil {
+			logger.Error("Failed to reschedule sync job", zap.Error(err))
+			return
+		}
+		logger.Warn("Sync job attempt failed, retrying", zap.Duration("retry_in", delay), zap.Error(err))
+
+	default:
+		if err := s.jobs.FailJob(updateCtx, job.ID, job.Attempts, toJobError(err)); err != nil {
+			logger.Error("Failed to record sync job failure", zap.Error(err))
+			return
+		}
+		logger.Warn("Sync job failed", zap.Error(err))
+	}
+}
+
+// backoff returns the delay before the attempt after the given one: the base
+// delay doubled per failed attempt, capped, with up to 10% jitter
+func (p *WorkerPool) backoff(attempt int) time.Duration {
+	cfg := p.service.cfg
+	delay := cfg.RetryBaseDelay
+	for i := 1; i < attempt && delay < cfg.RetryMaxDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, cfg.RetryMaxDelay)
+	return delay + rand.N(delay/10+1)
+}
+
+// failAbandoned periodically fails jobs whose final attempt lost its lease
+func (p *WorkerPool) failAbandoned(ctx context.Context) {
+	s := p.service
+	jobErr := &JobError{
+		Code:    string(errs.CodeInternalServerError),
+		Message: "Sync worker stopped during the final attempt",
+	}
+
+	ticker := time.NewTicker(abandonedCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		count, err := s.jobs.FailAbandonedJobs(ctx, jobErr)
+		if err != nil && ctx.Err() == nil {
+			s.logger.Error("Failed to fail abandoned sync jobs", zap.Error(err))
+		} else if count > 0 {
+			s.logger.Warn("Failed abandoned sync jobs", zap.Int64("count", count))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// cleanup periodically deletes finished jobs, uploaded bundles that no version
+// was published from and cached doc pages no sync has used, once past their
+// retention period. Bundles of unfinished jobs are never deleted, so deleting
+// finished jobs cannot orphan a bundle a job still reads.
+func (p *WorkerPool) cleanup(ctx context.Context) {
+	s := p.service
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		count, err := s.jobs.DeleteFinishedJobs(ctx, s.cfg.JobRetention)
+		if err != nil && ctx.Err() == nil {
+			s.logger.Error("Failed to delete finished sync jobs", zap.Error(err))
+		} else if count > 0 {
+			s.logger.Info("Deleted finished sync jobs", zap.Int64("count", count))
+		}
+
+		count, err = s.bundles.DeleteOrphanedBundles(ctx, s.cfg.Bundle.Retention)
+		if err != nil && ctx.Err() == nil {
+			s.logger.Error("Failed to delete orphaned policy bundles", zap.Error(err))
+		} else if count > 0 {
+			s.logger.Info("Deleted orphaned policy bundles", zap.Int64("count", count))
+		}
+
+		count, err = s.docCache.DeleteStaleDocs(ctx, s.cfg.Docs.CacheRetention)
+		if err != nil && ctx.Err() == nil {
+			s.logger.Error("Failed to delete stale cached doc pages", zap.Error(err))
+		} else if count > 0 {
+			s.logger.Info("Deleted stale cached doc pages", zap.Int64("count", count))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// scanSource scans the sync source on start and then on every scan interval
+// until ctx is cancelled
+func (p *WorkerPool) scanSource(ctx context.Context) {
+	s := p.service
+	ticker := time.NewTicker(s.cfg.Source.ScanInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.ScanSource(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Error("Scheduled sync source scan failed", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// isRetryable reports whether a failed attempt may succeed when run again.
+// Fetch, database and unexpected errors are transient; validation, signature,
+// conflict and fetch policy errors fail the same way every time.
+func isRetryable(err error) bool {
+	var appErr *errs.AppError
+	if !errors.As(err, &appErr) {
+		return true
+	}
+	return isRetryableCode(appErr.Code)
+}
+
+// isRetryableCode reports whether errors with the given code are transient
+func isRetryableCode(code errs.Code) bool {
+	switch code {
+	case errs.CodeSyncFetchFailed, errs.CodeDatabaseError, errs.CodeInternalServerError:
+		return true
+	default:
+		return false
+	}
+}
+
+// toJobError converts an attempt error into the error recorded on the job
+func toJobError(err error) *JobError {
+	var appErr *errs.AppError
+	if errors.As(err, &appErr) {
+		return &JobError{
+			Code:    string(appErr.Code),
+			Message: appErr.Message,
+			Details: appErr.Details,
+		}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &JobError{
+			Code:    string(errs.CodeInternalServerError),
+			Message: "Sync attempt timed out",
+		}
+	}
+	return &JobError{
+		Code:    string(errs.CodeInternalServerError),
+		Message: "Sync attempt failed",
+		Details: map[string]any{"error": err.Error()},
+	}
+}
+
+// stepTracker records the step progress of a job attempt
+type stepTracker struct {
+	jobs   JobRepository
+	job    *Job
+	steps  []JobStep
+	logger *zap.Logger
+}
+
+func newStepTracker(jobs JobRepository, job *Job, logger *zap.Logger) *stepTracker {
+	// Every attempt starts from a clean slate
+	steps := make([]JobStep, 0, len(Steps()))
+	for _, step := range Steps() {
+		steps = append(steps, JobStep{Name: step, Status: StepPending})
+	}
+	return &stepTracker{jobs: jobs, job: job, steps: steps, logger: logger}
+}
+
+// report is the ProgressFunc of the attempt; it persists every transition
+func (t *stepTracker) report(step string, status StepStatus, err error) {
+	now := time.Now()
+	for i := range t.steps {
+		if t.steps[i].Name != step {
+			continue
+		}
+		t.steps[i].Status = status
+		switch status {
+		case StepRunning:
+			t.steps[i].StartedAt = &now
+		case StepSucceeded, StepFailed, StepSkipped:
+			t.steps[i].FinishedAt = &now
+		}
+		if err != nil {
+			t.steps[i].Error = err.Error()
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), updateTimeout)
+	defer cancel()
+	if err := t.jobs.UpdateJobSteps(ctx, t.job.ID, t.job.Attempts, t.steps); err != nil {
+		t.logger.Warn("Failed to record sync job progress", zap.String("step", step), zap.Error(err))
+	}
+}
@@ -0,0 +1,148 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package validation
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/wso2/policyhub/internal/errs"
+)
+
+// CurrentDefinitionSchemaVersion is the schema version definitions that do not
+// declare a schemaVersion are validated against
+const CurrentDefinitionSchemaVersion = "v1"
+
+// maxViolations bounds the violations reported for one definition
+const maxViolations = 50
+
+//go:embed schemas/policy-definition-*.json
+var definitionSchemaFiles embed.FS
+
+// definitionSchema is a published version of the policy definition schema
+type definitionSchema struct {
+	raw    []byte
+	schema map[string]any
+}
+
+// definitionSchemas holds the published schema versions by version
+var definitionSchemas = loadDefinitionSchemas("v1")
+
+func loadDefinitionSchemas(versions ...string) map[string]*definitionSchema {
+	schemas := make(map[string]*definitionSchema, len(versions))
+	for _, version := range versions {
+		raw, err := definitionSchemaFiles.ReadFile("schemas/policy-definition-" + version + ".json")
+		if err != nil {
+			panic(fmt.Sprintf("policy definition schema %s is not embedded: %v", version, err))
+		}
+		var schema map[string]any
+		if err := json.Unmarshal(raw, &schema); err != nil {
+			panic(fmt.Sprintf("policy definition schema %s is invalid: %v", version, err))
+		}
+		schemas[version] = &definitionSchema{raw: raw, schema: schema}
+	}
+	return schemas
+}
+
+// DefinitionSchema returns a version of the policy definition schema as JSON
+func DefinitionSchema(version string) ([]byte, bool) {
+	schema, ok := definitionSchemas[version]
+	if !ok {
+		return nil, false
+	}
+	return schema.raw, true
+}
+
+// ValidateDefinition checks a policy definition against the schema version it
+// declares, or the current one, and checks that its name and version are the
+// ones it is published as. A "v" prefix on the definition's version is ignored.
+func ValidateDefinition(definition []byte, name, version string) *errs.AppError {
+	var doc any
+	if err := yaml.Unmarshal(definition, &doc); err != nil {
+		return errs.NewValidationError("invalid policy definition YAML", map[string]any{"error": err.Error()})
+	}
+	doc = normalizeYAML(doc)
+
+	schemaVersion := CurrentDefinitionSchemaVersion
+	object, _ := doc.(map[string]any)
+	if declared, ok := object["schemaVersion"].(string); ok {
+		if _, known := definitionSchemas[declared]; !known {
+			return errs.DefinitionInvalid(name, version, declared, []Violation{{
+				Path:    "/schemaVersion",
+				Message: fmt.Sprintf("unknown schema version %q", declared),
+			}})
+		}
+		schemaVersion = declared
+	}
+
+	violations := validateSchema(definitionSchemas[schemaVersion].schema, doc)
+	if len(violations) == 0 {
+		violations = append(violations, checkParameterSchema(object["parameters"], "/parameters")...)
+		violations = append(violations, checkParameterSchema(object["systemParameters"], "/systemParameters")...)
+		if object["name"] != name {
+			violations = append(violations, Violation{
+				Path:    "/name",
+				Message: fmt.Sprintf("must be %q, the policy name it is published under", name),
+			})
+		}
+		if strings.TrimPrefix(object["version"].(string), "v") != version {
+			violations = append(violations, Violation{
+				Path:    "/version",
+				Message: fmt.Sprintf("must be %q, the version it is published as", version),
+			})
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	if len(violations) > maxViolations {
+		violations = violations[:maxViolations]
+	}
+	return errs.DefinitionInvalid(name, version, schemaVersion, violations)
+}
+
+// checkParameterSchema checks what the definition schema cannot express: that
+// the properties an object parameter requires are ones it defines
+func checkParameterSchema(schema any, path string) []Violation {
+	object, ok := schema.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	var violations []Violation
+	properties, hasProperties := object["properties"].(map[string]any)
+	if required, ok := object["required"].([]any); ok && hasProperties {
+		for i, name := range required {
+			if _, defined := properties[name.(string)]; !defined {
+				violations = append(violations, Violation{
+					Path:    childPath(childPath(path, "required"), fmt.Sprint(i)),
+					Message: fmt.Sprintf("%q is not defined in properties", name),
+				})
+			}
+		}
+	}
+	for _, name := range sortedKeys(properties) {
+		violations = append(violations, checkParameterSchema(properties[name], childPath(childPath(path, "properties"), name))...)
+	}
+	for _, keyword := range []string{"items", "additionalProperties"} {
+		violations = append(violations, checkParameterSchema(object[keyword], childPath(path, keyword))...)
+	}
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		schemas, _ := object[keyword].([]any)
+		for i, sub := range schemas {
+			violations = append(violations, checkParameterSchema(sub, childPath(childPath(path, keyword), fmt.Sprint(i)))...)
+		}
+	}
+	return violations
+}
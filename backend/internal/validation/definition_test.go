@@ -0,0 +1,312 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wso2/policyhub/internal/errs"
+)
+
+func TestValidateDefinition(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition string
+		// version is the version the definition is published as, 1.2.0 when empty
+		version string
+		// paths are the JSON Pointers of the expected violations, in order;
+		// nil expects the definition to be valid
+		paths []string
+		// message, when set, must appear in the first violation
+		message string
+	}{
+		{
+			name: "valid",
+			definition: `
+name: rate-limit
+version: 1.2.0
+displayName: Rate Limit
+parameters:
+  type: object
+  required: [limit]
+  properties:
+    limit:
+      type: integer
+      minimum: 1
+    header:
+      type: string
+      pattern: "^X-[A-Za-z-]+$"
+`,
+		},
+		{
+			name:       "v prefixed version",
+			definition: "name: rate-limit\nversion: v1.2.0\n",
+		},
+		{
+			name:       "pre-release version",
+			definition: "schemaVersion: v1\nname: rate-limit\nversion: 1.2.0-rc.1+build.5\n",
+			version:    "1.2.0-rc.1+build.5",
+		},
+		{
+			name:       "missing name",
+			definition: "version: 1.2.0\n",
+			paths:      []string{"/name"},
+			message:    "is required",
+		},
+		{
+			name:       "missing version",
+			definition: "name: rate-limit\n",
+			paths:      []string{"/version"},
+			message:    "is required",
+		},
+		{
+			name:       "missing name and version",
+			definition: "displayName: Rate Limit\n",
+			paths:      []string{"/name", "/version"},
+		},
+		{
+			name:       "not an object",
+			definition: "- rate-limit\n",
+			paths:      []string{""},
+			message:    "must be an object",
+		},
+		{
+			name:       "wrong name type",
+			definition: "name: 42\nversion: 1.2.0\n",
+			paths:      []string{"/name"},
+			message:    "must be a string",
+		},
+		{
+			name:       "wrong version type",
+			definition: "name: rate-limit\nversion: 1.2\n",
+			paths:      []string{"/version"},
+			message:    "must be a string",
+		},
+		{
+			name:       "malformed version",
+			definition: "name: rate-limit\nversion: 1.2.x\n",
+			paths:      []string{"/version"},
+			message:    "must match the pattern",
+		},
+		{
+			name:       "wrong parameters type",
+			definition: "name: rate-limit\nversion: 1.2.0\nparameters: limit\n",
+			paths:      []string{"/parameters"},
+			message:    "must be an object",
+		},
+		{
+			name: "wrong nested keyword type",
+			definition: `
+name: rate-limit
+version: 1.2.0
+parameters:
+  type: object
+  properties:
+    limit:
+      type: integer
+      minimum: ten
+`,
+			paths:   []string{"/parameters/properties/limit/minimum"},
+			message: "must be a number",
+		},
+		{
+			name: "unknown parameter type",
+			definition: `
+name: rate-limit
+version: 1.2.0
+parameters:
+  type: float
+`,
+			paths:   []string{"/parameters/type"},
+			message: "must be one of",
+		},
+		{
+			name: "negative count",
+			definition: `
+name: rate-limit
+version: 1.2.0
+systemParameters:
+  type: string
+  maxLength: -1
+`,
+			paths:   []string{"/systemParameters/maxLength"},
+			message: "must be at least 0",
+		},
+		{
+			name: "invalid parameter pattern",
+			definition: `
+name: rate-limit
+version: 1.2.0
+parameters:
+  type: object
+  properties:
+    header:
+      type: string
+      pattern: "[A-Z"
+`,
+			paths:   []string{"/parameters/properties/header/pattern"},
+			message: "must be a valid regular expression",
+		},
+		{
+			name: "duplicate required properties",
+			definition: `
+name: rate-limit
+version: 1.2.0
+parameters:
+  type: object
+  required: [limit, limit]
+  properties:
+    limit:
+      type: integer
+`,
+			paths:   []string{"/parameters/required/1"},
+			message: "duplicates item 0",
+		},
+		{
+			name: "empty enum",
+			definition: `
+name: rate-limit
+version: 1.2.0
+parameters:
+  type: string
+  enum: []
+`,
+			paths:   []string{"/parameters/enum"},
+			message: "must have at least 1 items",
+		},
+		{
+			name: "required property not defined",
+			definition: `
+name: rate-limit
+version: 1.2.0
+parameters:
+  type: object
+  required: [limit, window]
+  properties:
+    limit:
+      type: integer
+`,
+			paths:   []string{"/parameters/required/1"},
+			message: `"window" is not defined in properties`,
+		},
+		{
+			name: "nested required property not defined",
+			definition: `
+name: rate-limit
+version: 1.2.0
+systemParameters:
+  type: array
+  items:
+    type: object
+    required: [key]
+    properties:
+      value:
+        type: string
+`,
+			paths: []string{"/systemParameters/items/required/0"},
+		},
+		{
+			name:       "name mismatch",
+			definition: "name: other-policy\nversion: 1.2.0\n",
+			paths:      []string{"/name"},
+			message:    `must be "rate-limit"`,
+		},
+		{
+			name:       "version mismatch",
+			definition: "name: rate-limit\nversion: v1.3.0\n",
+			paths:      []string{"/version"},
+			message:    `must be "1.2.0"`,
+		},
+		{
+			name:       "name and version mismatch",
+			definition: "name: other-policy\nversion: 1.3.0\n",
+			paths:      []string{"/name", "/version"},
+		},
+		{
+			name:       "unknown schema version",
+			definition: "schemaVersion: v9\nname: rate-limit\nversion: 1.2.0\n",
+			paths:      []string{"/schemaVersion"},
+			message:    `unknown schema version "v9"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version := tt.version
+			if version == "" {
+				version = "1.2.0"
+			}
+
+			err := ValidateDefinition([]byte(tt.definition), "rate-limit", version)
+			if tt.paths == nil {
+				if err != nil {
+					t.Fatalf("ValidateDefinition() = %v, want no error (details %v)", err, err.Details)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("ValidateDefinition() = nil, want violations at %v", tt.paths)
+			}
+			if err.Code != errs.CodeDefinitionInvalid {
+				t.Fatalf("error code = %s, want %s", err.Code, errs.CodeDefinitionInvalid)
+			}
+
+			violations, ok := err.Details["violations"].([]Violation)
+			if !ok {
+				t.Fatalf("violations detail is %T, want []Violation", err.Details["violations"])
+			}
+			paths := make([]string, len(violations))
+			for i, violation := range violations {
+				paths[i] = violation.Path
+			}
+			if !reflect.DeepEqual(paths, tt.paths) {
+				t.Errorf("violation paths = %q, want %q (violations %v)", paths, tt.paths, violations)
+			}
+			if tt.message != "" && !strings.Contains(violations[0].Message, tt.message) {
+				t.Errorf("violation message = %q, want it to contain %q", violations[0].Message, tt.message)
+			}
+		})
+	}
+}
+
+func TestValidateDefinitionInvalidYAML(t *testing.T) {
+	err := ValidateDefinition([]byte("name: [rate-limit\n"), "rate-limit", "1.2.0")
+	if err == nil || err.Code != errs.CodeValidationError {
+		t.Fatalf("ValidateDefinition() = %v, want %s", err, errs.CodeValidationError)
+	}
+}
+
+func TestValidateDefinitionSchemaVersionDetail(t *testing.T) {
+	err := ValidateDefinition([]byte("version: 1.2.0\n"), "rate-limit", "1.2.0")
+	if err == nil {
+		t.Fatal("ValidateDefinition() = nil, want a violation")
+	}
+	if got := err.Details["schemaVersion"]; got != CurrentDefinitionSchemaVersion {
+		t.Errorf("schemaVersion detail = %v, want %s", got, CurrentDefinitionSchemaVersion)
+	}
+}
+
+func TestValidateDefinitionLimitsViolations(t *testing.T) {
+	var definition strings.Builder
+	definition.WriteString("name: rate-limit\nversion: 1.2.0\nparameters:\n  type: object\n  properties:\n")
+	for i := 0; i < maxViolations+10; i++ {
+		definition.WriteString("    p" + strings.Repeat("x", i) + ":\n      minLength: -1\n")
+	}
+
+	err := ValidateDefinition([]byte(definition.String()), "rate-limit", "1.2.0")
+	if err == nil {
+		t.Fatal("ValidateDefinition() = nil, want violations")
+	}
+	if violations := err.Details["violations"].([]Violation); len(violations) != maxViolations {
+		t.Errorf("got %d violations, want them capped at %d", len(violations), maxViolations)
+	}
+}
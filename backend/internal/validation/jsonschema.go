@@ -0,0 +1,343 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com). All Rights Reserved.
+ *
+ * This software is the property of WSO2 LLC. and its suppliers, if any.
+ * Dissemination of any information or reproduction of any material contained
+ * herein in any form is strictly forbidden, unless permitted by WSO2 expressly.
+ * You may not alter or remove any copyright or other notice from copies of this content.
+ */
+
+package validation
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Violation is a value that does not conform to a schema. Path is a JSON
+// Pointer to the value, "" for the document itself.
+type Violation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+	// wrongType marks a value of a type the schema does not allow
+	wrongType bool
+}
+
+func (v Violation) String() string {
+	if v.Path == "" {
+		return v.Message
+	}
+	return v.Path + ": " + v.Message
+}
+
+// schemaValidator checks documents against a JSON Schema. It implements the
+// draft 2020-12 keywords the hub's own schemas use: $ref to local $defs, type,
+// enum, required, properties, additionalProperties, items, minItems,
+// uniqueItems, minLength, maxLength, pattern, format "regex", minimum,
+// exclusiveMinimum and anyOf. Other keywords are ignored.
+type schemaValidator struct {
+	root       map[string]any
+	violations []Violation
+}
+
+// validateSchema checks doc, decoded from JSON or normalized with
+// normalizeYAML, against schema and returns its violations ordered by path
+func validateSchema(schema map[string]any, doc any) []Violation {
+	v := &schemaValidator{root: schema}
+	v.validate(schema, doc, "")
+	sort.SliceStable(v.violations, func(i, j int) bool {
+		return v.violations[i].Path < v.violations[j].Path
+	})
+	return v.violations
+}
+
+func (v *schemaValidator) fail(path, format string, args ...any) {
+	v.violations = append(v.violations, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *schemaValidator) validate(schema any, value any, path string) {
+	switch s := schema.(type) {
+	case bool:
+		if !s {
+			v.fail(path, "is not allowed")
+		}
+		return
+	case map[string]any:
+		v.validateObject(s, value, path)
+	}
+}
+
+func (v *schemaValidator) validateObject(schema map[string]any, value any, path string) {
+	if ref, ok := schema["$ref"].(string); ok {
+		v.validate(v.resolve(ref), value, path)
+	}
+
+	if types, ok := schema["type"]; ok && !matchesType(types, value) {
+		v.violations = append(v.violations, Violation{Path: path, Message: "must be " + describeType(types), wrongType: true})
+		return // the remaining keywords would only repeat the mismatch
+	}
+	if enum, ok := schema["enum"].([]any); ok && !containsValue(enum, value) {
+		v.fail(path, "must be one of %s", describeValues(enum))
+	}
+	if alternatives, ok := schema["anyOf"].([]any); ok {
+		v.validateAnyOf(alternatives, value, path)
+	}
+
+	switch val := value.(type) {
+	case map[string]any:
+		v.validateProperties(schema, val, path)
+	case []any:
+		v.validateItems(schema, val, path)
+	case string:
+		v.validateString(schema, val, path)
+	case float64:
+		if min, ok := schema["minimum"].(float64); ok && val < min {
+			v.fail(path, "must be at least %v", min)
+		}
+		if min, ok := schema["exclusiveMinimum"].(float64); ok && val <= min {
+			v.fail(path, "must be greater than %v", min)
+		}
+	}
+}
+
+// validateAnyOf requires value to match one of the alternatives. When none
+// does, the violations of the closest alternative that allows the value's type
+// are reported, or the allowed types when none allows it.
+func (v *schemaValidator) validateAnyOf(alternatives []any, value any, path string) {
+	var closest []Violation
+	var types []string
+	for _, alternative := range alternatives {
+		sub := &schemaValidator{root: v.root}
+		sub.validate(alternative, value, path)
+		if len(sub.violations) == 0 {
+			return
+		}
+		if first := sub.violations[0]; len(sub.violations) == 1 && first.Path == path && first.wrongType {
+			types = append(types, strings.TrimPrefix(first.Message, "must be "))
+			continue
+		}
+		if closest == nil || len(sub.violations) < len(closest) {
+			closest = sub.violations
+		}
+	}
+	if closest != nil {
+		v.violations = append(v.violations, closest...)
+		return
+	}
+	v.violations = append(v.violations, Violation{Path: path, Message: "must be " + strings.Join(types, " or "), wrongType: true})
+}
+
+func (v *schemaValidator) validateProperties(schema map[string]any, object map[string]any, path string) {
+	if required, ok := schema["required"].([]any); ok {
+		for _, name := range required {
+			if name, ok := name.(string); ok {
+				if _, present := object[name]; !present {
+					v.fail(childPath(path, name), "is required")
+				}
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	additional, hasAdditional := schema["additionalProperties"]
+	for _, name := range sortedKeys(object) {
+		if propertySchema, ok := properties[name]; ok {
+			v.validate(propertySchema, object[name], childPath(path, name))
+		} else if hasAdditional {
+			v.validate(additional, object[name], childPath(path, name))
+		}
+	}
+}
+
+func (v *schemaValidator) validateItems(schema map[string]any, array []any, path string) {
+	if min, ok := schema["minItems"].(float64); ok && float64(len(array)) < min {
+		v.fail(path, "must have at least %v items", min)
+	}
+	if unique, _ := schema["uniqueItems"].(bool); unique {
+		for i := range array {
+			for j := 0; j < i; j++ {
+				if reflect.DeepEqual(array[i], array[j]) {
+					v.fail(childPath(path, fmt.Sprint(i)), "duplicates item %d", j)
+					break
+				}
+			}
+		}
+	}
+	if items, ok := schema["items"]; ok {
+		for i, item := range array {
+			v.validate(items, item, childPath(path, fmt.Sprint(i)))
+		}
+	}
+}
+
+func (v *schemaValidator) validateString(schema map[string]any, s string, path string) {
+	length := float64(utf8.RuneCountInString(s))
+	if min, ok := schema["minLength"].(float64); ok && length < min {
+		v.fail(path, "must be at least %v characters", min)
+	}
+	if max, ok := schema["maxLength"].(float64); ok && length > max {
+		v.fail(path, "must be at most %v characters", max)
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(s) {
+			v.fail(path, "must match the pattern %s", pattern)
+		}
+	}
+	if format, _ := schema["format"].(string); format == "regex" {
+		if _, err := regexp.Compile(s); err != nil {
+			v.fail(path, "must be a valid regular expression: %s", strings.TrimPrefix(err.Error(), "error parsing regexp: "))
+		}
+	}
+}
+
+// resolve looks up a local "#/$defs/name" reference; unknown references
+// resolve to a schema that allows anything
+func (v *schemaValidator) resolve(ref string) any {
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok {
+		return true
+	}
+	defs, _ := v.root["$defs"].(map[string]any)
+	if schema, ok := defs[name]; ok {
+		return schema
+	}
+	return true
+}
+
+// matchesType reports whether value has the JSON type, or one of the types, named by types
+func matchesType(types any, value any) bool {
+	switch t := types.(type) {
+	case string:
+		return typeOf(value) == t || (t == "number" && typeOf(value) == "integer")
+	case []any:
+		for _, name := range t {
+			if matchesType(name, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// typeOf returns the JSON type of a decoded value; whole numbers are integers
+func typeOf(value any) string {
+	switch val := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if val == math.Trunc(val) && !math.IsInf(val, 0) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return "unknown"
+}
+
+// describeType names the type or types of a type keyword, with an article
+func describeType(types any) string {
+	var names []string
+	switch t := types.(type) {
+	case string:
+		names = []string{t}
+	case []any:
+		for _, name := range t {
+			names = append(names, fmt.Sprint(name))
+		}
+	}
+	for i, name := range names {
+		article := "a"
+		if name == "array" || name == "object" || name == "integer" {
+			article = "an"
+		}
+		if name == "null" {
+			names[i] = "null"
+		} else {
+			names[i] = article + " " + name
+		}
+	}
+	return strings.Join(names, " or ")
+}
+
+// describeValues lists enum values as JSON-like literals
+func describeValues(values []any) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			parts[i] = fmt.Sprintf("%q", s)
+		} else {
+			parts[i] = fmt.Sprint(value)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func containsValue(values []any, value any) bool {
+	for _, candidate := range values {
+		if reflect.DeepEqual(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// childPath appends a JSON Pointer reference token to path
+func childPath(path, token string) string {
+	token = strings.ReplaceAll(token, "~", "~0")
+	token = strings.ReplaceAll(token, "/", "~1")
+	return path + "/" + token
+}
+
+func sortedKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// normalizeYAML converts a document decoded by yaml.v3 into the types JSON
+// decoding produces: string-keyed maps, float64 numbers and string timestamps
+func normalizeYAML(value any) any {
+	switch val := value.(type) {
+	case map[string]any:
+		for key, item := range val {
+			val[key] = normalizeYAML(item)
+		}
+		return val
+	case map[any]any:
+		object := make(map[string]any, len(val))
+		for key, item := range val {
+			object[fmt.Sprint(key)] = normalizeYAML(item)
+		}
+		return object
+	case []any:
+		for i, item := range val {
+			val[i] = normalizeYAML(item)
+		}
+		return val
+	case int:
+		return float64(val)
+	case int64:
+		return float64(val)
+	case uint64:
+		return float64(val)
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	}
+	return value
+}
@@ -59,7 +59,7 @@ func ValidateVersion(version string) *errs.AppError {
 	matched, _ := regexp.MatchString(policy.VersionRegex, version)
 	if !matched {
 		return errs.NewValidationError(
-			"version must follow semantic versioning format (e.g., v1.2.3)",
+			"version must follow semantic versioning format (e.g., 1.2.3, 2.0.0-beta.1, 1.0.0+build.5)",
 			map[string]any{"pattern": policy.VersionRegex},
 		)
 	}
@@ -16,6 +16,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -24,8 +25,11 @@ import (
 	"github.com/wso2/policyhub/internal/config"
 	"github.com/wso2/policyhub/internal/db"
 	httpPkg "github.com/wso2/policyhub/internal/http"
+	"github.com/wso2/policyhub/internal/http/middleware"
 	"github.com/wso2/policyhub/internal/logging"
 	"github.com/wso2/policyhub/internal/policy"
+	"github.com/wso2/policyhub/internal/signing"
+	"github.com/wso2/policyhub/internal/storage"
 	"github.com/wso2/policyhub/internal/sync"
 )
 
@@ -55,9 +59,23 @@ func main() {
 	}
 	defer database.Close()
 
-	// Create database schema
-	if err := db.CreateSchema(database.Pool, logger.Logger); err != nil {
-		logger.Fatal("Failed to create database schema", zap.Error(err))
+	// Run "policyhub migrate up|down|status" as a one-off command
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(database, logger, os.Args[2:]); err != nil {
+			logger.Fatal("Migration command failed", zap.Error(err))
+		}
+		return
+	}
+
+	// Apply pending schema migrations
+	if cfg.Database.AutoMigrate {
+		migrator, err := db.NewMigrator(database.Pool, logger)
+		if err != nil {
+			logger.Fatal("Failed to load database migrations", zap.Error(err))
+		}
+		if err := migrator.Up(context.Background()); err != nil {
+			logger.Fatal("Failed to migrate database schema", zap.Error(err))
+		}
 	}
 
 	// Initialize repository
@@ -65,10 +83,39 @@ func main() {
 
 	// Initialize services
 	policyService := policy.NewService(policyRepo, logger)
-	syncService := sync.NewService(policyService, logger)
+	// Initialize release signature verification
+	verifier, err := signing.NewVerifier(cfg.Signing.KeysFile, signing.UnsignedPolicy(cfg.Signing.UnsignedPolicy))
+	if err != nil {
+		logger.Fatal("Failed to load signing keys", zap.Error(err))
+	}
+	// Open the policy repository scanned for pull-based sync, if any
+	syncSource, err := sync.NewSource(&cfg.Sync.Source)
+	if err != nil {
+		logger.Fatal("Failed to open sync source", zap.Error(err))
+	}
+	// Open the blob store synced artifacts and images are mirrored into, if any
+	store, err := storage.New(&cfg.Storage)
+	if err != nil {
+		logger.Fatal("Failed to open blob store", zap.Error(err))
+	}
+	syncJobRepo := sync.NewSQLCJobRepository(database)
+	bundleRepo := sync.NewSQLCBundleRepository(database)
+	docCacheRepo := sync.NewSQLCDocCacheRepository(database)
+	syncService := sync.NewService(policyService, syncJobRepo, bundleRepo, docCacheRepo, verifier, syncSource, store, cfg.Server.PublicURL, &cfg.Storage, &cfg.Sync, logger)
+
+	// Start the workers that process queued sync jobs
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	syncWorkers := sync.NewWorkerPool(syncService)
+	syncWorkers.Start(workerCtx)
+
+	// Initialize internal API authentication
+	authMW, err := middleware.NewAuthMiddleware(&cfg.Auth, logger)
+	if err != nil {
+		logger.Fatal("Failed to initialize authentication", zap.Error(err))
+	}
 
 	// Setup HTTP router
-	router := httpPkg.SetupRouter(cfg, policyService, syncService, logger)
+	router := httpPkg.SetupRouter(cfg, policyService, syncService, store, authMW, logger)
 
 	// Create HTTP server
 	addr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
@@ -105,5 +152,55 @@ func main() {
 		logger.Fatal("Server forced to shutdown", zap.Error(err))
 	}
 
+	// Stop the sync workers; in-flight jobs are requeued for the next start
+	stopWorkers()
+	syncWorkers.Wait()
+
 	logger.Info("Server exited gracefully")
 }
+
+// runMigrate executes the migrate subcommand: up, down [steps] or status
+func runMigrate(database *db.DB, logger *logging.Logger, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: policyhub migrate up|down [steps]|status")
+	}
+
+	migrator, err := db.NewMigrator(database.Pool, logger)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		return migrator.Up(ctx)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid steps %q: %w", args[1], err)
+			}
+		}
+		return migrator.Down(ctx, steps)
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%06d  %-40s  %s\n", status.Version, status.Name, appliedAt)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown migrate command %q (must be up, down or status)", args[0])
+	}
+}
@@ -810,6 +810,10 @@ enforcement:
   type: "request"
   stage: "pre"', 'github', 'https://github.com/wso2/policy-hub/tree/main/storage/api-key-auth/2.0.0');
 
+-- Compute definition digests (the sync pipeline does this for published versions)
+UPDATE policy_version
+SET definition_digest = 'sha256:' || encode(sha256(convert_to(definition_yaml, 'UTF8')), 'hex');
+
 -- Insert policy documentation
 INSERT INTO policy_docs (policy_version_id, page, content_md) VALUES
 -- Rate Limiting 1.0.0 docs